@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+// reloadDebounce is how long file changes are collected before signing keys are reloaded, so a key
+// and config file replaced together cause a single reload.
+const reloadDebounce = time.Second
+
+// keyReloader reloads the private keys of an OAuth2Config from the config file.
+type keyReloader struct {
+	oauth2Config *fositex.OAuth2Config
+	configFile   string
+	watcher      *fsnotify.Watcher
+	files        map[string]struct{}
+	dirs         map[string]struct{}
+}
+
+// watchSigningKeys reloads the OAuth2Config's private keys when the config file or a key file changes,
+// or when the process receives SIGHUP. Keys that fail to load are logged and the current keys are kept.
+func watchSigningKeys(ctx context.Context, oauth2Config *fositex.OAuth2Config, keys []fositex.PrivateKey) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	r := &keyReloader{
+		oauth2Config: oauth2Config,
+		configFile:   viper.ConfigFileUsed(),
+		watcher:      watcher,
+		files:        map[string]struct{}{},
+		dirs:         map[string]struct{}{},
+	}
+
+	r.watch(keys)
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go r.run(ctx, sighup)
+
+	return nil
+}
+
+// watch adds the config file and the given keys' files to the watched files. Directories are watched
+// rather than files, as files replaced by renaming, such as mounted Kubernetes secrets, stop emitting
+// events for the original path.
+func (r *keyReloader) watch(keys []fositex.PrivateKey) {
+	paths := make([]string, 0, len(keys)+1)
+
+	if r.configFile != "" {
+		paths = append(paths, r.configFile)
+	}
+
+	for _, key := range keys {
+		paths = append(paths, key.Path)
+	}
+
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+
+		r.files[path] = struct{}{}
+
+		dir := filepath.Dir(path)
+		if _, ok := r.dirs[dir]; ok {
+			continue
+		}
+
+		if err := r.watcher.Add(dir); err != nil {
+			logger.Warnw("unable to watch directory for signing key changes", "dir", dir, "error", err)
+
+			continue
+		}
+
+		r.dirs[dir] = struct{}{}
+	}
+}
+
+// relevant reports whether a file event may change the config or a key file.
+func (r *keyReloader) relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+
+	// Kubernetes updates mounted secrets and config maps by swapping a "..data" symlink.
+	if strings.HasPrefix(filepath.Base(event.Name), "..") {
+		return true
+	}
+
+	path, err := filepath.Abs(event.Name)
+	if err != nil {
+		return false
+	}
+
+	_, ok := r.files[path]
+
+	return ok
+}
+
+func (r *keyReloader) run(ctx context.Context, sighup chan os.Signal) {
+	defer signal.Stop(sighup)
+	defer r.watcher.Close()
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			logger.Infow("received SIGHUP, reloading signing keys")
+
+			r.reload()
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if r.relevant(event) {
+				debounce.Reset(reloadDebounce)
+			}
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			logger.Warnw("error watching signing key files", "error", err)
+		case <-debounce.C:
+			logger.Infow("signing key files changed, reloading signing keys")
+
+			r.reload()
+		}
+	}
+}
+
+// reload reads the config file and replaces the signing keys with its private keys. The config is read
+// into a new viper instance, as the global instance is not safe for use from the watcher goroutine.
+func (r *keyReloader) reload() {
+	v := viper.New()
+	v.SetConfigFile(r.configFile)
+	bindEnv(v)
+
+	if err := v.ReadInConfig(); err != nil {
+		logger.Errorw("unable to read config file, keeping current signing keys", "error", err)
+
+		return
+	}
+
+	var cfg struct {
+		OAuth fositex.Config
+	}
+
+	if err := unmarshalConfig(v, &cfg); err != nil {
+		logger.Errorw("unable to decode config file, keeping current signing keys", "error", err)
+
+		return
+	}
+
+	if err := r.oauth2Config.ReloadPrivateKeys(cfg.OAuth.PrivateKeys); err != nil {
+		logger.Errorw("unable to load signing keys, keeping current signing keys", "error", err)
+
+		return
+	}
+
+	// Watch the files of keys added to the config.
+	r.watch(cfg.OAuth.PrivateKeys)
+
+	logger.Infow("reloaded signing keys", "keys", len(cfg.OAuth.PrivateKeys))
+}
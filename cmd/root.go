@@ -4,7 +4,9 @@ package cmd
 import (
 	"os"
 	"strings"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.infratographer.com/x/loggingx"
@@ -39,10 +41,7 @@ func initConfig() {
 		viper.SetConfigName("identity-api")
 	}
 
-	// Allow populating configuration from environment
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.SetEnvPrefix("idapi")
-	viper.AutomaticEnv() // read in environment variables that match
+	bindEnv(viper.GetViper())
 
 	err := viper.ReadInConfig()
 
@@ -54,12 +53,30 @@ func initConfig() {
 		)
 	}
 
-	err = viper.Unmarshal(&config.Config)
+	err = unmarshalConfig(viper.GetViper(), &config.Config)
 	if err != nil {
 		logger.Fatalw("unable to decode app config", "error", err)
 	}
 }
 
+// bindEnv allows populating configuration read by v from the environment.
+func bindEnv(v *viper.Viper) {
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetEnvPrefix("idapi")
+	v.AutomaticEnv() // read in environment variables that match
+}
+
+// unmarshalConfig decodes the configuration read by v into out.
+func unmarshalConfig(v *viper.Viper, out any) error {
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	)
+
+	return v.Unmarshal(out, viper.DecodeHook(decodeHook))
+}
+
 // Execute executes the root command.
 func Execute() {
 	err := rootCmd.Execute()
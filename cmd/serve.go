@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ory/fosite/compose"
 	fositestorage "github.com/ory/fosite/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.infratographer.com/x/crdbx"
@@ -15,13 +17,18 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"go.infratographer.com/identity-api/internal/api/httpsrv"
+	"go.infratographer.com/identity-api/internal/authz"
+	"go.infratographer.com/identity-api/internal/celutils"
 	"go.infratographer.com/identity-api/internal/config"
+	"go.infratographer.com/identity-api/internal/discovery"
 	"go.infratographer.com/identity-api/internal/fositex"
 	"go.infratographer.com/identity-api/internal/jwks"
+	"go.infratographer.com/identity-api/internal/keymanager"
 	"go.infratographer.com/identity-api/internal/rfc8693"
 	"go.infratographer.com/identity-api/internal/routes"
 	"go.infratographer.com/identity-api/internal/storage"
 	"go.infratographer.com/identity-api/internal/userinfo"
+	"go.infratographer.com/identity-api/internal/vault"
 )
 
 var serveCmd = &cobra.Command{
@@ -53,6 +60,8 @@ func serve(ctx context.Context) {
 		logger.Fatalf("error initializing tracing: %s", err)
 	}
 
+	celutils.Configure(config.Config.CEL)
+
 	storageEngine, err := storage.NewEngine(config.Config.Storage)
 	if err != nil {
 		logger.Fatalf("error initializing storage: %s", err)
@@ -60,9 +69,14 @@ func serve(ctx context.Context) {
 
 	defer storageEngine.Shutdown()
 
-	mappingStrategy := rfc8693.NewClaimMappingStrategy(storageEngine)
+	mappingStrategy := rfc8693.NewClaimMappingStrategy(storageEngine, storageEngine)
 
-	jwksStrategy := jwks.NewIssuerJWKSURIStrategy(storageEngine)
+	jwksStrategy := jwks.NewIssuerJWKSURIStrategy(storageEngine, discovery.DefaultClient)
+
+	subjectStrategy, err := rfc8693.NewSubjectStrategy(config.Config.OAuth.Subject, storageEngine, storageEngine)
+	if err != nil {
+		logger.Fatalf("error initializing subject strategy: %s", err)
+	}
 
 	oauth2Config, err := fositex.NewOAuth2Config(config.Config.OAuth)
 	if err != nil {
@@ -72,13 +86,63 @@ func serve(ctx context.Context) {
 	oauth2Config.IssuerJWKSURIStrategy = jwksStrategy
 	oauth2Config.ClaimMappingStrategy = mappingStrategy
 	oauth2Config.UserInfoStrategy = storageEngine
+	oauth2Config.SubjectStrategy = subjectStrategy
+
+	jwksFetcher := fositex.NewJWKSFetcher(config.Config.OAuth.JWKS, http.DefaultClient)
+	jwksFetcher.StartBackgroundRefresh(ctx)
+
+	prometheus.MustRegister(fositex.NewJWKSFetcherCollector(jwksFetcher))
+
+	oauth2Config.JWKSFetcherStrategy = jwksFetcher
+
+	if maxStaleness := config.Config.OAuth.JWKSSnapshotMaxStaleness; maxStaleness > 0 {
+		snapshotStrategy := jwks.NewSnapshotStrategy(storageEngine, storageEngine, maxStaleness, logger)
+
+		prometheus.MustRegister(jwks.NewSnapshotCollector(snapshotStrategy))
 
-	keyGetter := func(ctx context.Context) (any, error) {
-		return oauth2Config.GetSigningKey(ctx), nil
+		oauth2Config.IssuerJWKSSnapshotStrategy = snapshotStrategy
+	}
+
+	if recipients := config.Config.OAuth.TokenEncryption.Recipients; len(recipients) > 0 {
+		encryptionStrategy, err := rfc8693.NewTokenEncryptionStrategy(config.Config.OAuth.TokenEncryption)
+		if err != nil {
+			logger.Fatalf("error initializing token encryption strategy: %s", err)
+		}
+
+		oauth2Config.TokenEncryptionStrategy = encryptionStrategy
+	}
+
+	switch oauthConfig := config.Config.OAuth; {
+	case oauthConfig.VaultTransit.Enabled():
+		signer, err := vault.NewTransitSigner(ctx, oauthConfig.VaultTransit, http.DefaultClient)
+		if err != nil {
+			logger.Fatalf("error initializing vault transit signer: %s", err)
+		}
+
+		signer.StartBackgroundRefresh(ctx)
+
+		oauth2Config.Signer = signer
+	case oauthConfig.ManagedKeys.Enabled:
+		manager, err := keymanager.NewManager(oauthConfig.ManagedKeys, storageEngine, oauth2Config, logger)
+		if err != nil {
+			logger.Fatalf("error initializing signing key manager: %s", err)
+		}
+
+		if err := manager.Init(ctx); err != nil {
+			logger.Fatalf("error loading managed signing keys: %s", err)
+		}
+
+		manager.StartBackgroundRefresh(ctx)
+
+		oauth2Config.SigningKeyManager = manager
+	default:
+		if err := watchSigningKeys(ctx, oauth2Config, oauthConfig.PrivateKeys); err != nil {
+			logger.Fatalf("error watching signing keys: %s", err)
+		}
 	}
 
 	hmacStrategy := compose.NewOAuth2HMACStrategy(oauth2Config)
-	jwtStrategy := compose.NewOAuth2JWTStrategy(keyGetter, hmacStrategy, oauth2Config)
+	jwtStrategy := compose.NewOAuth2JWTStrategy(oauth2Config.GetPrivateKey, hmacStrategy, oauth2Config)
 	store := fositestorage.NewExampleStore()
 
 	provider := fositex.NewOAuth2Provider(
@@ -88,7 +152,12 @@ func serve(ctx context.Context) {
 		rfc8693.NewTokenExchangeHandler,
 	)
 
-	apiHandler, err := httpsrv.NewAPIHandler(storageEngine)
+	apiAuthConfig := config.Config.APIAuth
+	if apiAuthConfig.Disabled {
+		logger.Warn("management API authentication is disabled")
+	}
+
+	apiHandler, err := httpsrv.NewAPIHandler(storageEngine, oauth2Config, apiAuthConfig, authz.NewAuthorizer(apiAuthConfig))
 	if err != nil {
 		logger.Fatal("error initializing API server: %s", err)
 	}
@@ -108,6 +177,8 @@ func serve(ctx context.Context) {
 	engine := ginx.DefaultEngine(logger.Desugar(), emptyLogFn)
 	engine.ContextWithFallback = true
 
+	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	router.Routes(engine.Group("/"))
 	apiHandler.Routes(engine.Group("/"))
 	userInfoHandler.Routes(engine.Group("/"))
@@ -0,0 +1,137 @@
+package httpsrv
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// requestIDHeader is the header requests are identified by in the audit log.
+const requestIDHeader = "X-Request-ID"
+
+type (
+	// actorContextKey is the context key of the subject of the caller's token.
+	actorContextKey struct{}
+	// requestIDContextKey is the context key of the ID of the request.
+	requestIDContextKey struct{}
+)
+
+// contextWithActor returns a copy of the given context with the given actor, which is recorded in audit
+// events.
+func contextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// requestIDMiddleware adds the ID of the request to the request's context, so it can be recorded in audit
+// events.
+func requestIDMiddleware(ctx *gin.Context) {
+	reqCtx := context.WithValue(ctx.Request.Context(), requestIDContextKey{}, ctx.GetHeader(requestIDHeader))
+
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+
+	ctx.Next()
+}
+
+// recordAuditEvent records a change to one of a tenant's resources, or to a resource not scoped to a
+// tenant if tenantID is empty, given the resource's API representation before and after the change. The event is recorded in the request's transaction, so
+// it is only committed along with the change.
+func (h *apiHandler) recordAuditEvent(
+	ctx context.Context,
+	tenantID string,
+	action types.AuditAction,
+	resourceType, resourceID string,
+	before, after any,
+) error {
+	beforeFields, afterFields, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+
+	event := types.AuditEvent{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		Actor:        actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		RequestID:    requestID,
+		Before:       beforeFields,
+		After:        afterFields,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	_, err = h.engine.RecordAuditEvent(ctx, event)
+
+	return err
+}
+
+// auditDiff returns the JSON fields of a resource before and after a change, either of which may be nil.
+// If both are given, fields which did not change are dropped from both.
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for k, v := range beforeFields {
+			if reflect.DeepEqual(v, afterFields[k]) {
+				delete(beforeFields, k)
+				delete(afterFields, k)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterJSON, err := marshalAuditFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+func auditFields(resource any) (map[string]any, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]any
+
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func marshalAuditFields(fields map[string]any) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(fields)
+}
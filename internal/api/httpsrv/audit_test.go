@@ -0,0 +1,99 @@
+package httpsrv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestAuditDiff checks that audit events record the fields of resources which changed.
+func TestAuditDiff(t *testing.T) {
+	t.Parallel()
+
+	type resource struct {
+		Name  string            `json:"name"`
+		URI   string            `json:"uri"`
+		Roles []string          `json:"roles,omitempty"`
+		Extra map[string]string `json:"extra,omitempty"`
+	}
+
+	type diffInput struct {
+		before any
+		after  any
+	}
+
+	type diff struct {
+		before string
+		after  string
+	}
+
+	runFn := func(ctx context.Context, input diffInput) testingx.TestResult[diff] {
+		before, after, err := auditDiff(input.before, input.after)
+
+		return testingx.TestResult[diff]{
+			Success: diff{
+				before: string(before),
+				after:  string(after),
+			},
+			Err: err,
+		}
+	}
+
+	checkDiff := func(before, after string) func(context.Context, *testing.T, testingx.TestResult[diff]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[diff]) {
+			if !assert.NoError(t, res.Err) {
+				return
+			}
+
+			if before == "" {
+				assert.Empty(t, res.Success.before)
+			} else {
+				assert.JSONEq(t, before, res.Success.before)
+			}
+
+			if after == "" {
+				assert.Empty(t, res.Success.after)
+			} else {
+				assert.JSONEq(t, after, res.Success.after)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[diffInput, diff]{
+		{
+			Name: "Create",
+			Input: diffInput{
+				after: resource{Name: "Example", URI: "https://example.com/"},
+			},
+			CheckFn: checkDiff("", `{"name":"Example","uri":"https://example.com/"}`),
+		},
+		{
+			Name: "Update",
+			Input: diffInput{
+				before: resource{Name: "Example", URI: "https://example.com/", Roles: []string{"admin"}},
+				after:  resource{Name: "Example", URI: "https://example.com/", Roles: []string{"admin", "viewer"}, Extra: map[string]string{"a": "b"}},
+			},
+			CheckFn: checkDiff(`{"roles":["admin"]}`, `{"roles":["admin","viewer"],"extra":{"a":"b"}}`),
+		},
+		{
+			Name: "Unchanged",
+			Input: diffInput{
+				before: resource{Name: "Example"},
+				after:  resource{Name: "Example"},
+			},
+			CheckFn: checkDiff(`{}`, `{}`),
+		},
+		{
+			Name: "Delete",
+			Input: diffInput{
+				before: resource{Name: "Example", URI: "https://example.com/"},
+			},
+			CheckFn: checkDiff(`{"name":"Example","uri":"https://example.com/"}`, ""),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -0,0 +1,154 @@
+package httpsrv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.hollow.sh/toolbox/ginauth"
+
+	"go.infratographer.com/identity-api/internal/authz"
+	v1 "go.infratographer.com/identity-api/pkg/api/v1"
+)
+
+// claimsKey is the gin context key the caller's verified token claims are stored under.
+const claimsKey = "identity-api.claims"
+
+// operationActions maps API operations to the action they perform, which is also the scope callers'
+// tokens must carry. Operations missing from the map are denied.
+var operationActions = map[string]string{
+	"CreateIssuer":           authz.ActionIssuersWrite,
+	"ListIssuers":            authz.ActionIssuersRead,
+	"GetIssuerByID":          authz.ActionIssuersRead,
+	"UpdateIssuer":           authz.ActionIssuersWrite,
+	"DeleteIssuer":           authz.ActionIssuersWrite,
+	"EvaluateClaimMappings":  authz.ActionIssuersRead,
+	"CreateGroupRoleMapping": authz.ActionIssuersWrite,
+	"ListGroupRoleMappings":  authz.ActionIssuersRead,
+	"DeleteGroupRoleMapping": authz.ActionIssuersWrite,
+	"CreateSigningKey":       authz.ActionSigningKeysWrite,
+	"ListSigningKeys":        authz.ActionSigningKeysRead,
+	"PromoteSigningKey":      authz.ActionSigningKeysWrite,
+	"RetireSigningKey":       authz.ActionSigningKeysWrite,
+	"ListAuditEvents":        authz.ActionAuditEventsRead,
+	"ListGlobalAuditEvents":  authz.ActionAuditEventsRead,
+}
+
+var (
+	errorUnauthenticated = errorWithStatus{
+		status:  http.StatusUnauthorized,
+		message: "valid bearer token required",
+	}
+
+	errorForbidden = errorWithStatus{
+		status:  http.StatusForbidden,
+		message: "forbidden",
+	}
+)
+
+// tokenVerifier represents a verifier of callers' bearer tokens.
+type tokenVerifier interface {
+	Verify(ctx *gin.Context) (ginauth.ClaimMetadata, error)
+}
+
+// apiAuth authenticates and authorizes API operations.
+type apiAuth struct {
+	verifier   tokenVerifier
+	authorizer authz.Authorizer
+}
+
+// authenticate is a gin middleware which requires the caller to present a valid bearer token. It runs
+// before requests are validated, so unauthenticated callers cannot probe the API's validation rules.
+func (a *apiAuth) authenticate(ctx *gin.Context) {
+	claims, err := a.verifier.Verify(ctx)
+	if err != nil {
+		resp := v1.ErrorResponse{
+			Errors: []string{
+				errorUnauthenticated.message,
+			},
+		}
+
+		ctx.Header("WWW-Authenticate", "Bearer")
+		ctx.AbortWithStatusJSON(errorUnauthenticated.status, resp)
+
+		return
+	}
+
+	ctx.Set(claimsKey, claims)
+}
+
+// middleware returns a strict handler middleware which requires the caller's token, verified by
+// authenticate, to carry the scope of the operation, and the authorizer to allow the caller to perform
+// it on the requested tenant.
+func (a *apiAuth) middleware(f StrictHandlerFunc, operationID string) StrictHandlerFunc {
+	action, known := operationActions[operationID]
+
+	return func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		if !known {
+			return nil, errorForbidden
+		}
+
+		value, _ := ctx.Get(claimsKey)
+
+		claims, ok := value.(ginauth.ClaimMetadata)
+		if !ok {
+			return nil, errorUnauthenticated
+		}
+
+		if !hasScope(claims.Roles, action) {
+			return nil, errorWithStatus{
+				status:  http.StatusForbidden,
+				message: fmt.Sprintf("missing required scope '%s'", action),
+			}
+		}
+
+		req := authz.Request{
+			Subject:  claims.Subject,
+			Token:    bearerToken(ctx),
+			Action:   action,
+			TenantID: ctx.Param("tenantID"),
+		}
+
+		err := a.authorizer.Authorize(ctx, req)
+		switch {
+		case err == nil:
+		case errors.Is(err, authz.ErrDenied):
+			return nil, errorForbidden
+		default:
+			return nil, errorWithStatus{
+				status:  http.StatusBadGateway,
+				message: err.Error(),
+			}
+		}
+
+		ctx.Request = ctx.Request.WithContext(contextWithActor(ctx.Request.Context(), claims.Subject))
+
+		return f(ctx, request)
+	}
+}
+
+// hasScope reports whether the given scopes include the given scope. Scopes may be given as a single
+// space-separated string.
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		for _, field := range strings.Fields(s) {
+			if field == scope {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// bearerToken returns the bearer token of the request, if any.
+func bearerToken(ctx *gin.Context) string {
+	scheme, token, ok := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
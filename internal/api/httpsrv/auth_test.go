@@ -0,0 +1,240 @@
+package httpsrv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.hollow.sh/toolbox/ginauth"
+
+	"go.infratographer.com/identity-api/internal/authz"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+var errTestUnauthenticated = errors.New("unauthenticated")
+
+type fakeTokenVerifier struct {
+	claims ginauth.ClaimMetadata
+	err    error
+}
+
+func (v fakeTokenVerifier) Verify(ctx *gin.Context) (ginauth.ClaimMetadata, error) {
+	return v.claims, v.err
+}
+
+// TestAPIAuthBeforeValidation checks that callers are authenticated before their requests are
+// validated, and that authenticated requests are still validated.
+func TestAPIAuthBeforeValidation(t *testing.T) {
+	t.Parallel()
+
+	validationMiddleware, err := oapiValidationMiddleware()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	type routesInput struct {
+		verifier fakeTokenVerifier
+		path     string
+	}
+
+	runFn := func(ctx context.Context, input routesInput) testingx.TestResult[int] {
+		auth := &apiAuth{
+			verifier:   input.verifier,
+			authorizer: authz.NewStaticAuthorizer(nil),
+		}
+
+		engine := gin.New()
+		engine.Use(auth.authenticate, validationMiddleware)
+		engine.GET("/api/v1/tenants/:tenantID/issuers", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, input.path, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		engine.ServeHTTP(w, req)
+
+		return testingx.TestResult[int]{
+			Success: w.Code,
+		}
+	}
+
+	validToken := fakeTokenVerifier{
+		claims: ginauth.ClaimMetadata{
+			Subject: "urn:example:user/admin",
+		},
+	}
+
+	invalidToken := fakeTokenVerifier{
+		err: errTestUnauthenticated,
+	}
+
+	checkStatus := func(status int) func(context.Context, *testing.T, testingx.TestResult[int]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[int]) {
+			if assert.NoError(t, res.Err) {
+				assert.Equal(t, status, res.Success)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[routesInput, int]{
+		{
+			Name: "Valid",
+			Input: routesInput{
+				verifier: validToken,
+				path:     "/api/v1/tenants/56a95c1b-33f8-4def-8b6d-ca9fe6976170/issuers",
+			},
+			CheckFn: checkStatus(http.StatusOK),
+		},
+		{
+			Name: "Invalid",
+			Input: routesInput{
+				verifier: validToken,
+				path:     "/api/v1/tenants/56a95c1b-33f8-4def-8b6d-ca9fe6976170/issuers?limit=0",
+			},
+			CheckFn: checkStatus(http.StatusBadRequest),
+		},
+		{
+			Name: "UnauthenticatedInvalid",
+			Input: routesInput{
+				verifier: invalidToken,
+				path:     "/api/v1/tenants/56a95c1b-33f8-4def-8b6d-ca9fe6976170/issuers?limit=0",
+			},
+			CheckFn: checkStatus(http.StatusUnauthorized),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestAPIAuthMiddleware checks that API operations require a valid token carrying their scope, and
+// that the authorizer allows the caller to perform them on the requested tenant.
+func TestAPIAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
+
+	authorizer := authz.NewStaticAuthorizer([]authz.PolicyRule{
+		{
+			Subjects: []string{"urn:example:user/admin"},
+			Tenants:  []string{tenantID},
+			Actions:  []string{authz.Wildcard},
+		},
+	})
+
+	type authInput struct {
+		operationID string
+		verifier    fakeTokenVerifier
+		tenantID    string
+	}
+
+	runFn := func(ctx context.Context, input authInput) testingx.TestResult[any] {
+		auth := &apiAuth{
+			verifier:   input.verifier,
+			authorizer: authorizer,
+		}
+
+		next := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+			return "ok", nil
+		}
+
+		w := httptest.NewRecorder()
+
+		ginCtx, _ := gin.CreateTestContext(w)
+		ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		ginCtx.Request.Header.Set("Authorization", "Bearer token")
+		ginCtx.Params = gin.Params{{Key: "tenantID", Value: input.tenantID}}
+
+		auth.authenticate(ginCtx)
+
+		if ginCtx.IsAborted() {
+			return testingx.TestResult[any]{
+				Err: errorWithStatus{
+					status:  w.Code,
+					message: w.Body.String(),
+				},
+			}
+		}
+
+		resp, err := auth.middleware(next, input.operationID)(ginCtx, nil)
+
+		return testingx.TestResult[any]{
+			Success: resp,
+			Err:     err,
+		}
+	}
+
+	adminToken := fakeTokenVerifier{
+		claims: ginauth.ClaimMetadata{
+			Subject: "urn:example:user/admin",
+			Roles:   []string{authz.ActionIssuersRead + " " + authz.ActionIssuersWrite},
+		},
+	}
+
+	checkStatus := func(status int) func(context.Context, *testing.T, testingx.TestResult[any]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+			var errStatus errorWithStatus
+
+			if assert.ErrorAs(t, res.Err, &errStatus) {
+				assert.Equal(t, status, errStatus.status)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[authInput, any]{
+		{
+			Name: "Allowed",
+			Input: authInput{
+				operationID: "UpdateIssuer",
+				verifier:    adminToken,
+				tenantID:    tenantID,
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, "ok", res.Success)
+				}
+			},
+		},
+		{
+			Name: "Unauthenticated",
+			Input: authInput{
+				operationID: "GetIssuerByID",
+				verifier:    fakeTokenVerifier{err: errTestUnauthenticated},
+				tenantID:    tenantID,
+			},
+			CheckFn: checkStatus(http.StatusUnauthorized),
+		},
+		{
+			Name: "MissingScope",
+			Input: authInput{
+				operationID: "CreateSigningKey",
+				verifier:    adminToken,
+			},
+			CheckFn: checkStatus(http.StatusForbidden),
+		},
+		{
+			Name: "Denied",
+			Input: authInput{
+				operationID: "GetIssuerByID",
+				verifier:    adminToken,
+				tenantID:    "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+			},
+			CheckFn: checkStatus(http.StatusForbidden),
+		},
+		{
+			Name: "UnknownOperation",
+			Input: authInput{
+				operationID: "Unknown",
+				verifier:    adminToken,
+				tenantID:    tenantID,
+			},
+			CheckFn: checkStatus(http.StatusForbidden),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -1,6 +1,14 @@
 package httpsrv
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.infratographer.com/identity-api/internal/celutils"
+	"go.infratographer.com/identity-api/internal/keymanager"
+	"go.infratographer.com/identity-api/internal/types"
+)
 
 type errorWithStatus struct {
 	status  int
@@ -16,4 +24,52 @@ var (
 		status:  http.StatusNotFound,
 		message: "not found",
 	}
+
+	errorManagedKeysDisabled = errorWithStatus{
+		status:  http.StatusNotFound,
+		message: "managed signing keys are not enabled",
+	}
 )
+
+// errorMessage produces a message for the given error, including the wrapped error if one exists.
+func errorMessage(err error) string {
+	if inner := errors.Unwrap(err); inner != nil {
+		return fmt.Sprintf("%s: %s", err, inner)
+	}
+
+	return err.Error()
+}
+
+// claimMappingError produces a bad request error for claim mappings that could not be built.
+func claimMappingError(err error) error {
+	message := "error parsing CEL expression"
+
+	if errors.Is(err, &celutils.ErrorCELLimit{}) ||
+		errors.Is(err, celutils.ErrorOutputTypeMismatch) ||
+		errors.Is(err, celutils.ErrorUnknownOutputType) {
+		message = errorMessage(err)
+	}
+
+	return errorWithStatus{
+		status:  http.StatusBadRequest,
+		message: message,
+	}
+}
+
+// signingKeyError produces an error for a failed signing key operation, with a not found or bad request
+// status where the request was at fault.
+func signingKeyError(err error) error {
+	switch {
+	case errors.Is(err, types.ErrorSigningKeyNotFound):
+		return errorNotFound
+	case errors.Is(err, keymanager.ErrInvalidStateTransition),
+		errors.Is(err, keymanager.ErrKeyNotPublished),
+		errors.Is(err, keymanager.ErrUnsupportedAlgorithm):
+		return errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: err.Error(),
+		}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,230 @@
+package httpsrv
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	fositestorage "github.com/ory/fosite/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+
+	"go.infratographer.com/identity-api/internal/authz"
+	"go.infratographer.com/identity-api/internal/discovery"
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/jwks"
+	"go.infratographer.com/identity-api/internal/rfc8693"
+	"go.infratographer.com/identity-api/internal/routes"
+	"go.infratographer.com/identity-api/internal/storage"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// TestTokenExchangeAPIAuth checks that a token exchanged for the management API's audience and scopes
+// can be used to call the management API.
+func TestTokenExchangeAPIAuth(t *testing.T) {
+	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
+	issuerURI := "https://iam.example.com/"
+	apiAudience := "https://iam.example.com/api/v1"
+	upstreamURI := "https://upstream.example.com/"
+
+	engine, err := storage.NewEngine(storage.Config{Type: storage.EngineTypeMemory})
+	require.NoError(t, err)
+
+	defer engine.Shutdown()
+
+	upstreamKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ctx, err := engine.BeginContext(context.Background())
+	require.NoError(t, err)
+
+	_, err = engine.CreateIssuer(ctx, types.Issuer{
+		TenantID: tenantID,
+		ID:       "e495a393-ae79-4a02-a78d-9798c7d9d252",
+		Name:     "Upstream",
+		URI:      upstreamURI,
+		JWKS: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{
+					Key:       upstreamKey.Public(),
+					KeyID:     "upstream",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				},
+			},
+		},
+		ClaimMappings: types.ClaimsMapping{},
+	})
+	require.NoError(t, err)
+
+	// User info is stored ahead of the exchange, so it is not fetched from the upstream issuer.
+	_, err = engine.StoreUserInfo(ctx, types.UserInfo{
+		Name:    "User",
+		Email:   "user@example.com",
+		Issuer:  upstreamURI,
+		Subject: "user",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, engine.CommitContext(ctx))
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{
+		Key:       signingKey,
+		KeyID:     "identity-api",
+		Algorithm: string(jose.ES256),
+		Use:       "sig",
+	}
+
+	subjectStrategy, err := rfc8693.NewSubjectStrategy(fositex.SubjectConfig{}, engine, engine)
+	require.NoError(t, err)
+
+	oauth2Config := &fositex.OAuth2Config{
+		Config: &fosite.Config{
+			AccessTokenIssuer:   issuerURI,
+			AccessTokenLifespan: time.Hour,
+			GlobalSecret:        []byte("abcd1234abcd1234abcd1234abcd1234"),
+		},
+		SigningKey:            &jwk,
+		SigningJWKS:           &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}},
+		IssuerJWKSURIStrategy: jwks.NewIssuerJWKSURIStrategy(engine, discovery.DefaultClient),
+		ClaimMappingStrategy:  rfc8693.NewClaimMappingStrategy(engine, engine),
+		UserInfoStrategy:      engine,
+		SubjectStrategy:       subjectStrategy,
+		TokenExchange: fositex.TokenExchangeConfig{
+			AllowedAudiences: []string{apiAudience},
+			AllowedScopes:    []string{authz.ActionIssuersRead},
+		},
+	}
+
+	hmacStrategy := compose.NewOAuth2HMACStrategy(oauth2Config)
+	jwtStrategy := compose.NewOAuth2JWTStrategy(oauth2Config.GetPrivateKey, hmacStrategy, oauth2Config)
+
+	provider := fositex.NewOAuth2Provider(
+		oauth2Config,
+		fositestorage.NewExampleStore(),
+		jwtStrategy,
+		rfc8693.NewTokenExchangeHandler,
+	)
+
+	authorizer := authz.NewStaticAuthorizer([]authz.PolicyRule{
+		{
+			Subjects: []string{authz.Wildcard},
+			Tenants:  []string{tenantID},
+			Actions:  []string{authz.ActionIssuersRead},
+		},
+	})
+
+	apiHandler, err := NewAPIHandler(engine, oauth2Config, authz.Config{}, authorizer)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.ContextWithFallback = true
+
+	routes.NewRouter(zap.NewNop().Sugar(), oauth2Config, provider).Routes(router.Group("/"))
+	apiHandler.Routes(router.Group("/"))
+
+	upstreamSigner, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: upstreamKey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "upstream"),
+	)
+	require.NoError(t, err)
+
+	subjectToken, err := josejwt.Signed(upstreamSigner).Claims(josejwt.Claims{
+		Issuer:   upstreamURI,
+		Subject:  "user",
+		IssuedAt: josejwt.NewNumericDate(time.Now()),
+		Expiry:   josejwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).CompactSerialize()
+	require.NoError(t, err)
+
+	exchange := func(audience, scope string) *httptest.ResponseRecorder {
+		form := url.Values{
+			"grant_type":         {rfc8693.GrantTypeTokenExchange},
+			"subject_token":      {subjectToken},
+			"subject_token_type": {rfc8693.TokenTypeJWT},
+			"audience":           {audience},
+			"scope":              {scope},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("my-client", "foobar")
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		return resp
+	}
+
+	listIssuers := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/"+tenantID+"/issuers", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		return resp.Code
+	}
+
+	accessToken := func(t *testing.T, resp *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var body struct {
+			AccessToken string `json:"access_token"`
+		}
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+
+		return body.AccessToken
+	}
+
+	t.Run("AllowedScope", func(t *testing.T) {
+		token := accessToken(t, exchange(apiAudience, authz.ActionIssuersRead))
+
+		assert.Equal(t, http.StatusOK, listIssuers(token))
+	})
+
+	t.Run("MissingScope", func(t *testing.T) {
+		token := accessToken(t, exchange(apiAudience, ""))
+
+		assert.Equal(t, http.StatusForbidden, listIssuers(token))
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, listIssuers(""))
+	})
+
+	t.Run("DisallowedScope", func(t *testing.T) {
+		resp := exchange(apiAudience, authz.ActionIssuersWrite)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "invalid_scope")
+	})
+
+	t.Run("DisallowedAudience", func(t *testing.T) {
+		resp := exchange("https://other.example.com/", authz.ActionIssuersRead)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "invalid_target")
+	})
+}
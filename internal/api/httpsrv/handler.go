@@ -2,12 +2,23 @@ package httpsrv
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/ory/fosite/token/jwt"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
 
+	"go.infratographer.com/identity-api/internal/authz"
+	"go.infratographer.com/identity-api/internal/celutils"
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/rfc8693"
 	"go.infratographer.com/identity-api/internal/storage"
+	"go.infratographer.com/identity-api/internal/tokenauth"
 	"go.infratographer.com/identity-api/internal/types"
 	v1 "go.infratographer.com/identity-api/pkg/api/v1"
 )
@@ -115,6 +126,7 @@ func storageMiddleware(engine storage.Engine) gin.HandlerFunc {
 // apiHandler represents an API handler.
 type apiHandler struct {
 	engine storage.Engine
+	config fositex.OAuth2Configurator
 }
 
 func (h *apiHandler) CreateIssuer(ctx context.Context, req CreateIssuerRequestObject) (CreateIssuerResponseObject, error) {
@@ -123,28 +135,45 @@ func (h *apiHandler) CreateIssuer(ctx context.Context, req CreateIssuerRequestOb
 
 	var (
 		claimsMapping types.ClaimsMapping
+		outputs       types.ClaimMappingOutputs
 		err           error
 	)
 
+	if createOp.ClaimMappingOutputs != nil {
+		outputs = types.NewClaimMappingOutputs(*createOp.ClaimMappingOutputs)
+	}
+
 	if createOp.ClaimMappings != nil {
-		claimsMapping, err = types.NewClaimsMapping(*createOp.ClaimMappings)
+		claimsMapping, err = types.NewClaimsMapping(*createOp.ClaimMappings, outputs)
 		if err != nil {
-			err = errorWithStatus{
-				status:  http.StatusBadRequest,
-				message: "error parsing CEL expression",
-			}
-
-			return nil, err
+			return nil, claimMappingError(err)
 		}
 	}
 
 	issuerToCreate := types.Issuer{
-		TenantID:      tenantID.String(),
-		ID:            uuid.New().String(),
-		Name:          createOp.Name,
-		URI:           createOp.URI,
-		JWKSURI:       createOp.JWKSURI,
-		ClaimMappings: claimsMapping,
+		TenantID:            tenantID.String(),
+		ID:                  uuid.New().String(),
+		Name:                createOp.Name,
+		URI:                 createOp.URI,
+		ClaimMappings:       claimsMapping,
+		ClaimMappingOutputs: outputs.For(claimsMapping),
+	}
+
+	if createOp.JWKSURI != nil {
+		issuerToCreate.JWKSURI = *createOp.JWKSURI
+	}
+
+	issuerToCreate.JWKS, err = buildIssuerJWKS(createOp.JWKS, createOp.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if createOp.UserInfoEndpoint != nil {
+		issuerToCreate.UserInfoEndpoint = *createOp.UserInfoEndpoint
+	}
+
+	if createOp.IntrospectionEndpoint != nil {
+		issuerToCreate.IntrospectionEndpoint = *createOp.IntrospectionEndpoint
 	}
 
 	issuer, err := h.engine.CreateIssuer(ctx, issuerToCreate)
@@ -157,13 +186,91 @@ func (h *apiHandler) CreateIssuer(ctx context.Context, req CreateIssuerRequestOb
 		return nil, err
 	}
 
+	err = h.recordAuditEvent(ctx, issuer.TenantID, types.AuditActionCreate, types.AuditResourceIssuer, issuer.ID, nil, out)
+	if err != nil {
+		return nil, err
+	}
+
 	return CreateIssuer200JSONResponse(out), nil
 }
 
+// getTenantIssuer gets the issuer with the given ID. Issuers that belong to other tenants are not found,
+// so their existence is not revealed.
+func (h *apiHandler) getTenantIssuer(ctx context.Context, tenantID, id string) (*types.Issuer, error) {
+	iss, err := h.engine.GetIssuerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if iss.TenantID != tenantID {
+		return nil, types.ErrorIssuerNotFound
+	}
+
+	return iss, nil
+}
+
+func (h *apiHandler) ListIssuers(ctx context.Context, req ListIssuersRequestObject) (ListIssuersResponseObject, error) {
+	params := req.Params
+
+	opts := types.IssuerListOptions{}
+
+	if params.Name != nil {
+		opts.Name = *params.Name
+	}
+
+	if params.Uri != nil {
+		opts.URI = *params.Uri
+	}
+
+	if params.Sort != nil {
+		sort := string(*params.Sort)
+
+		opts.Descending = strings.HasPrefix(sort, "-")
+		opts.SortBy = types.IssuerSortField(strings.TrimPrefix(sort, "-"))
+	}
+
+	if params.Limit != nil {
+		opts.Limit = *params.Limit
+	}
+
+	if params.Cursor != nil {
+		opts.Cursor = *params.Cursor
+	}
+
+	page, err := h.engine.ListIssuers(ctx, req.TenantID.String(), opts)
+	switch {
+	case err == nil:
+	case errors.Is(err, types.ErrInvalidIssuerListOptions):
+		return nil, errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: err.Error(),
+		}
+	default:
+		return nil, err
+	}
+
+	out := v1.IssuerList{
+		Issuers: make([]v1.Issuer, len(page.Issuers)),
+	}
+
+	for i, iss := range page.Issuers {
+		out.Issuers[i], err = iss.ToV1Issuer()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if page.NextCursor != "" {
+		out.NextCursor = &page.NextCursor
+	}
+
+	return ListIssuers200JSONResponse(out), nil
+}
+
 func (h *apiHandler) GetIssuerByID(ctx context.Context, req GetIssuerByIDRequestObject) (GetIssuerByIDResponseObject, error) {
 	id := req.Id.String()
 
-	iss, err := h.engine.GetIssuerByID(ctx, id)
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), id)
 	switch err {
 	case nil:
 	case types.ErrorIssuerNotFound:
@@ -184,28 +291,44 @@ func (h *apiHandler) UpdateIssuer(ctx context.Context, req UpdateIssuerRequestOb
 	id := req.Id.String()
 	updateOp := req.Body
 
-	var (
-		claimsMapping types.ClaimsMapping
-		err           error
-	)
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), id)
+	switch err {
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
 
-	if updateOp.ClaimMappings != nil {
-		claimsMapping, err = types.NewClaimsMapping(*updateOp.ClaimMappings)
-		if err != nil {
-			err = errorWithStatus{
-				status:  http.StatusBadRequest,
-				message: "error parsing CEL expression",
-			}
+	before, err := iss.ToV1Issuer()
+	if err != nil {
+		return nil, err
+	}
 
-			return nil, err
-		}
+	claimsMapping, outputs, err := buildClaimMappingsUpdate(iss, updateOp)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks, err := buildIssuerJWKS(updateOp.JWKS, updateOp.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty JWKS in an update removes the issuer's inline JWKS.
+	if updateOp.JWKS != nil && len(*updateOp.JWKS) == 0 {
+		jwks = &jose.JSONWebKeySet{}
 	}
 
 	update := types.IssuerUpdate{
-		Name:          updateOp.Name,
-		URI:           updateOp.URI,
-		JWKSURI:       updateOp.JWKSURI,
-		ClaimMappings: claimsMapping,
+		Name:                  updateOp.Name,
+		URI:                   updateOp.URI,
+		JWKSURI:               updateOp.JWKSURI,
+		UserInfoEndpoint:      updateOp.UserInfoEndpoint,
+		IntrospectionEndpoint: updateOp.IntrospectionEndpoint,
+		JWKS:                  jwks,
+		ClaimMappings:         claimsMapping,
+		ClaimMappingOutputs:   outputs,
 	}
 
 	issuer, err := h.engine.UpdateIssuer(ctx, id, update)
@@ -222,19 +345,101 @@ func (h *apiHandler) UpdateIssuer(ctx context.Context, req UpdateIssuerRequestOb
 		return nil, err
 	}
 
+	err = h.recordAuditEvent(ctx, issuer.TenantID, types.AuditActionUpdate, types.AuditResourceIssuer, issuer.ID, before, out)
+	if err != nil {
+		return nil, err
+	}
+
 	return UpdateIssuer200JSONResponse(out), nil
 }
 
+// buildClaimMappingsUpdate builds the claim mappings and declared outputs for an update of the given
+// issuer. Outputs are checked against the mappings they will apply to, so updating either one requires
+// the current issuer. Returned values are nil if they are unchanged.
+func buildClaimMappingsUpdate(
+	iss *types.Issuer,
+	updateOp *v1.IssuerUpdate,
+) (types.ClaimsMapping, types.ClaimMappingOutputs, error) {
+	if updateOp.ClaimMappings == nil && updateOp.ClaimMappingOutputs == nil {
+		return nil, nil, nil
+	}
+
+	outputs := iss.ClaimMappingOutputs
+	if updateOp.ClaimMappingOutputs != nil {
+		outputs = types.NewClaimMappingOutputs(*updateOp.ClaimMappingOutputs)
+	}
+
+	if updateOp.ClaimMappings == nil {
+		if err := outputs.Check(iss.ClaimMappings); err != nil {
+			return nil, nil, claimMappingError(err)
+		}
+
+		return nil, outputs.For(iss.ClaimMappings), nil
+	}
+
+	claimsMapping, err := types.NewClaimsMapping(*updateOp.ClaimMappings, outputs)
+	if err != nil {
+		return nil, nil, claimMappingError(err)
+	}
+
+	return claimsMapping, outputs.For(claimsMapping), nil
+}
+
+// buildIssuerJWKS builds an issuer's inline JWKS from a create or update request. An inline JWKS replaces
+// fetching the issuer's JWKS URI, so both cannot be given. Returns nil if no JWKS or an empty one is given.
+func buildIssuerJWKS(jwks *map[string]interface{}, jwksURI *string) (*jose.JSONWebKeySet, error) {
+	if jwks == nil || len(*jwks) == 0 {
+		return nil, nil
+	}
+
+	if jwksURI != nil && *jwksURI != "" {
+		err := errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: "jwks and jwks_uri cannot both be set",
+		}
+
+		return nil, err
+	}
+
+	out, err := types.NewIssuerJWKS(*jwks)
+	if err != nil {
+		err = errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: err.Error(),
+		}
+
+		return nil, err
+	}
+
+	return out, nil
+}
+
 func (h *apiHandler) DeleteIssuer(ctx context.Context, req DeleteIssuerRequestObject) (DeleteIssuerResponseObject, error) {
 	id := req.Id.String()
 
-	err := h.engine.DeleteIssuer(ctx, id)
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), id)
+	if err == nil {
+		err = h.engine.DeleteIssuer(ctx, id)
+	}
+
 	switch err {
-	case nil, types.ErrorIssuerNotFound:
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
 	default:
 		return nil, err
 	}
 
+	before, err := iss.ToV1Issuer()
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.recordAuditEvent(ctx, iss.TenantID, types.AuditActionDelete, types.AuditResourceIssuer, iss.ID, before, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	out := v1.DeleteResponse{
 		Success: true,
 	}
@@ -242,14 +447,568 @@ func (h *apiHandler) DeleteIssuer(ctx context.Context, req DeleteIssuerRequestOb
 	return DeleteIssuer200JSONResponse(out), nil
 }
 
+func (h *apiHandler) EvaluateClaimMappings(ctx context.Context, req EvaluateClaimMappingsRequestObject) (EvaluateClaimMappingsResponseObject, error) {
+	id := req.Id.String()
+	evalOp := req.Body
+
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), id)
+	switch err {
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
+
+	claims, err := buildSampleClaims(evalOp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch claims.Issuer {
+	case "":
+		claims.Issuer = iss.URI
+	case iss.URI:
+	default:
+		err = errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: "sample claims issuer does not match issuer URI",
+		}
+
+		return nil, err
+	}
+
+	errs := make(map[string]string)
+	mappings := iss.ClaimMappings
+
+	if evalOp.ClaimMappings != nil {
+		mappings = make(types.ClaimsMapping, len(*evalOp.ClaimMappings))
+
+		for k, expr := range *evalOp.ClaimMappings {
+			ast, err := celutils.ParseCEL(expr)
+			if err != nil {
+				errs[k] = errorMessage(err)
+
+				continue
+			}
+
+			if err := celutils.CheckCost(ast); err != nil {
+				errs[k] = errorMessage(err)
+
+				continue
+			}
+
+			mappings[k] = ast
+		}
+	}
+
+	outputs := iss.ClaimMappingOutputs
+	if evalOp.ClaimMappingOutputs != nil {
+		outputs = types.NewClaimMappingOutputs(*evalOp.ClaimMappingOutputs)
+	}
+
+	programs := make(types.ClaimMappingPrograms, len(mappings))
+
+	for k, ast := range mappings {
+		if err := celutils.CheckOutputType(ast, outputs[k].Type); err != nil {
+			errs[k] = errorMessage(err)
+
+			continue
+		}
+
+		prog, err := celutils.Program(ast)
+		if err != nil {
+			errs[k] = errorMessage(err)
+
+			continue
+		}
+
+		programs[k] = prog
+	}
+
+	mappingCtx, err := h.buildSampleMappingContext(ctx, iss, claims, evalOp)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := rfc8693.EvaluateClaimMappings(ctx, claims, iss, programs, outputs, mappingCtx)
+	if err != nil {
+		err = errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: err.Error(),
+		}
+
+		return nil, err
+	}
+
+	for k, err := range result.Errors {
+		errs[k] = errorMessage(err)
+	}
+
+	var mappedClaims jwt.JWTClaims
+
+	mappedClaims.FromMap(result.Claims)
+
+	// The subject is assigned when a user is first seen during a real exchange, so it is omitted here.
+	tokenClaims := rfc8693.BuildTokenClaims(h.config.GetAccessTokenIssuer(ctx), "", &mappedClaims, evalOp.ClientID)
+
+	out := v1.ClaimMappingEvaluation{
+		MappedClaims: result.Claims,
+		Errors:       errs,
+		Claims:       tokenClaims.ToMap(),
+	}
+
+	return EvaluateClaimMappings200JSONResponse(out), nil
+}
+
+// buildSampleClaims builds a set of claims from either the sample claims or the unverified sample subject
+// token in a claim mapping evaluation request.
+func buildSampleClaims(evalOp *v1.ClaimMappingEvaluationRequest) (*jwt.JWTClaims, error) {
+	var claimsMap map[string]any
+
+	switch {
+	case evalOp.Claims != nil && evalOp.SubjectToken == nil:
+		claimsMap = *evalOp.Claims
+	case evalOp.Claims == nil && evalOp.SubjectToken != nil:
+		token, err := josejwt.ParseSigned(*evalOp.SubjectToken)
+		if err != nil {
+			err = errorWithStatus{
+				status:  http.StatusBadRequest,
+				message: "error parsing subject token",
+			}
+
+			return nil, err
+		}
+
+		if err := token.UnsafeClaimsWithoutVerification(&claimsMap); err != nil {
+			err = errorWithStatus{
+				status:  http.StatusBadRequest,
+				message: "error parsing subject token claims",
+			}
+
+			return nil, err
+		}
+	default:
+		err := errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: "exactly one of claims or subject_token must be provided",
+		}
+
+		return nil, err
+	}
+
+	var claims jwt.JWTClaims
+
+	claims.FromMap(claimsMap)
+
+	return &claims, nil
+}
+
+// buildSampleMappingContext builds the claim mapping context for a claim mapping evaluation request. If no
+// sample user info is provided, stored user info for the sample subject is used if it exists.
+func (h *apiHandler) buildSampleMappingContext(
+	ctx context.Context,
+	iss *types.Issuer,
+	claims *jwt.JWTClaims,
+	evalOp *v1.ClaimMappingEvaluationRequest,
+) (types.ClaimMappingContext, error) {
+	var mappingCtx types.ClaimMappingContext
+
+	if evalOp.ClientID != nil {
+		mappingCtx.ClientID = *evalOp.ClientID
+	}
+
+	if evalOp.RequestedScopes != nil {
+		mappingCtx.RequestedScopes = *evalOp.RequestedScopes
+	}
+
+	if evalOp.Audience != nil {
+		mappingCtx.Audience = *evalOp.Audience
+	}
+
+	roles, err := rfc8693.ResolveRoles(ctx, h.engine, iss, claims)
+	if err != nil {
+		return types.ClaimMappingContext{}, err
+	}
+
+	mappingCtx.Roles = roles
+
+	if evalOp.Userinfo != nil {
+		userInfo := types.UserInfo{
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+		}
+
+		if evalOp.Userinfo.Name != nil {
+			userInfo.Name = *evalOp.Userinfo.Name
+		}
+
+		if evalOp.Userinfo.Email != nil {
+			userInfo.Email = *evalOp.Userinfo.Email
+		}
+
+		mappingCtx.UserInfo = &userInfo
+
+		return mappingCtx, nil
+	}
+
+	if claims.Subject == "" {
+		return mappingCtx, nil
+	}
+
+	userInfo, err := h.engine.LookupUserInfoByClaims(ctx, claims.Issuer, claims.Subject)
+	switch {
+	case err == nil:
+		mappingCtx.UserInfo = userInfo
+	case errors.Is(err, types.ErrUserInfoNotFound):
+	default:
+		return types.ClaimMappingContext{}, err
+	}
+
+	return mappingCtx, nil
+}
+
+func (h *apiHandler) CreateGroupRoleMapping(ctx context.Context, req CreateGroupRoleMappingRequestObject) (CreateGroupRoleMappingResponseObject, error) {
+	issuerID := req.Id.String()
+	createOp := req.Body
+
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), issuerID)
+	switch err {
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
+
+	mappingToCreate := types.GroupRoleMapping{
+		ID:       uuid.New().String(),
+		TenantID: iss.TenantID,
+		IssuerID: iss.ID,
+		Group:    createOp.Group,
+		Role:     createOp.Role,
+	}
+
+	mapping, err := h.engine.CreateGroupRoleMapping(ctx, mappingToCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	out := mapping.ToV1GroupRoleMapping()
+
+	err = h.recordAuditEvent(ctx, mapping.TenantID, types.AuditActionCreate, types.AuditResourceRoleMapping, mapping.ID, nil, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateGroupRoleMapping200JSONResponse(out), nil
+}
+
+func (h *apiHandler) ListGroupRoleMappings(ctx context.Context, req ListGroupRoleMappingsRequestObject) (ListGroupRoleMappingsResponseObject, error) {
+	issuerID := req.Id.String()
+
+	_, err := h.getTenantIssuer(ctx, req.TenantID.String(), issuerID)
+	switch err {
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
+
+	mappings, err := h.engine.ListGroupRoleMappings(ctx, issuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := v1.GroupRoleMappingList{
+		RoleMappings: make([]v1.GroupRoleMapping, len(mappings)),
+	}
+
+	for i, mapping := range mappings {
+		out.RoleMappings[i] = mapping.ToV1GroupRoleMapping()
+	}
+
+	return ListGroupRoleMappings200JSONResponse(out), nil
+}
+
+func (h *apiHandler) DeleteGroupRoleMapping(ctx context.Context, req DeleteGroupRoleMappingRequestObject) (DeleteGroupRoleMappingResponseObject, error) {
+	issuerID := req.Id.String()
+	id := req.MappingID.String()
+
+	iss, err := h.getTenantIssuer(ctx, req.TenantID.String(), issuerID)
+	switch err {
+	case nil:
+	case types.ErrorIssuerNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
+
+	mapping, err := h.engine.DeleteGroupRoleMapping(ctx, issuerID, id)
+	switch err {
+	case nil:
+	case types.ErrorGroupRoleMappingNotFound:
+		return nil, errorNotFound
+	default:
+		return nil, err
+	}
+
+	err = h.recordAuditEvent(ctx, iss.TenantID, types.AuditActionDelete, types.AuditResourceRoleMapping, id, mapping.ToV1GroupRoleMapping(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := v1.DeleteResponse{
+		Success: true,
+	}
+
+	return DeleteGroupRoleMapping200JSONResponse(out), nil
+}
+
+func (h *apiHandler) ListAuditEvents(ctx context.Context, req ListAuditEventsRequestObject) (ListAuditEventsResponseObject, error) {
+	params := req.Params
+
+	out, err := h.listAuditEvents(ctx, req.TenantID.String(), params.ResourceId, params.Limit, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListAuditEvents200JSONResponse(out), nil
+}
+
+func (h *apiHandler) ListGlobalAuditEvents(ctx context.Context, req ListGlobalAuditEventsRequestObject) (ListGlobalAuditEventsResponseObject, error) {
+	params := req.Params
+
+	out, err := h.listAuditEvents(ctx, "", params.ResourceId, params.Limit, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListGlobalAuditEvents200JSONResponse(out), nil
+}
+
+// listAuditEvents lists a page of the given tenant's audit events, or of the events not scoped to a
+// tenant if tenantID is empty.
+func (h *apiHandler) listAuditEvents(ctx context.Context, tenantID string, resourceID *string, limit *int, cursor *string) (v1.AuditEventList, error) {
+	opts := types.AuditEventListOptions{}
+
+	if resourceID != nil {
+		opts.ResourceID = *resourceID
+	}
+
+	if limit != nil {
+		opts.Limit = *limit
+	}
+
+	if cursor != nil {
+		opts.Cursor = *cursor
+	}
+
+	page, err := h.engine.ListAuditEvents(ctx, tenantID, opts)
+	switch {
+	case err == nil:
+	case errors.Is(err, types.ErrInvalidAuditEventListOptions):
+		return v1.AuditEventList{}, errorWithStatus{
+			status:  http.StatusBadRequest,
+			message: err.Error(),
+		}
+	default:
+		return v1.AuditEventList{}, err
+	}
+
+	out := v1.AuditEventList{
+		AuditEvents: make([]v1.AuditEvent, len(page.Events)),
+	}
+
+	for i, event := range page.Events {
+		out.AuditEvents[i], err = event.ToV1AuditEvent()
+		if err != nil {
+			return v1.AuditEventList{}, err
+		}
+	}
+
+	if page.NextCursor != "" {
+		out.NextCursor = &page.NextCursor
+	}
+
+	return out, nil
+}
+
+// signingKeyManager returns the config's signing key manager, or an error if managed signing keys are
+// not enabled.
+func (h *apiHandler) signingKeyManager(ctx context.Context) (fositex.SigningKeyManager, error) {
+	manager := h.config.GetSigningKeyManager(ctx)
+	if manager == nil {
+		return nil, errorManagedKeysDisabled
+	}
+
+	return manager, nil
+}
+
+func (h *apiHandler) CreateSigningKey(ctx context.Context, req CreateSigningKeyRequestObject) (CreateSigningKeyResponseObject, error) {
+	manager, err := h.signingKeyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alg jose.SignatureAlgorithm
+
+	if req.Body.Algorithm != nil {
+		alg = jose.SignatureAlgorithm(*req.Body.Algorithm)
+	}
+
+	key, err := manager.GenerateSigningKey(ctx, alg)
+	if err != nil {
+		return nil, signingKeyError(err)
+	}
+
+	out, err := key.ToV1SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Signing keys are not scoped to a tenant, so changes to them are recorded as global events.
+	err = h.recordAuditEvent(ctx, "", types.AuditActionCreate, types.AuditResourceSigningKey, out.ID, nil, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateSigningKey200JSONResponse(out), nil
+}
+
+func (h *apiHandler) ListSigningKeys(ctx context.Context, req ListSigningKeysRequestObject) (ListSigningKeysResponseObject, error) {
+	manager, err := h.signingKeyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := manager.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := v1.SigningKeyList{
+		SigningKeys: make([]v1.SigningKey, len(keys)),
+	}
+
+	for i, key := range keys {
+		out.SigningKeys[i], err = key.ToV1SigningKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ListSigningKeys200JSONResponse(out), nil
+}
+
+func (h *apiHandler) PromoteSigningKey(ctx context.Context, req PromoteSigningKeyRequestObject) (PromoteSigningKeyResponseObject, error) {
+	manager, err := h.signingKeyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Promoting a key also demotes the active key, so every key is compared before and after the change.
+	before, err := manager.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := manager.PromoteSigningKey(ctx, req.Kid)
+	if err != nil {
+		return nil, signingKeyError(err)
+	}
+
+	if err := h.recordSigningKeyUpdates(ctx, manager, before); err != nil {
+		return nil, err
+	}
+
+	out, err := key.ToV1SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return PromoteSigningKey200JSONResponse(out), nil
+}
+
+func (h *apiHandler) RetireSigningKey(ctx context.Context, req RetireSigningKeyRequestObject) (RetireSigningKeyResponseObject, error) {
+	manager, err := h.signingKeyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := manager.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := manager.RetireSigningKey(ctx, req.Kid)
+	if err != nil {
+		return nil, signingKeyError(err)
+	}
+
+	if err := h.recordSigningKeyUpdates(ctx, manager, before); err != nil {
+		return nil, err
+	}
+
+	out, err := key.ToV1SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return RetireSigningKey200JSONResponse(out), nil
+}
+
+// recordSigningKeyUpdates records an update event for each signing key which changed since the given
+// keys were listed. Signing keys are not scoped to a tenant, so the events are global.
+func (h *apiHandler) recordSigningKeyUpdates(ctx context.Context, manager fositex.SigningKeyManager, before []types.SigningKey) error {
+	after, err := manager.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]types.SigningKey, len(before))
+
+	for _, key := range before {
+		previous[key.ID] = key
+	}
+
+	for _, key := range after {
+		prev, ok := previous[key.ID]
+		if !ok || (prev.State == key.State && prev.ActivatedAt.Equal(key.ActivatedAt)) {
+			continue
+		}
+
+		prevOut, err := prev.ToV1SigningKey()
+		if err != nil {
+			return err
+		}
+
+		out, err := key.ToV1SigningKey()
+		if err != nil {
+			return err
+		}
+
+		err = h.recordAuditEvent(ctx, "", types.AuditActionUpdate, types.AuditResourceSigningKey, key.ID, prevOut, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // APIHandler represents an identity-api management API handler.
 type APIHandler struct {
 	handler              *apiHandler
+	auth                 *apiAuth
 	validationMiddleware gin.HandlerFunc
 }
 
-// NewAPIHandler creates an API handler with the given storage engine.
-func NewAPIHandler(engine storage.Engine) (*APIHandler, error) {
+// NewAPIHandler creates an API handler with the given storage engine and OAuth 2.0 config. Unless auth
+// is disabled, operations require identity-api-issued bearer tokens carrying the operation's scope, and
+// are checked against the given authorizer.
+func NewAPIHandler(engine storage.Engine, config fositex.OAuth2Configurator, authConfig authz.Config, authorizer authz.Authorizer) (*APIHandler, error) {
 	validationMiddleware, err := oapiValidationMiddleware()
 	if err != nil {
 		return nil, err
@@ -257,6 +1016,7 @@ func NewAPIHandler(engine storage.Engine) (*APIHandler, error) {
 
 	handler := apiHandler{
 		engine: engine,
+		config: config,
 	}
 
 	out := &APIHandler{
@@ -264,14 +1024,47 @@ func NewAPIHandler(engine storage.Engine) (*APIHandler, error) {
 		validationMiddleware: validationMiddleware,
 	}
 
+	if authConfig.Disabled {
+		return out, nil
+	}
+
+	audience := authConfig.Audience
+	if audience == "" {
+		audience, err = url.JoinPath(config.GetAccessTokenIssuer(context.Background()), "api", "v1")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scopesClaim := authConfig.ScopesClaim
+	if scopesClaim == "" {
+		scopesClaim = authz.DefaultScopesClaim
+	}
+
+	verifier, err := tokenauth.NewVerifier(config, audience, scopesClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	out.auth = &apiAuth{
+		verifier:   verifier,
+		authorizer: authorizer,
+	}
+
 	return out, nil
 }
 
 // Routes registers the API's routes against the provided router group.
 func (h *APIHandler) Routes(rg *gin.RouterGroup) {
+	// Callers are authenticated before their requests are validated.
+	if h.auth != nil {
+		rg.Use(h.auth.authenticate)
+	}
+
 	rg.Use(
 		h.validationMiddleware,
 		errorHandlerMiddleware,
+		requestIDMiddleware,
 		storageMiddleware(h.handler.engine),
 	)
 
@@ -279,7 +1072,13 @@ func (h *APIHandler) Routes(rg *gin.RouterGroup) {
 		ErrorHandler: validationErrorHandler,
 	}
 
-	strictHandler := NewStrictHandler(h.handler, nil)
+	var middlewares []StrictMiddlewareFunc
+
+	if h.auth != nil {
+		middlewares = append(middlewares, h.auth.middleware)
+	}
+
+	strictHandler := NewStrictHandler(h.handler, middlewares)
 
 	RegisterHandlersWithOptions(rg, strictHandler, options)
 }
@@ -2,12 +2,19 @@ package httpsrv
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
 
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/keymanager"
 	"go.infratographer.com/identity-api/internal/storage"
 	"go.infratographer.com/identity-api/internal/testingx"
 	"go.infratographer.com/identity-api/internal/types"
@@ -19,13 +26,14 @@ func TestAPIHandler(t *testing.T) {
 		"foo": "123",
 	}
 
-	mappings, err := types.NewClaimsMapping(mappingStrs)
+	mappings, err := types.NewClaimsMapping(mappingStrs, nil)
 	if err != nil {
 		panic(err)
 	}
 
 	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
 	tenantUUID := uuid.MustParse(tenantID)
+	otherTenantUUID := uuid.MustParse("b8bfd705-b768-47a4-85a0-fe006f5bcfca")
 	issuerID := "e495a393-ae79-4a02-a78d-9798c7d9d252"
 	issuerUUID := uuid.MustParse(issuerID)
 	issuer := types.Issuer{
@@ -42,7 +50,7 @@ func TestAPIHandler(t *testing.T) {
 		SeedData: storage.SeedData{
 			Issuers: []storage.SeedIssuer{
 				{
-					TenantID:      "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+					TenantID:      tenantID,
 					ID:            "e495a393-ae79-4a02-a78d-9798c7d9d252",
 					Name:          "Example",
 					URI:           "https://example.com/",
@@ -64,13 +72,48 @@ func TestAPIHandler(t *testing.T) {
 			engine: issSvc,
 		}
 
+		jwksURI := "https://issuer.info/jwks.json"
+
 		createOp := &v1.CreateIssuer{
 			ClaimMappings: &mappingStrs,
-			JWKSURI:       "https://issuer.info/jwks.json",
+			JWKSURI:       &jwksURI,
 			Name:          "Good issuer",
 			URI:           "https://issuer.info/",
 		}
 
+		privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "key generation failed")
+		}
+
+		jwks, err := types.IssuerJWKSToV1(&jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{
+					Key:       privKey.Public(),
+					KeyID:     "inline",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				},
+			},
+		})
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "JWKS conversion failed")
+		}
+
+		privateJWKS, err := types.IssuerJWKSToV1(&jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{
+					Key:       privKey,
+					KeyID:     "private",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				},
+			},
+		})
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "JWKS conversion failed")
+		}
+
 		setupFn := func(ctx context.Context) context.Context {
 			ctx, err := issSvc.BeginContext(ctx)
 			if !assert.NoError(t, err) {
@@ -118,6 +161,79 @@ func TestAPIHandler(t *testing.T) {
 				},
 				CleanupFn: cleanupFn,
 			},
+			{
+				Name: "InlineJWKS",
+				Input: CreateIssuerRequestObject{
+					TenantID: tenantUUID,
+					Body: &v1.CreateIssuer{
+						JWKS: &jwks,
+						Name: "Air-gapped issuer",
+						URI:  "https://airgapped.info/",
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateIssuerResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(CreateIssuer200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for create issuer response")
+					}
+
+					obsIssuer, err := issSvc.GetIssuerByID(ctx, resp.ID.String())
+					if !assert.NoError(t, err) || !assert.NotNil(t, obsIssuer.JWKS) {
+						return
+					}
+
+					assert.Len(t, obsIssuer.JWKS.Key("inline"), 1)
+					assert.Equal(t, &jwks, resp.JWKS)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "PrivateJWKS",
+				Input: CreateIssuerRequestObject{
+					TenantID: tenantUUID,
+					Body: &v1.CreateIssuer{
+						JWKS: &privateJWKS,
+						Name: "Leaky issuer",
+						URI:  "https://leaky.info/",
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateIssuerResponseObject]) {
+					var errWithStatus errorWithStatus
+
+					if assert.ErrorAs(t, result.Err, &errWithStatus) {
+						assert.Equal(t, http.StatusBadRequest, errWithStatus.status)
+						assert.Contains(t, errWithStatus.message, "not a public key")
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "JWKSAndJWKSURI",
+				Input: CreateIssuerRequestObject{
+					TenantID: tenantUUID,
+					Body: &v1.CreateIssuer{
+						JWKS:    &jwks,
+						JWKSURI: &jwksURI,
+						Name:    "Ambiguous issuer",
+						URI:     "https://ambiguous.info/",
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateIssuerResponseObject]) {
+					var errWithStatus errorWithStatus
+
+					if assert.ErrorAs(t, result.Err, &errWithStatus) {
+						assert.Equal(t, http.StatusBadRequest, errWithStatus.status)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
 			{
 				Name: "CELError",
 				Input: CreateIssuerRequestObject{
@@ -126,9 +242,8 @@ func TestAPIHandler(t *testing.T) {
 						ClaimMappings: &map[string]string{
 							"bad": "'123",
 						},
-						JWKSURI: "https://bad.info/jwks.json",
-						Name:    "Bad issuer",
-						URI:     "https://bad.info/",
+						Name: "Bad issuer",
+						URI:  "https://bad.info/",
 					},
 				},
 				SetupFn: setupFn,
@@ -142,6 +257,29 @@ func TestAPIHandler(t *testing.T) {
 				},
 				CleanupFn: cleanupFn,
 			},
+			{
+				Name: "CELCostLimit",
+				Input: CreateIssuerRequestObject{
+					TenantID: tenantUUID,
+					Body: &v1.CreateIssuer{
+						ClaimMappings: &map[string]string{
+							"expensive": "requested_scopes.map(a, requested_scopes.map(b, requested_scopes.map(c, a + b + c)))",
+						},
+						Name: "Expensive issuer",
+						URI:  "https://expensive.info/",
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateIssuerResponseObject]) {
+					var errWithStatus errorWithStatus
+
+					if assert.ErrorAs(t, result.Err, &errWithStatus) {
+						assert.Equal(t, http.StatusBadRequest, errWithStatus.status)
+						assert.Contains(t, errWithStatus.message, "exceeded evaluation limits")
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
 		}
 
 		runFn := func(ctx context.Context, input CreateIssuerRequestObject) testingx.TestResult[CreateIssuerResponseObject] {
@@ -169,7 +307,8 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "Success",
 				Input: GetIssuerByIDRequestObject{
-					Id: issuerUUID,
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
 				},
 				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[GetIssuerByIDResponseObject]) {
 					if !assert.NoError(t, result.Err) {
@@ -179,7 +318,7 @@ func TestAPIHandler(t *testing.T) {
 					expIssuer := v1.Issuer{
 						ID:            issuerUUID,
 						ClaimMappings: mappingStrs,
-						JWKSURI:       issuer.JWKSURI,
+						JWKSURI:       &issuer.JWKSURI,
 						Name:          issuer.Name,
 						URI:           issuer.URI,
 					}
@@ -197,7 +336,18 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "NotFound",
 				Input: GetIssuerByIDRequestObject{
-					Id: uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					TenantID: tenantUUID,
+					Id:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[GetIssuerByIDResponseObject]) {
+					assert.ErrorIs(t, errorNotFound, result.Err)
+				},
+			},
+			{
+				Name: "OtherTenant",
+				Input: GetIssuerByIDRequestObject{
+					TenantID: otherTenantUUID,
+					Id:       issuerUUID,
 				},
 				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[GetIssuerByIDResponseObject]) {
 					assert.ErrorIs(t, errorNotFound, result.Err)
@@ -219,6 +369,118 @@ func TestAPIHandler(t *testing.T) {
 		testingx.RunTests(context.Background(), t, testCases, runFn)
 	})
 
+	t.Run("ListIssuers", func(t *testing.T) {
+		t.Parallel()
+
+		handler := apiHandler{
+			engine: issSvc,
+		}
+
+		listTenantUUID := uuid.MustParse("1f5e0c3a-7d9b-4f0e-8c2d-6a4b3e2d1c0f")
+
+		issuers := []types.Issuer{
+			{
+				TenantID:      listTenantUUID.String(),
+				ID:            "2a7c6e1d-5b3f-4e8a-9c0d-1e2f3a4b5c61",
+				Name:          "First",
+				URI:           "https://first.example.com/",
+				ClaimMappings: mappings,
+			},
+			{
+				TenantID:      listTenantUUID.String(),
+				ID:            "2a7c6e1d-5b3f-4e8a-9c0d-1e2f3a4b5c62",
+				Name:          "Second",
+				URI:           "https://second.example.com/",
+				ClaimMappings: mappings,
+			},
+		}
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := issSvc.BeginContext(ctx)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			for _, iss := range issuers {
+				_, err = issSvc.CreateIssuer(ctx, iss)
+				if !assert.NoError(t, err) {
+					assert.FailNow(t, "setup failed")
+				}
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := issSvc.RollbackContext(ctx)
+			assert.NoError(t, err)
+		}
+
+		sortNameDesc := v1.MinusName
+		limit := 1
+		badCursor := "not a cursor"
+
+		testCases := []testingx.TestCase[ListIssuersRequestObject, ListIssuersResponseObject]{
+			{
+				Name: "Success",
+				Input: ListIssuersRequestObject{
+					TenantID: listTenantUUID,
+					Params: v1.ListIssuersParams{
+						Sort:  &sortNameDesc,
+						Limit: &limit,
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListIssuersResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(ListIssuers200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for list issuers response")
+					}
+
+					if assert.Len(t, resp.Issuers, 1) {
+						assert.Equal(t, "Second", resp.Issuers[0].Name)
+					}
+
+					assert.NotNil(t, resp.NextCursor)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "InvalidCursor",
+				Input: ListIssuersRequestObject{
+					TenantID: listTenantUUID,
+					Params: v1.ListIssuersParams{
+						Cursor: &badCursor,
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListIssuersResponseObject]) {
+					var statusErr errorWithStatus
+
+					if assert.ErrorAs(t, result.Err, &statusErr) {
+						assert.Equal(t, http.StatusBadRequest, statusErr.status)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input ListIssuersRequestObject) testingx.TestResult[ListIssuersResponseObject] {
+			resp, err := handler.ListIssuers(ctx, input)
+
+			return testingx.TestResult[ListIssuersResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
 	t.Run("UpdateIssuer", func(t *testing.T) {
 		t.Parallel()
 
@@ -263,7 +525,8 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "Success",
 				Input: UpdateIssuerRequestObject{
-					Id: issuerUUID,
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
 					Body: &v1.IssuerUpdate{
 						Name: &newName,
 					},
@@ -277,7 +540,7 @@ func TestAPIHandler(t *testing.T) {
 					expIssuer := v1.Issuer{
 						ID:            issuerUUID,
 						ClaimMappings: mappingStrs,
-						JWKSURI:       issuer.JWKSURI,
+						JWKSURI:       &issuer.JWKSURI,
 						Name:          newName,
 						URI:           issuer.URI,
 					}
@@ -296,7 +559,23 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "NotFound",
 				Input: UpdateIssuerRequestObject{
-					Id: uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					TenantID: tenantUUID,
+					Id:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					Body: &v1.IssuerUpdate{
+						Name: &newName,
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[UpdateIssuerResponseObject]) {
+					assert.ErrorIs(t, errorNotFound, result.Err)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "OtherTenant",
+				Input: UpdateIssuerRequestObject{
+					TenantID: otherTenantUUID,
+					Id:       issuerUUID,
 					Body: &v1.IssuerUpdate{
 						Name: &newName,
 					},
@@ -366,7 +645,8 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "Success",
 				Input: DeleteIssuerRequestObject{
-					Id: issuerUUID,
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
 				},
 				SetupFn: setupFn,
 				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteIssuerResponseObject]) {
@@ -395,26 +675,27 @@ func TestAPIHandler(t *testing.T) {
 			{
 				Name: "NotFound",
 				Input: DeleteIssuerRequestObject{
-					Id: uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					TenantID: tenantUUID,
+					Id:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
 				},
 				SetupFn: setupFn,
 				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteIssuerResponseObject]) {
-					if !assert.NoError(t, result.Err) {
-						return
-					}
-
-					resp, ok := result.Success.(DeleteIssuer200JSONResponse)
-					if !ok {
-						assert.FailNow(t, "unexpected result type for delete issuer response")
-					}
-
-					obsResp := v1.DeleteResponse(resp)
-
-					expResp := v1.DeleteResponse{
-						Success: true,
-					}
+					assert.ErrorIs(t, result.Err, errorNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "OtherTenant",
+				Input: DeleteIssuerRequestObject{
+					TenantID: otherTenantUUID,
+					Id:       issuerUUID,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteIssuerResponseObject]) {
+					assert.ErrorIs(t, result.Err, errorNotFound)
 
-					assert.Equal(t, expResp, obsResp)
+					_, err := issSvc.GetIssuerByID(ctx, issuerUUID.String())
+					assert.NoError(t, err)
 				},
 				CleanupFn: cleanupFn,
 			},
@@ -433,4 +714,876 @@ func TestAPIHandler(t *testing.T) {
 
 		testingx.RunTests(context.Background(), t, testCases, runFn)
 	})
+
+	t.Run("EvaluateClaimMappings", func(t *testing.T) {
+		t.Parallel()
+
+		accessTokenIssuer := "https://identity-api.example.com/"
+
+		handler := apiHandler{
+			engine: issSvc,
+			config: &fositex.OAuth2Config{
+				Config: &fosite.Config{
+					AccessTokenIssuer: accessTokenIssuer,
+				},
+			},
+		}
+
+		sampleClaims := map[string]any{
+			"sub": "foo",
+			"num": 2,
+		}
+
+		clientID := "my-client"
+		email := "foo@example.com"
+		listStringType := v1.Liststring
+		stringType := v1.String
+		omitNull := true
+
+		testCases := []testingx.TestCase[EvaluateClaimMappingsRequestObject, EvaluateClaimMappingsResponseObject]{
+			{
+				Name: "Success",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body: &v1.ClaimMappingEvaluationRequest{
+						Claims: &sampleClaims,
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(EvaluateClaimMappings200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for evaluate claim mappings response")
+					}
+
+					assert.Equal(t, map[string]any{"foo": int64(123)}, resp.MappedClaims)
+					assert.Empty(t, resp.Errors)
+					assert.Equal(t, accessTokenIssuer, resp.Claims["iss"])
+					assert.Equal(t, int64(123), resp.Claims["foo"])
+					assert.NotContains(t, resp.Claims, "sub")
+				},
+			},
+			{
+				Name: "CandidateMappings",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body: &v1.ClaimMappingEvaluationRequest{
+						Claims: &sampleClaims,
+						ClaimMappings: &map[string]string{
+							"plusone": "1 + claims.num",
+							"missing": "claims.missing",
+							"bad":     "'123",
+						},
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(EvaluateClaimMappings200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for evaluate claim mappings response")
+					}
+
+					assert.Equal(t, map[string]any{"plusone": int64(3)}, resp.MappedClaims)
+					assert.Contains(t, resp.Errors, "missing")
+					assert.Contains(t, resp.Errors, "bad")
+					assert.NotContains(t, resp.Claims, "foo")
+				},
+			},
+			{
+				Name: "TypedOutputs",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body: &v1.ClaimMappingEvaluationRequest{
+						Claims: &sampleClaims,
+						ClaimMappings: &map[string]string{
+							"scopes":   "['read', 'write']",
+							"optional": "has(claims.missing) ? claims.missing : null",
+							"num":      "claims.num",
+						},
+						ClaimMappingOutputs: &map[string]v1.ClaimMappingOutput{
+							"scopes": {
+								Type: &listStringType,
+							},
+							"optional": {
+								OmitNull: &omitNull,
+							},
+							"num": {
+								Type: &stringType,
+							},
+						},
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(EvaluateClaimMappings200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for evaluate claim mappings response")
+					}
+
+					assert.Equal(t, map[string]any{"scopes": []string{"read", "write"}}, resp.MappedClaims)
+					assert.Contains(t, resp.Errors, "num")
+					assert.NotContains(t, resp.Claims, "optional")
+				},
+			},
+			{
+				Name: "MappingContext",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body: &v1.ClaimMappingEvaluationRequest{
+						Claims: &sampleClaims,
+						ClaimMappings: &map[string]string{
+							"tenant": "issuer.tenant_id",
+							"scopes": "requested_scopes.join(' ')",
+							"email":  "userinfo.email",
+						},
+						ClientID:        &clientID,
+						RequestedScopes: &[]string{"read", "write"},
+						Userinfo: &v1.SampleUserInfo{
+							Email: &email,
+						},
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(EvaluateClaimMappings200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for evaluate claim mappings response")
+					}
+
+					expMapped := map[string]any{
+						"tenant": "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+						"scopes": "read write",
+						"email":  email,
+					}
+
+					assert.Equal(t, expMapped, resp.MappedClaims)
+					assert.Empty(t, resp.Errors)
+					assert.Equal(t, &clientID, resp.Claims["client_id"])
+				},
+			},
+			{
+				Name: "MissingInput",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body:     &v1.ClaimMappingEvaluationRequest{},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					expErr := errorWithStatus{
+						status:  http.StatusBadRequest,
+						message: "exactly one of claims or subject_token must be provided",
+					}
+
+					assert.ErrorIs(t, expErr, result.Err)
+				},
+			},
+			{
+				Name: "NotFound",
+				Input: EvaluateClaimMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+					Body: &v1.ClaimMappingEvaluationRequest{
+						Claims: &sampleClaims,
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[EvaluateClaimMappingsResponseObject]) {
+					assert.ErrorIs(t, errorNotFound, result.Err)
+				},
+			},
+		}
+
+		runFn := func(ctx context.Context, input EvaluateClaimMappingsRequestObject) testingx.TestResult[EvaluateClaimMappingsResponseObject] {
+			resp, err := handler.EvaluateClaimMappings(ctx, input)
+
+			result := testingx.TestResult[EvaluateClaimMappingsResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+
+			return result
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("CreateGroupRoleMapping", func(t *testing.T) {
+		t.Parallel()
+		handler := apiHandler{
+			engine: issSvc,
+		}
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := issSvc.BeginContext(ctx)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := issSvc.RollbackContext(ctx)
+			assert.NoError(t, err)
+		}
+
+		createOp := &v1.CreateGroupRoleMapping{
+			Group: "admins",
+			Role:  "admin",
+		}
+
+		testCases := []testingx.TestCase[CreateGroupRoleMappingRequestObject, CreateGroupRoleMappingResponseObject]{
+			{
+				Name: "Success",
+				Input: CreateGroupRoleMappingRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+					Body:     createOp,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateGroupRoleMappingResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(CreateGroupRoleMapping200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for create group role mapping response")
+					}
+
+					obsMapping := v1.GroupRoleMapping(resp)
+
+					expMapping := v1.GroupRoleMapping{
+						ID:       obsMapping.ID,
+						IssuerID: issuerUUID,
+						Group:    createOp.Group,
+						Role:     createOp.Role,
+					}
+
+					assert.Equal(t, expMapping, obsMapping)
+
+					roles, err := issSvc.LookupRolesByGroups(ctx, issuerID, []string{"admins"})
+					assert.NoError(t, err)
+					assert.Equal(t, []string{"admin"}, roles)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "NotFound",
+				Input: CreateGroupRoleMappingRequestObject{
+					TenantID: tenantUUID,
+					Id:       uuid.New(),
+					Body:     createOp,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[CreateGroupRoleMappingResponseObject]) {
+					assert.ErrorIs(t, result.Err, errorNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input CreateGroupRoleMappingRequestObject) testingx.TestResult[CreateGroupRoleMappingResponseObject] {
+			resp, err := handler.CreateGroupRoleMapping(ctx, input)
+
+			return testingx.TestResult[CreateGroupRoleMappingResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("ListGroupRoleMappings", func(t *testing.T) {
+		t.Parallel()
+		handler := apiHandler{
+			engine: issSvc,
+		}
+
+		mapping := types.GroupRoleMapping{
+			ID:       "7f1d3c52-3d0e-4b2e-9a61-0f4b8f3f3b10",
+			TenantID: tenantID,
+			IssuerID: issuerID,
+			Group:    "users",
+			Role:     "viewer",
+		}
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := issSvc.BeginContext(ctx)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			_, err = issSvc.CreateGroupRoleMapping(ctx, mapping)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := issSvc.RollbackContext(ctx)
+			assert.NoError(t, err)
+		}
+
+		testCases := []testingx.TestCase[ListGroupRoleMappingsRequestObject, ListGroupRoleMappingsResponseObject]{
+			{
+				Name: "Success",
+				Input: ListGroupRoleMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       issuerUUID,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListGroupRoleMappingsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					expResp := ListGroupRoleMappings200JSONResponse{
+						RoleMappings: []v1.GroupRoleMapping{
+							mapping.ToV1GroupRoleMapping(),
+						},
+					}
+
+					assert.Equal(t, expResp, result.Success)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "NotFound",
+				Input: ListGroupRoleMappingsRequestObject{
+					TenantID: tenantUUID,
+					Id:       uuid.New(),
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListGroupRoleMappingsResponseObject]) {
+					assert.ErrorIs(t, result.Err, errorNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input ListGroupRoleMappingsRequestObject) testingx.TestResult[ListGroupRoleMappingsResponseObject] {
+			resp, err := handler.ListGroupRoleMappings(ctx, input)
+
+			return testingx.TestResult[ListGroupRoleMappingsResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("DeleteGroupRoleMapping", func(t *testing.T) {
+		t.Parallel()
+		handler := apiHandler{
+			engine: issSvc,
+		}
+
+		mapping := types.GroupRoleMapping{
+			ID:       "7f1d3c52-3d0e-4b2e-9a61-0f4b8f3f3b11",
+			TenantID: tenantID,
+			IssuerID: issuerID,
+			Group:    "admins",
+			Role:     "admin",
+		}
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := issSvc.BeginContext(ctx)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			_, err = issSvc.CreateGroupRoleMapping(ctx, mapping)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := issSvc.RollbackContext(ctx)
+			assert.NoError(t, err)
+		}
+
+		testCases := []testingx.TestCase[DeleteGroupRoleMappingRequestObject, DeleteGroupRoleMappingResponseObject]{
+			{
+				Name: "Success",
+				Input: DeleteGroupRoleMappingRequestObject{
+					TenantID:  tenantUUID,
+					Id:        issuerUUID,
+					MappingID: uuid.MustParse(mapping.ID),
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteGroupRoleMappingResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					expResp := DeleteGroupRoleMapping200JSONResponse{
+						Success: true,
+					}
+
+					assert.Equal(t, expResp, result.Success)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "NotFound",
+				Input: DeleteGroupRoleMappingRequestObject{
+					TenantID:  tenantUUID,
+					Id:        issuerUUID,
+					MappingID: uuid.New(),
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteGroupRoleMappingResponseObject]) {
+					assert.ErrorIs(t, result.Err, errorNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "OtherTenant",
+				Input: DeleteGroupRoleMappingRequestObject{
+					TenantID:  otherTenantUUID,
+					Id:        issuerUUID,
+					MappingID: uuid.MustParse(mapping.ID),
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[DeleteGroupRoleMappingResponseObject]) {
+					assert.ErrorIs(t, result.Err, errorNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input DeleteGroupRoleMappingRequestObject) testingx.TestResult[DeleteGroupRoleMappingResponseObject] {
+			resp, err := handler.DeleteGroupRoleMapping(ctx, input)
+
+			return testingx.TestResult[DeleteGroupRoleMappingResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("AuditEvents", func(t *testing.T) {
+		t.Parallel()
+
+		handler := apiHandler{
+			engine: issSvc,
+		}
+
+		auditTenantUUID := uuid.MustParse("3d8f2a6c-1b4e-4c7a-9e5d-0f2b6a8c4e17")
+		auditIssuerUUID := uuid.MustParse("3d8f2a6c-1b4e-4c7a-9e5d-0f2b6a8c4e18")
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := issSvc.BeginContext(ctx)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			ctx = contextWithActor(ctx, "urn:example:user/admin")
+			ctx = context.WithValue(ctx, requestIDContextKey{}, "request-1")
+
+			iss := types.Issuer{
+				TenantID:      auditTenantUUID.String(),
+				ID:            auditIssuerUUID.String(),
+				Name:          "Audited",
+				URI:           "https://audited.example.com/",
+				ClaimMappings: mappings,
+			}
+
+			if _, err := issSvc.CreateIssuer(ctx, iss); !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := issSvc.RollbackContext(ctx)
+			assert.NoError(t, err)
+		}
+
+		newName := "Renamed"
+		newMappings := map[string]string{
+			"foo": "456",
+		}
+		badCursor := "not a cursor"
+
+		testCases := []testingx.TestCase[ListAuditEventsRequestObject, ListAuditEventsResponseObject]{
+			{
+				Name: "Success",
+				Input: ListAuditEventsRequestObject{
+					TenantID: auditTenantUUID,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListAuditEventsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(ListAuditEvents200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for list audit events response")
+					}
+
+					if !assert.Len(t, resp.AuditEvents, 1) {
+						return
+					}
+
+					event := resp.AuditEvents[0]
+
+					assert.Equal(t, v1.Update, event.Action)
+					assert.Equal(t, "urn:example:user/admin", event.Actor)
+					assert.Equal(t, "request-1", event.RequestID)
+					assert.Equal(t, types.AuditResourceIssuer, event.ResourceType)
+					assert.Equal(t, auditIssuerUUID.String(), event.ResourceID)
+
+					// Only changed fields are recorded.
+					if assert.NotNil(t, event.Before) && assert.NotNil(t, event.After) {
+						assert.Equal(t, map[string]interface{}{"name": "Audited", "claim_mappings": map[string]interface{}{"foo": "123"}}, *event.Before)
+						assert.Equal(t, map[string]interface{}{"name": "Renamed", "claim_mappings": map[string]interface{}{"foo": "456"}}, *event.After)
+					}
+
+					assert.Nil(t, resp.NextCursor)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "OtherTenant",
+				Input: ListAuditEventsRequestObject{
+					TenantID: otherTenantUUID,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListAuditEventsResponseObject]) {
+					if !assert.NoError(t, result.Err) {
+						return
+					}
+
+					resp, ok := result.Success.(ListAuditEvents200JSONResponse)
+					if !ok {
+						assert.FailNow(t, "unexpected result type for list audit events response")
+					}
+
+					assert.Empty(t, resp.AuditEvents)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "InvalidCursor",
+				Input: ListAuditEventsRequestObject{
+					TenantID: auditTenantUUID,
+					Params: v1.ListAuditEventsParams{
+						Cursor: &badCursor,
+					},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[ListAuditEventsResponseObject]) {
+					var statusErr errorWithStatus
+
+					if assert.ErrorAs(t, result.Err, &statusErr) {
+						assert.Equal(t, http.StatusBadRequest, statusErr.status)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		// Each case updates the issuer before listing events, so the update is recorded in the case's
+		// transaction.
+		runFn := func(ctx context.Context, input ListAuditEventsRequestObject) testingx.TestResult[ListAuditEventsResponseObject] {
+			updateReq := UpdateIssuerRequestObject{
+				TenantID: auditTenantUUID,
+				Id:       auditIssuerUUID,
+				Body: &v1.IssuerUpdate{
+					Name:          &newName,
+					ClaimMappings: &newMappings,
+				},
+			}
+
+			if _, err := handler.UpdateIssuer(ctx, updateReq); err != nil {
+				return testingx.TestResult[ListAuditEventsResponseObject]{
+					Err: err,
+				}
+			}
+
+			resp, err := handler.ListAuditEvents(ctx, input)
+
+			return testingx.TestResult[ListAuditEventsResponseObject]{
+				Success: resp,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("SigningKeys", func(t *testing.T) {
+		t.Parallel()
+
+		oauth2Config := &fositex.OAuth2Config{}
+
+		managedKeysConfig := fositex.ManagedKeysConfig{
+			Enabled:   true,
+			KEK:       "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+			Algorithm: jose.ES256,
+		}
+
+		manager, err := keymanager.NewManager(managedKeysConfig, issSvc, oauth2Config, zap.NewNop().Sugar())
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+
+		oauth2Config.SigningKeyManager = manager
+
+		handler := apiHandler{
+			engine: issSvc,
+			config: oauth2Config,
+		}
+
+		disabledHandler := apiHandler{
+			engine: issSvc,
+			config: &fositex.OAuth2Config{},
+		}
+
+		type input struct {
+			handler *apiHandler
+			op      func(ctx context.Context, h *apiHandler) (*v1.SigningKey, error)
+		}
+
+		generate := func(alg string) func(ctx context.Context, h *apiHandler) (*v1.SigningKey, error) {
+			return func(ctx context.Context, h *apiHandler) (*v1.SigningKey, error) {
+				req := CreateSigningKeyRequestObject{
+					Body: &v1.CreateSigningKey{},
+				}
+
+				if alg != "" {
+					req.Body.Algorithm = &alg
+				}
+
+				resp, err := h.CreateSigningKey(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+
+				out := v1.SigningKey(resp.(CreateSigningKey200JSONResponse))
+
+				return &out, nil
+			}
+		}
+
+		testCases := []testingx.TestCase[input, *v1.SigningKey]{
+			{
+				Name: "Generate",
+				Input: input{
+					handler: &handler,
+					op:      generate(""),
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*v1.SigningKey]) {
+					if !assert.NoError(t, res.Err) {
+						return
+					}
+
+					assert.Equal(t, "ES256", res.Success.Algorithm)
+					assert.Equal(t, "pending", res.Success.State)
+					assert.Equal(t, "EC", res.Success.PublicKey["kty"])
+					assert.NotContains(t, res.Success.PublicKey, "d")
+				},
+			},
+			{
+				Name: "UnsupportedAlgorithm",
+				Input: input{
+					handler: &handler,
+					op:      generate("HS256"),
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*v1.SigningKey]) {
+					assert.ErrorContains(t, res.Err, keymanager.ErrUnsupportedAlgorithm.Error())
+				},
+			},
+			{
+				Name: "PromoteNotFound",
+				Input: input{
+					handler: &handler,
+					op: func(ctx context.Context, h *apiHandler) (*v1.SigningKey, error) {
+						_, err := h.PromoteSigningKey(ctx, PromoteSigningKeyRequestObject{Kid: "missing"})
+
+						return nil, err
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*v1.SigningKey]) {
+					assert.ErrorIs(t, res.Err, errorNotFound)
+				},
+			},
+			{
+				Name: "PromoteTooSoon",
+				Input: input{
+					handler: &handler,
+					op: func(ctx context.Context, h *apiHandler) (*v1.SigningKey, error) {
+						key, err := generate("")(ctx, h)
+						if err != nil {
+							return nil, err
+						}
+
+						_, err = h.PromoteSigningKey(ctx, PromoteSigningKeyRequestObject{Kid: key.ID})
+
+						return nil, err
+					},
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*v1.SigningKey]) {
+					var statusErr errorWithStatus
+
+					if assert.ErrorAs(t, res.Err, &statusErr) {
+						assert.Equal(t, http.StatusBadRequest, statusErr.status)
+						assert.Contains(t, statusErr.message, keymanager.ErrKeyNotPublished.Error())
+					}
+				},
+			},
+			{
+				Name: "Disabled",
+				Input: input{
+					handler: &disabledHandler,
+					op:      generate(""),
+				},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*v1.SigningKey]) {
+					assert.ErrorIs(t, res.Err, errorManagedKeysDisabled)
+				},
+			},
+		}
+
+		runFn := func(ctx context.Context, in input) testingx.TestResult[*v1.SigningKey] {
+			ctx, err := issSvc.BeginContext(ctx)
+			if err != nil {
+				return testingx.TestResult[*v1.SigningKey]{Err: err}
+			}
+
+			defer issSvc.RollbackContext(ctx) //nolint:errcheck
+
+			key, err := in.op(ctx, in.handler)
+
+			return testingx.TestResult[*v1.SigningKey]{
+				Success: key,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+
+		t.Run("AuditEvents", func(t *testing.T) {
+			t.Parallel()
+
+			// Each case makes changes to signing keys, then lists the global audit events recorded for them.
+			type auditInput struct {
+				op func(ctx context.Context) error
+			}
+
+			auditRunFn := func(ctx context.Context, in auditInput) testingx.TestResult[[]v1.AuditEvent] {
+				ctx, err := issSvc.BeginContext(ctx)
+				if err != nil {
+					return testingx.TestResult[[]v1.AuditEvent]{Err: err}
+				}
+
+				defer issSvc.RollbackContext(ctx) //nolint:errcheck
+
+				ctx = contextWithActor(ctx, "urn:example:user/admin")
+
+				if err := in.op(ctx); err != nil {
+					return testingx.TestResult[[]v1.AuditEvent]{Err: err}
+				}
+
+				resp, err := handler.ListGlobalAuditEvents(ctx, ListGlobalAuditEventsRequestObject{})
+				if err != nil {
+					return testingx.TestResult[[]v1.AuditEvent]{Err: err}
+				}
+
+				return testingx.TestResult[[]v1.AuditEvent]{
+					Success: resp.(ListGlobalAuditEvents200JSONResponse).AuditEvents,
+				}
+			}
+
+			auditTestCases := []testingx.TestCase[auditInput, []v1.AuditEvent]{
+				{
+					Name: "Generate",
+					Input: auditInput{
+						op: func(ctx context.Context) error {
+							_, err := generate("")(ctx, &handler)
+
+							return err
+						},
+					},
+					CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]v1.AuditEvent]) {
+						if !assert.NoError(t, res.Err) || !assert.Len(t, res.Success, 1) {
+							return
+						}
+
+						event := res.Success[0]
+
+						assert.Equal(t, v1.Create, event.Action)
+						assert.Equal(t, types.AuditResourceSigningKey, event.ResourceType)
+						assert.Equal(t, "urn:example:user/admin", event.Actor)
+						assert.Nil(t, event.TenantID)
+						assert.Nil(t, event.Before)
+
+						if assert.NotNil(t, event.After) {
+							assert.Equal(t, "pending", (*event.After)["state"])
+						}
+					},
+				},
+				{
+					Name: "Retire",
+					Input: auditInput{
+						op: func(ctx context.Context) error {
+							key, err := generate("")(ctx, &handler)
+							if err != nil {
+								return err
+							}
+
+							_, err = handler.RetireSigningKey(ctx, RetireSigningKeyRequestObject{Kid: key.ID})
+
+							return err
+						},
+					},
+					CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]v1.AuditEvent]) {
+						if !assert.NoError(t, res.Err) || !assert.Len(t, res.Success, 2) {
+							return
+						}
+
+						event := res.Success[0]
+
+						assert.Equal(t, v1.Update, event.Action)
+						assert.Equal(t, res.Success[1].ResourceID, event.ResourceID)
+
+						// Only changed fields are recorded.
+						if assert.NotNil(t, event.Before) && assert.NotNil(t, event.After) {
+							assert.Equal(t, map[string]interface{}{"state": "pending"}, *event.Before)
+							assert.Equal(t, map[string]interface{}{"state": "retired"}, *event.After)
+						}
+					},
+				},
+			}
+
+			testingx.RunTests(context.Background(), t, auditTestCases, auditRunFn)
+		})
+	})
 }
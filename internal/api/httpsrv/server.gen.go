@@ -17,18 +17,51 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Deletes an issuer with the given ID.
-	// (DELETE /api/v1/issuers/{id})
-	DeleteIssuer(c *gin.Context, id openapi_types.UUID)
-	// Gets an issuer by ID.
-	// (GET /api/v1/issuers/{id})
-	GetIssuerByID(c *gin.Context, id openapi_types.UUID)
-	// Updates an issuer.
-	// (PATCH /api/v1/issuers/{id})
-	UpdateIssuer(c *gin.Context, id openapi_types.UUID)
+	// Lists the audit events of resources not scoped to a tenant, such as signing keys, newest first.
+	// (GET /api/v1/audit-events)
+	ListGlobalAuditEvents(c *gin.Context, params ListGlobalAuditEventsParams)
+	// Lists signing keys, oldest first.
+	// (GET /api/v1/signing-keys)
+	ListSigningKeys(c *gin.Context)
+	// Generates a new signing key. New keys are pending, so they are published before they sign tokens.
+	// (POST /api/v1/signing-keys)
+	CreateSigningKey(c *gin.Context)
+	// Promotes a pending or retiring signing key to sign tokens. The previously active key is retired from signing but stays published.
+	// (POST /api/v1/signing-keys/{kid}/promote)
+	PromoteSigningKey(c *gin.Context, kid string)
+	// Retires a signing key, removing it from the JWKS. The active signing key cannot be retired.
+	// (POST /api/v1/signing-keys/{kid}/retire)
+	RetireSigningKey(c *gin.Context, kid string)
+	// Lists a tenant's audit events, newest first.
+	// (GET /api/v1/tenants/{tenantID}/audit-events)
+	ListAuditEvents(c *gin.Context, tenantID openapi_types.UUID, params ListAuditEventsParams)
+	// Lists a tenant's issuers.
+	// (GET /api/v1/tenants/{tenantID}/issuers)
+	ListIssuers(c *gin.Context, tenantID openapi_types.UUID, params ListIssuersParams)
 	// Creates an issuer.
 	// (POST /api/v1/tenants/{tenantID}/issuers)
 	CreateIssuer(c *gin.Context, tenantID openapi_types.UUID)
+	// Deletes an issuer with the given ID.
+	// (DELETE /api/v1/tenants/{tenantID}/issuers/{id})
+	DeleteIssuer(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Gets an issuer by ID.
+	// (GET /api/v1/tenants/{tenantID}/issuers/{id})
+	GetIssuerByID(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Updates an issuer.
+	// (PATCH /api/v1/tenants/{tenantID}/issuers/{id})
+	UpdateIssuer(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Evaluates claim mappings for an issuer against sample claims without performing a token exchange.
+	// (POST /api/v1/tenants/{tenantID}/issuers/{id}/claim-mappings/evaluate)
+	EvaluateClaimMappings(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Lists the group to role mappings for an issuer.
+	// (GET /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings)
+	ListGroupRoleMappings(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Creates a mapping from an upstream group to a role for an issuer.
+	// (POST /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings)
+	CreateGroupRoleMapping(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID)
+	// Deletes a group to role mapping with the given ID.
+	// (DELETE /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings/{mappingID})
+	DeleteGroupRoleMapping(c *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID, mappingID openapi_types.UUID)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -40,11 +73,294 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// ListGlobalAuditEvents operation middleware
+func (siw *ServerInterfaceWrapper) ListGlobalAuditEvents(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListGlobalAuditEventsParams
+
+	// ------------- Optional query parameter "resource_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resource_id", c.Request.URL.Query(), &params.ResourceId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resource_id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListGlobalAuditEvents(c, params)
+}
+
+// ListSigningKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListSigningKeys(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListSigningKeys(c)
+}
+
+// CreateSigningKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateSigningKey(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateSigningKey(c)
+}
+
+// PromoteSigningKey operation middleware
+func (siw *ServerInterfaceWrapper) PromoteSigningKey(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "kid" -------------
+	var kid string
+
+	err = runtime.BindStyledParameter("simple", false, "kid", c.Param("kid"), &kid)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter kid: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PromoteSigningKey(c, kid)
+}
+
+// RetireSigningKey operation middleware
+func (siw *ServerInterfaceWrapper) RetireSigningKey(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "kid" -------------
+	var kid string
+
+	err = runtime.BindStyledParameter("simple", false, "kid", c.Param("kid"), &kid)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter kid: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RetireSigningKey(c, kid)
+}
+
+// ListAuditEvents operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditEvents(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEventsParams
+
+	// ------------- Optional query parameter "resource_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resource_id", c.Request.URL.Query(), &params.ResourceId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resource_id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListAuditEvents(c, tenantID, params)
+}
+
+// ListIssuers operation middleware
+func (siw *ServerInterfaceWrapper) ListIssuers(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListIssuersParams
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", c.Request.URL.Query(), &params.Name)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "uri" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "uri", c.Request.URL.Query(), &params.Uri)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uri: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", c.Request.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sort: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListIssuers(c, tenantID, params)
+}
+
+// CreateIssuer operation middleware
+func (siw *ServerInterfaceWrapper) CreateIssuer(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateIssuer(c, tenantID)
+}
+
 // DeleteIssuer operation middleware
 func (siw *ServerInterfaceWrapper) DeleteIssuer(c *gin.Context) {
 
 	var err error
 
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
 
@@ -54,6 +370,8 @@ func (siw *ServerInterfaceWrapper) DeleteIssuer(c *gin.Context) {
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -61,7 +379,7 @@ func (siw *ServerInterfaceWrapper) DeleteIssuer(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.DeleteIssuer(c, id)
+	siw.Handler.DeleteIssuer(c, tenantID, id)
 }
 
 // GetIssuerByID operation middleware
@@ -69,6 +387,15 @@ func (siw *ServerInterfaceWrapper) GetIssuerByID(c *gin.Context) {
 
 	var err error
 
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
 
@@ -78,6 +405,8 @@ func (siw *ServerInterfaceWrapper) GetIssuerByID(c *gin.Context) {
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -85,7 +414,7 @@ func (siw *ServerInterfaceWrapper) GetIssuerByID(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetIssuerByID(c, id)
+	siw.Handler.GetIssuerByID(c, tenantID, id)
 }
 
 // UpdateIssuer operation middleware
@@ -93,6 +422,15 @@ func (siw *ServerInterfaceWrapper) UpdateIssuer(c *gin.Context) {
 
 	var err error
 
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
 
@@ -102,6 +440,8 @@ func (siw *ServerInterfaceWrapper) UpdateIssuer(c *gin.Context) {
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -109,11 +449,11 @@ func (siw *ServerInterfaceWrapper) UpdateIssuer(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.UpdateIssuer(c, id)
+	siw.Handler.UpdateIssuer(c, tenantID, id)
 }
 
-// CreateIssuer operation middleware
-func (siw *ServerInterfaceWrapper) CreateIssuer(c *gin.Context) {
+// EvaluateClaimMappings operation middleware
+func (siw *ServerInterfaceWrapper) EvaluateClaimMappings(c *gin.Context) {
 
 	var err error
 
@@ -126,6 +466,17 @@ func (siw *ServerInterfaceWrapper) CreateIssuer(c *gin.Context) {
 		return
 	}
 
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -133,145 +484,998 @@ func (siw *ServerInterfaceWrapper) CreateIssuer(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.CreateIssuer(c, tenantID)
+	siw.Handler.EvaluateClaimMappings(c, tenantID, id)
 }
 
-// GinServerOptions provides options for the Gin server.
-type GinServerOptions struct {
-	BaseURL      string
-	Middlewares  []MiddlewareFunc
-	ErrorHandler func(*gin.Context, error, int)
+// ListGroupRoleMappings operation middleware
+func (siw *ServerInterfaceWrapper) ListGroupRoleMappings(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListGroupRoleMappings(c, tenantID, id)
+}
+
+// CreateGroupRoleMapping operation middleware
+func (siw *ServerInterfaceWrapper) CreateGroupRoleMapping(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateGroupRoleMapping(c, tenantID, id)
+}
+
+// DeleteGroupRoleMapping operation middleware
+func (siw *ServerInterfaceWrapper) DeleteGroupRoleMapping(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "tenantID" -------------
+	var tenantID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "tenantID", c.Param("tenantID"), &tenantID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tenantID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "mappingID" -------------
+	var mappingID openapi_types.UUID
+
+	err = runtime.BindStyledParameter("simple", false, "mappingID", c.Param("mappingID"), &mappingID)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter mappingID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteGroupRoleMapping(c, tenantID, id, mappingID)
+}
+
+// GinServerOptions provides options for the Gin server.
+type GinServerOptions struct {
+	BaseURL      string
+	Middlewares  []MiddlewareFunc
+	ErrorHandler func(*gin.Context, error, int)
+}
+
+// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
+func RegisterHandlers(router gin.IRouter, si ServerInterface) {
+	RegisterHandlersWithOptions(router, si, GinServerOptions{})
+}
+
+// RegisterHandlersWithOptions creates http.Handler with additional options
+func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
+	errorHandler := options.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(c *gin.Context, err error, statusCode int) {
+			c.JSON(statusCode, gin.H{"msg": err.Error()})
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandler:       errorHandler,
+	}
+
+	router.GET(options.BaseURL+"/api/v1/audit-events", wrapper.ListGlobalAuditEvents)
+	router.GET(options.BaseURL+"/api/v1/signing-keys", wrapper.ListSigningKeys)
+	router.POST(options.BaseURL+"/api/v1/signing-keys", wrapper.CreateSigningKey)
+	router.POST(options.BaseURL+"/api/v1/signing-keys/:kid/promote", wrapper.PromoteSigningKey)
+	router.POST(options.BaseURL+"/api/v1/signing-keys/:kid/retire", wrapper.RetireSigningKey)
+	router.GET(options.BaseURL+"/api/v1/tenants/:tenantID/audit-events", wrapper.ListAuditEvents)
+	router.GET(options.BaseURL+"/api/v1/tenants/:tenantID/issuers", wrapper.ListIssuers)
+	router.POST(options.BaseURL+"/api/v1/tenants/:tenantID/issuers", wrapper.CreateIssuer)
+	router.DELETE(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id", wrapper.DeleteIssuer)
+	router.GET(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id", wrapper.GetIssuerByID)
+	router.PATCH(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id", wrapper.UpdateIssuer)
+	router.POST(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id/claim-mappings/evaluate", wrapper.EvaluateClaimMappings)
+	router.GET(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id/role-mappings", wrapper.ListGroupRoleMappings)
+	router.POST(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id/role-mappings", wrapper.CreateGroupRoleMapping)
+	router.DELETE(options.BaseURL+"/api/v1/tenants/:tenantID/issuers/:id/role-mappings/:mappingID", wrapper.DeleteGroupRoleMapping)
+}
+
+type ForbiddenJSONResponse ErrorResponse
+
+type UnauthorizedJSONResponse ErrorResponse
+
+type ListGlobalAuditEventsRequestObject struct {
+	Params ListGlobalAuditEventsParams
+}
+
+type ListGlobalAuditEventsResponseObject interface {
+	VisitListGlobalAuditEventsResponse(w http.ResponseWriter) error
+}
+
+type ListGlobalAuditEvents200JSONResponse AuditEventList
+
+func (response ListGlobalAuditEvents200JSONResponse) VisitListGlobalAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListGlobalAuditEvents401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListGlobalAuditEvents401JSONResponse) VisitListGlobalAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListGlobalAuditEvents403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListGlobalAuditEvents403JSONResponse) VisitListGlobalAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListSigningKeysRequestObject struct {
+}
+
+type ListSigningKeysResponseObject interface {
+	VisitListSigningKeysResponse(w http.ResponseWriter) error
+}
+
+type ListSigningKeys200JSONResponse SigningKeyList
+
+func (response ListSigningKeys200JSONResponse) VisitListSigningKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListSigningKeys401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListSigningKeys401JSONResponse) VisitListSigningKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListSigningKeys403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListSigningKeys403JSONResponse) VisitListSigningKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateSigningKeyRequestObject struct {
+	Body *CreateSigningKeyJSONRequestBody
+}
+
+type CreateSigningKeyResponseObject interface {
+	VisitCreateSigningKeyResponse(w http.ResponseWriter) error
+}
+
+type CreateSigningKey200JSONResponse SigningKey
+
+func (response CreateSigningKey200JSONResponse) VisitCreateSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateSigningKey401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response CreateSigningKey401JSONResponse) VisitCreateSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateSigningKey403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response CreateSigningKey403JSONResponse) VisitCreateSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PromoteSigningKeyRequestObject struct {
+	Kid string `json:"kid"`
+}
+
+type PromoteSigningKeyResponseObject interface {
+	VisitPromoteSigningKeyResponse(w http.ResponseWriter) error
+}
+
+type PromoteSigningKey200JSONResponse SigningKey
+
+func (response PromoteSigningKey200JSONResponse) VisitPromoteSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PromoteSigningKey401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response PromoteSigningKey401JSONResponse) VisitPromoteSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PromoteSigningKey403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response PromoteSigningKey403JSONResponse) VisitPromoteSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RetireSigningKeyRequestObject struct {
+	Kid string `json:"kid"`
+}
+
+type RetireSigningKeyResponseObject interface {
+	VisitRetireSigningKeyResponse(w http.ResponseWriter) error
+}
+
+type RetireSigningKey200JSONResponse SigningKey
+
+func (response RetireSigningKey200JSONResponse) VisitRetireSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RetireSigningKey401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response RetireSigningKey401JSONResponse) VisitRetireSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RetireSigningKey403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response RetireSigningKey403JSONResponse) VisitRetireSigningKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAuditEventsRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Params   ListAuditEventsParams
+}
+
+type ListAuditEventsResponseObject interface {
+	VisitListAuditEventsResponse(w http.ResponseWriter) error
+}
+
+type ListAuditEvents200JSONResponse AuditEventList
+
+func (response ListAuditEvents200JSONResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAuditEvents401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListAuditEvents401JSONResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAuditEvents403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListAuditEvents403JSONResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListIssuersRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Params   ListIssuersParams
+}
+
+type ListIssuersResponseObject interface {
+	VisitListIssuersResponse(w http.ResponseWriter) error
+}
+
+type ListIssuers200JSONResponse IssuerList
+
+func (response ListIssuers200JSONResponse) VisitListIssuersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListIssuers401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListIssuers401JSONResponse) VisitListIssuersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListIssuers403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListIssuers403JSONResponse) VisitListIssuersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateIssuerRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Body     *CreateIssuerJSONRequestBody
+}
+
+type CreateIssuerResponseObject interface {
+	VisitCreateIssuerResponse(w http.ResponseWriter) error
+}
+
+type CreateIssuer200JSONResponse Issuer
+
+func (response CreateIssuer200JSONResponse) VisitCreateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateIssuer401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response CreateIssuer401JSONResponse) VisitCreateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateIssuer403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response CreateIssuer403JSONResponse) VisitCreateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIssuerRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+}
+
+type DeleteIssuerResponseObject interface {
+	VisitDeleteIssuerResponse(w http.ResponseWriter) error
+}
+
+type DeleteIssuer200JSONResponse DeleteResponse
+
+func (response DeleteIssuer200JSONResponse) VisitDeleteIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIssuer401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response DeleteIssuer401JSONResponse) VisitDeleteIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIssuer403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response DeleteIssuer403JSONResponse) VisitDeleteIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIssuerByIDRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+}
+
+type GetIssuerByIDResponseObject interface {
+	VisitGetIssuerByIDResponse(w http.ResponseWriter) error
+}
+
+type GetIssuerByID200JSONResponse Issuer
+
+func (response GetIssuerByID200JSONResponse) VisitGetIssuerByIDResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIssuerByID401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response GetIssuerByID401JSONResponse) VisitGetIssuerByIDResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIssuerByID403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response GetIssuerByID403JSONResponse) VisitGetIssuerByIDResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateIssuerRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+	Body     *UpdateIssuerJSONRequestBody
+}
+
+type UpdateIssuerResponseObject interface {
+	VisitUpdateIssuerResponse(w http.ResponseWriter) error
+}
+
+type UpdateIssuer200JSONResponse Issuer
+
+func (response UpdateIssuer200JSONResponse) VisitUpdateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateIssuer401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response UpdateIssuer401JSONResponse) VisitUpdateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateIssuer403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response UpdateIssuer403JSONResponse) VisitUpdateIssuerResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EvaluateClaimMappingsRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+	Body     *EvaluateClaimMappingsJSONRequestBody
+}
+
+type EvaluateClaimMappingsResponseObject interface {
+	VisitEvaluateClaimMappingsResponse(w http.ResponseWriter) error
+}
+
+type EvaluateClaimMappings200JSONResponse ClaimMappingEvaluation
+
+func (response EvaluateClaimMappings200JSONResponse) VisitEvaluateClaimMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EvaluateClaimMappings401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response EvaluateClaimMappings401JSONResponse) VisitEvaluateClaimMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EvaluateClaimMappings403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response EvaluateClaimMappings403JSONResponse) VisitEvaluateClaimMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListGroupRoleMappingsRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+}
+
+type ListGroupRoleMappingsResponseObject interface {
+	VisitListGroupRoleMappingsResponse(w http.ResponseWriter) error
+}
+
+type ListGroupRoleMappings200JSONResponse GroupRoleMappingList
+
+func (response ListGroupRoleMappings200JSONResponse) VisitListGroupRoleMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListGroupRoleMappings401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListGroupRoleMappings401JSONResponse) VisitListGroupRoleMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListGroupRoleMappings403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListGroupRoleMappings403JSONResponse) VisitListGroupRoleMappingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateGroupRoleMappingRequestObject struct {
+	TenantID openapi_types.UUID `json:"tenantID"`
+	Id       openapi_types.UUID `json:"id"`
+	Body     *CreateGroupRoleMappingJSONRequestBody
+}
+
+type CreateGroupRoleMappingResponseObject interface {
+	VisitCreateGroupRoleMappingResponse(w http.ResponseWriter) error
+}
+
+type CreateGroupRoleMapping200JSONResponse GroupRoleMapping
+
+func (response CreateGroupRoleMapping200JSONResponse) VisitCreateGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateGroupRoleMapping401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response CreateGroupRoleMapping401JSONResponse) VisitCreateGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateGroupRoleMapping403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response CreateGroupRoleMapping403JSONResponse) VisitCreateGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteGroupRoleMappingRequestObject struct {
+	TenantID  openapi_types.UUID `json:"tenantID"`
+	Id        openapi_types.UUID `json:"id"`
+	MappingID openapi_types.UUID `json:"mappingID"`
+}
+
+type DeleteGroupRoleMappingResponseObject interface {
+	VisitDeleteGroupRoleMappingResponse(w http.ResponseWriter) error
+}
+
+type DeleteGroupRoleMapping200JSONResponse DeleteResponse
+
+func (response DeleteGroupRoleMapping200JSONResponse) VisitDeleteGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteGroupRoleMapping401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response DeleteGroupRoleMapping401JSONResponse) VisitDeleteGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteGroupRoleMapping403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response DeleteGroupRoleMapping403JSONResponse) VisitDeleteGroupRoleMappingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Lists the audit events of resources not scoped to a tenant, such as signing keys, newest first.
+	// (GET /api/v1/audit-events)
+	ListGlobalAuditEvents(ctx context.Context, request ListGlobalAuditEventsRequestObject) (ListGlobalAuditEventsResponseObject, error)
+	// Lists signing keys, oldest first.
+	// (GET /api/v1/signing-keys)
+	ListSigningKeys(ctx context.Context, request ListSigningKeysRequestObject) (ListSigningKeysResponseObject, error)
+	// Generates a new signing key. New keys are pending, so they are published before they sign tokens.
+	// (POST /api/v1/signing-keys)
+	CreateSigningKey(ctx context.Context, request CreateSigningKeyRequestObject) (CreateSigningKeyResponseObject, error)
+	// Promotes a pending or retiring signing key to sign tokens. The previously active key is retired from signing but stays published.
+	// (POST /api/v1/signing-keys/{kid}/promote)
+	PromoteSigningKey(ctx context.Context, request PromoteSigningKeyRequestObject) (PromoteSigningKeyResponseObject, error)
+	// Retires a signing key, removing it from the JWKS. The active signing key cannot be retired.
+	// (POST /api/v1/signing-keys/{kid}/retire)
+	RetireSigningKey(ctx context.Context, request RetireSigningKeyRequestObject) (RetireSigningKeyResponseObject, error)
+	// Lists a tenant's audit events, newest first.
+	// (GET /api/v1/tenants/{tenantID}/audit-events)
+	ListAuditEvents(ctx context.Context, request ListAuditEventsRequestObject) (ListAuditEventsResponseObject, error)
+	// Lists a tenant's issuers.
+	// (GET /api/v1/tenants/{tenantID}/issuers)
+	ListIssuers(ctx context.Context, request ListIssuersRequestObject) (ListIssuersResponseObject, error)
+	// Creates an issuer.
+	// (POST /api/v1/tenants/{tenantID}/issuers)
+	CreateIssuer(ctx context.Context, request CreateIssuerRequestObject) (CreateIssuerResponseObject, error)
+	// Deletes an issuer with the given ID.
+	// (DELETE /api/v1/tenants/{tenantID}/issuers/{id})
+	DeleteIssuer(ctx context.Context, request DeleteIssuerRequestObject) (DeleteIssuerResponseObject, error)
+	// Gets an issuer by ID.
+	// (GET /api/v1/tenants/{tenantID}/issuers/{id})
+	GetIssuerByID(ctx context.Context, request GetIssuerByIDRequestObject) (GetIssuerByIDResponseObject, error)
+	// Updates an issuer.
+	// (PATCH /api/v1/tenants/{tenantID}/issuers/{id})
+	UpdateIssuer(ctx context.Context, request UpdateIssuerRequestObject) (UpdateIssuerResponseObject, error)
+	// Evaluates claim mappings for an issuer against sample claims without performing a token exchange.
+	// (POST /api/v1/tenants/{tenantID}/issuers/{id}/claim-mappings/evaluate)
+	EvaluateClaimMappings(ctx context.Context, request EvaluateClaimMappingsRequestObject) (EvaluateClaimMappingsResponseObject, error)
+	// Lists the group to role mappings for an issuer.
+	// (GET /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings)
+	ListGroupRoleMappings(ctx context.Context, request ListGroupRoleMappingsRequestObject) (ListGroupRoleMappingsResponseObject, error)
+	// Creates a mapping from an upstream group to a role for an issuer.
+	// (POST /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings)
+	CreateGroupRoleMapping(ctx context.Context, request CreateGroupRoleMappingRequestObject) (CreateGroupRoleMappingResponseObject, error)
+	// Deletes a group to role mapping with the given ID.
+	// (DELETE /api/v1/tenants/{tenantID}/issuers/{id}/role-mappings/{mappingID})
+	DeleteGroupRoleMapping(ctx context.Context, request DeleteGroupRoleMappingRequestObject) (DeleteGroupRoleMappingResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx *gin.Context, args interface{}) (interface{}, error)
+
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+}
+
+// ListGlobalAuditEvents operation middleware
+func (sh *strictHandler) ListGlobalAuditEvents(ctx *gin.Context, params ListGlobalAuditEventsParams) {
+	var request ListGlobalAuditEventsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListGlobalAuditEvents(ctx, request.(ListGlobalAuditEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListGlobalAuditEvents")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(ListGlobalAuditEventsResponseObject); ok {
+		if err := validResponse.VisitListGlobalAuditEventsResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ListSigningKeys operation middleware
+func (sh *strictHandler) ListSigningKeys(ctx *gin.Context) {
+	var request ListSigningKeysRequestObject
+
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListSigningKeys(ctx, request.(ListSigningKeysRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListSigningKeys")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(ListSigningKeysResponseObject); ok {
+		if err := validResponse.VisitListSigningKeysResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
-func RegisterHandlers(router gin.IRouter, si ServerInterface) {
-	RegisterHandlersWithOptions(router, si, GinServerOptions{})
-}
+// CreateSigningKey operation middleware
+func (sh *strictHandler) CreateSigningKey(ctx *gin.Context) {
+	var request CreateSigningKeyRequestObject
 
-// RegisterHandlersWithOptions creates http.Handler with additional options
-func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
-	errorHandler := options.ErrorHandler
-	if errorHandler == nil {
-		errorHandler = func(c *gin.Context, err error, statusCode int) {
-			c.JSON(statusCode, gin.H{"msg": err.Error()})
-		}
+	var body CreateSigningKeyJSONRequestBody
+	if err := ctx.ShouldBind(&body); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		ctx.Error(err)
+		return
 	}
+	request.Body = &body
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandler:       errorHandler,
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateSigningKey(ctx, request.(CreateSigningKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateSigningKey")
 	}
 
-	router.DELETE(options.BaseURL+"/api/v1/issuers/:id", wrapper.DeleteIssuer)
-	router.GET(options.BaseURL+"/api/v1/issuers/:id", wrapper.GetIssuerByID)
-	router.PATCH(options.BaseURL+"/api/v1/issuers/:id", wrapper.UpdateIssuer)
-	router.POST(options.BaseURL+"/api/v1/tenants/:tenantID/issuers", wrapper.CreateIssuer)
-}
+	response, err := handler(ctx, request)
 
-type DeleteIssuerRequestObject struct {
-	Id openapi_types.UUID `json:"id"`
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(CreateSigningKeyResponseObject); ok {
+		if err := validResponse.VisitCreateSigningKeyResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type DeleteIssuerResponseObject interface {
-	VisitDeleteIssuerResponse(w http.ResponseWriter) error
-}
+// PromoteSigningKey operation middleware
+func (sh *strictHandler) PromoteSigningKey(ctx *gin.Context, kid string) {
+	var request PromoteSigningKeyRequestObject
 
-type DeleteIssuer200JSONResponse DeleteResponse
+	request.Kid = kid
 
-func (response DeleteIssuer200JSONResponse) VisitDeleteIssuerResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PromoteSigningKey(ctx, request.(PromoteSigningKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PromoteSigningKey")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(ctx, request)
 
-type GetIssuerByIDRequestObject struct {
-	Id openapi_types.UUID `json:"id"`
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(PromoteSigningKeyResponseObject); ok {
+		if err := validResponse.VisitPromoteSigningKeyResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type GetIssuerByIDResponseObject interface {
-	VisitGetIssuerByIDResponse(w http.ResponseWriter) error
-}
+// RetireSigningKey operation middleware
+func (sh *strictHandler) RetireSigningKey(ctx *gin.Context, kid string) {
+	var request RetireSigningKeyRequestObject
 
-type GetIssuerByID200JSONResponse Issuer
+	request.Kid = kid
 
-func (response GetIssuerByID200JSONResponse) VisitGetIssuerByIDResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.RetireSigningKey(ctx, request.(RetireSigningKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RetireSigningKey")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(ctx, request)
 
-type UpdateIssuerRequestObject struct {
-	Id   openapi_types.UUID `json:"id"`
-	Body *UpdateIssuerJSONRequestBody
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(RetireSigningKeyResponseObject); ok {
+		if err := validResponse.VisitRetireSigningKeyResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type UpdateIssuerResponseObject interface {
-	VisitUpdateIssuerResponse(w http.ResponseWriter) error
-}
+// ListAuditEvents operation middleware
+func (sh *strictHandler) ListAuditEvents(ctx *gin.Context, tenantID openapi_types.UUID, params ListAuditEventsParams) {
+	var request ListAuditEventsRequestObject
 
-type UpdateIssuer200JSONResponse Issuer
+	request.TenantID = tenantID
+	request.Params = params
 
-func (response UpdateIssuer200JSONResponse) VisitUpdateIssuerResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAuditEvents(ctx, request.(ListAuditEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAuditEvents")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(ctx, request)
 
-type CreateIssuerRequestObject struct {
-	TenantID openapi_types.UUID `json:"tenantID"`
-	Body     *CreateIssuerJSONRequestBody
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(ListAuditEventsResponseObject); ok {
+		if err := validResponse.VisitListAuditEventsResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type CreateIssuerResponseObject interface {
-	VisitCreateIssuerResponse(w http.ResponseWriter) error
-}
+// ListIssuers operation middleware
+func (sh *strictHandler) ListIssuers(ctx *gin.Context, tenantID openapi_types.UUID, params ListIssuersParams) {
+	var request ListIssuersRequestObject
 
-type CreateIssuer200JSONResponse Issuer
+	request.TenantID = tenantID
+	request.Params = params
 
-func (response CreateIssuer200JSONResponse) VisitCreateIssuerResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListIssuers(ctx, request.(ListIssuersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListIssuers")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(ctx, request)
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Deletes an issuer with the given ID.
-	// (DELETE /api/v1/issuers/{id})
-	DeleteIssuer(ctx context.Context, request DeleteIssuerRequestObject) (DeleteIssuerResponseObject, error)
-	// Gets an issuer by ID.
-	// (GET /api/v1/issuers/{id})
-	GetIssuerByID(ctx context.Context, request GetIssuerByIDRequestObject) (GetIssuerByIDResponseObject, error)
-	// Updates an issuer.
-	// (PATCH /api/v1/issuers/{id})
-	UpdateIssuer(ctx context.Context, request UpdateIssuerRequestObject) (UpdateIssuerResponseObject, error)
-	// Creates an issuer.
-	// (POST /api/v1/tenants/{tenantID}/issuers)
-	CreateIssuer(ctx context.Context, request CreateIssuerRequestObject) (CreateIssuerResponseObject, error)
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(ListIssuersResponseObject); ok {
+		if err := validResponse.VisitListIssuersResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc func(ctx *gin.Context, args interface{}) (interface{}, error)
+// CreateIssuer operation middleware
+func (sh *strictHandler) CreateIssuer(ctx *gin.Context, tenantID openapi_types.UUID) {
+	var request CreateIssuerRequestObject
 
-type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+	request.TenantID = tenantID
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares}
-}
+	var body CreateIssuerJSONRequestBody
+	if err := ctx.ShouldBind(&body); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		ctx.Error(err)
+		return
+	}
+	request.Body = &body
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateIssuer(ctx, request.(CreateIssuerRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateIssuer")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(CreateIssuerResponseObject); ok {
+		if err := validResponse.VisitCreateIssuerResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
 // DeleteIssuer operation middleware
-func (sh *strictHandler) DeleteIssuer(ctx *gin.Context, id openapi_types.UUID) {
+func (sh *strictHandler) DeleteIssuer(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
 	var request DeleteIssuerRequestObject
 
+	request.TenantID = tenantID
 	request.Id = id
 
 	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
@@ -295,9 +1499,10 @@ func (sh *strictHandler) DeleteIssuer(ctx *gin.Context, id openapi_types.UUID) {
 }
 
 // GetIssuerByID operation middleware
-func (sh *strictHandler) GetIssuerByID(ctx *gin.Context, id openapi_types.UUID) {
+func (sh *strictHandler) GetIssuerByID(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
 	var request GetIssuerByIDRequestObject
 
+	request.TenantID = tenantID
 	request.Id = id
 
 	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
@@ -321,9 +1526,10 @@ func (sh *strictHandler) GetIssuerByID(ctx *gin.Context, id openapi_types.UUID)
 }
 
 // UpdateIssuer operation middleware
-func (sh *strictHandler) UpdateIssuer(ctx *gin.Context, id openapi_types.UUID) {
+func (sh *strictHandler) UpdateIssuer(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
 	var request UpdateIssuerRequestObject
 
+	request.TenantID = tenantID
 	request.Id = id
 
 	var body UpdateIssuerJSONRequestBody
@@ -354,13 +1560,14 @@ func (sh *strictHandler) UpdateIssuer(ctx *gin.Context, id openapi_types.UUID) {
 	}
 }
 
-// CreateIssuer operation middleware
-func (sh *strictHandler) CreateIssuer(ctx *gin.Context, tenantID openapi_types.UUID) {
-	var request CreateIssuerRequestObject
+// EvaluateClaimMappings operation middleware
+func (sh *strictHandler) EvaluateClaimMappings(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
+	var request EvaluateClaimMappingsRequestObject
 
 	request.TenantID = tenantID
+	request.Id = id
 
-	var body CreateIssuerJSONRequestBody
+	var body EvaluateClaimMappingsJSONRequestBody
 	if err := ctx.ShouldBind(&body); err != nil {
 		ctx.Status(http.StatusBadRequest)
 		ctx.Error(err)
@@ -369,18 +1576,108 @@ func (sh *strictHandler) CreateIssuer(ctx *gin.Context, tenantID openapi_types.U
 	request.Body = &body
 
 	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
-		return sh.ssi.CreateIssuer(ctx, request.(CreateIssuerRequestObject))
+		return sh.ssi.EvaluateClaimMappings(ctx, request.(EvaluateClaimMappingsRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "CreateIssuer")
+		handler = middleware(handler, "EvaluateClaimMappings")
 	}
 
 	response, err := handler(ctx, request)
 
 	if err != nil {
 		ctx.Error(err)
-	} else if validResponse, ok := response.(CreateIssuerResponseObject); ok {
-		if err := validResponse.VisitCreateIssuerResponse(ctx.Writer); err != nil {
+	} else if validResponse, ok := response.(EvaluateClaimMappingsResponseObject); ok {
+		if err := validResponse.VisitEvaluateClaimMappingsResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ListGroupRoleMappings operation middleware
+func (sh *strictHandler) ListGroupRoleMappings(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
+	var request ListGroupRoleMappingsRequestObject
+
+	request.TenantID = tenantID
+	request.Id = id
+
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListGroupRoleMappings(ctx, request.(ListGroupRoleMappingsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListGroupRoleMappings")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(ListGroupRoleMappingsResponseObject); ok {
+		if err := validResponse.VisitListGroupRoleMappingsResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreateGroupRoleMapping operation middleware
+func (sh *strictHandler) CreateGroupRoleMapping(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID) {
+	var request CreateGroupRoleMappingRequestObject
+
+	request.TenantID = tenantID
+	request.Id = id
+
+	var body CreateGroupRoleMappingJSONRequestBody
+	if err := ctx.ShouldBind(&body); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		ctx.Error(err)
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateGroupRoleMapping(ctx, request.(CreateGroupRoleMappingRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateGroupRoleMapping")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(CreateGroupRoleMappingResponseObject); ok {
+		if err := validResponse.VisitCreateGroupRoleMappingResponse(ctx.Writer); err != nil {
+			ctx.Error(err)
+		}
+	} else if response != nil {
+		ctx.Error(fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteGroupRoleMapping operation middleware
+func (sh *strictHandler) DeleteGroupRoleMapping(ctx *gin.Context, tenantID openapi_types.UUID, id openapi_types.UUID, mappingID openapi_types.UUID) {
+	var request DeleteGroupRoleMappingRequestObject
+
+	request.TenantID = tenantID
+	request.Id = id
+	request.MappingID = mappingID
+
+	handler := func(ctx *gin.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteGroupRoleMapping(ctx, request.(DeleteGroupRoleMappingRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteGroupRoleMapping")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		ctx.Error(err)
+	} else if validResponse, ok := response.(DeleteGroupRoleMappingResponseObject); ok {
+		if err := validResponse.VisitDeleteGroupRoleMappingResponse(ctx.Writer); err != nil {
 			ctx.Error(err)
 		}
 	} else if response != nil {
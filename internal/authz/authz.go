@@ -0,0 +1,106 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// ActionIssuersRead is the scope and action required to read issuers and their mappings.
+	ActionIssuersRead = "identity-api:issuers:read"
+	// ActionIssuersWrite is the scope and action required to create, update and delete issuers and
+	// their mappings.
+	ActionIssuersWrite = "identity-api:issuers:write"
+	// ActionSigningKeysRead is the scope and action required to list signing keys.
+	ActionSigningKeysRead = "identity-api:signing-keys:read"
+	// ActionSigningKeysWrite is the scope and action required to generate, promote and retire signing
+	// keys.
+	ActionSigningKeysWrite = "identity-api:signing-keys:write"
+	// ActionAuditEventsRead is the scope and action required to list audit events.
+	ActionAuditEventsRead = "identity-api:audit-events:read"
+
+	// DefaultScopesClaim is the claim scopes are read from by default. Tokens issued by identity-api
+	// list their granted scopes in the scp claim.
+	DefaultScopesClaim = "scp"
+	// DefaultTimeout is how long permissions checks may take by default.
+	DefaultTimeout = 5 * time.Second
+
+	// Wildcard matches any subject, tenant or action in a policy rule.
+	Wildcard = "*"
+)
+
+var (
+	// ErrDenied is returned when the caller is not allowed to perform an operation.
+	ErrDenied = errors.New("permission denied")
+
+	// ErrPermissionsCheck is returned when a permissions check could not be completed.
+	ErrPermissionsCheck = errors.New("permissions check failed")
+)
+
+// Config represents the configuration of management API authentication and authorization.
+type Config struct {
+	// Disabled turns off authentication and authorization of the management API. It should only be
+	// set for local development.
+	Disabled bool
+	// Audience is the audience tokens must be issued for. Defaults to the management API's URL under
+	// the issuer, i.e. <issuer>/api/v1.
+	Audience string
+	// ScopesClaim is the claim scopes are read from. Defaults to DefaultScopesClaim.
+	ScopesClaim string
+	// Policy lists the rules of the static policy. It is used when no PermissionsURL is set.
+	Policy []PolicyRule
+	// PermissionsURL is the URL of a permissions-check endpoint, which is used in place of Policy.
+	PermissionsURL string
+	// Timeout is how long permissions checks may take. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// PolicyRule represents a rule of a static policy, allowing subjects to perform actions on tenants.
+type PolicyRule struct {
+	// Subjects lists the subjects the rule applies to.
+	Subjects []string
+	// Tenants lists the tenants the rule applies to. Operations which are not scoped to a tenant, such
+	// as managing signing keys, are only allowed by rules for the Wildcard tenant.
+	Tenants []string
+	// Actions lists the allowed actions.
+	Actions []string
+}
+
+// Request represents a request to perform an operation.
+type Request struct {
+	// Subject is the subject of the caller's token.
+	Subject string
+	// Token is the caller's token.
+	Token string
+	// Action is the action the operation performs.
+	Action string
+	// TenantID is the ID of the tenant the operation acts on, or empty if it is not scoped to a tenant.
+	TenantID string
+}
+
+// Authorizer represents a decision point for management API operations.
+type Authorizer interface {
+	// Authorize returns nil if the request is allowed, or an error wrapping ErrDenied if it is not.
+	Authorize(ctx context.Context, req Request) error
+}
+
+// NewAuthorizer creates the Authorizer for the given config: an HTTPAuthorizer if a PermissionsURL is
+// set, or else a StaticAuthorizer for the configured policy.
+func NewAuthorizer(config Config) Authorizer {
+	if config.PermissionsURL == "" {
+		return NewStaticAuthorizer(config.Policy)
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	return NewHTTPAuthorizer(config.PermissionsURL, client)
+}
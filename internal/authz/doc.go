@@ -0,0 +1,2 @@
+// Package authz decides whether callers of the management API may perform the operations they request.
+package authz
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// permissionsCheck represents the body of a permissions-check request.
+type permissionsCheck struct {
+	Subject  string `json:"subject"`
+	Action   string `json:"action"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// HTTPAuthorizer is an Authorizer which delegates decisions to a permissions-check endpoint.
+type HTTPAuthorizer struct {
+	url    string
+	client *http.Client
+}
+
+// implement the Authorizer interface
+var _ Authorizer = (*HTTPAuthorizer)(nil)
+
+// NewHTTPAuthorizer creates an HTTPAuthorizer which checks permissions against the given URL.
+func NewHTTPAuthorizer(url string, client *http.Client) *HTTPAuthorizer {
+	return &HTTPAuthorizer{
+		url:    url,
+		client: client,
+	}
+}
+
+// Authorize posts the request's subject, action and tenant to the permissions-check endpoint, with the
+// caller's token as a bearer token. Any 2xx response allows the request, and 401 or 403 responses deny
+// it.
+func (a *HTTPAuthorizer) Authorize(ctx context.Context, req Request) error {
+	body, err := json.Marshal(permissionsCheck{
+		Subject:  req.Subject,
+		Action:   req.Action,
+		TenantID: req.TenantID,
+	})
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	if req.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+req.Token)
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPermissionsCheck, err)
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %s on tenant '%s'", ErrDenied, req.Action, req.TenantID)
+	default:
+		return fmt.Errorf("%w: unexpected status %d", ErrPermissionsCheck, resp.StatusCode)
+	}
+}
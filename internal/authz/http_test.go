@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestHTTPAuthorizer checks that permissions checks are sent with the caller's token, and that their
+// responses are mapped to decisions.
+func TestHTTPAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	// The fake permissions-check endpoint responds with the status given as the request's subject.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var check permissionsCheck
+
+		if err := json.NewDecoder(r.Body).Decode(&check); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer token" || check.Action != ActionIssuersRead {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		switch check.Subject {
+		case "allowed":
+			w.WriteHeader(http.StatusOK)
+		case "denied":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	t.Cleanup(srv.Close)
+
+	authorizer := NewHTTPAuthorizer(srv.URL, srv.Client())
+
+	runFn := func(ctx context.Context, subject string) testingx.TestResult[any] {
+		req := Request{
+			Subject:  subject,
+			Token:    "token",
+			Action:   ActionIssuersRead,
+			TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+		}
+
+		return testingx.TestResult[any]{
+			Err: authorizer.Authorize(ctx, req),
+		}
+	}
+
+	testCases := []testingx.TestCase[string, any]{
+		{
+			Name:  "Allowed",
+			Input: "allowed",
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+				assert.NoError(t, res.Err)
+			},
+		},
+		{
+			Name:  "Denied",
+			Input: "denied",
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+				assert.ErrorIs(t, res.Err, ErrDenied)
+			},
+		},
+		{
+			Name:  "Error",
+			Input: "error",
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+				assert.ErrorIs(t, res.Err, ErrPermissionsCheck)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
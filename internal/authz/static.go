@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+)
+
+// StaticAuthorizer is an Authorizer which allows requests matching the rules of a static policy.
+type StaticAuthorizer struct {
+	rules []PolicyRule
+}
+
+// implement the Authorizer interface
+var _ Authorizer = (*StaticAuthorizer)(nil)
+
+// NewStaticAuthorizer creates a StaticAuthorizer for the given policy. Requests not matching any rule
+// are denied.
+func NewStaticAuthorizer(rules []PolicyRule) *StaticAuthorizer {
+	return &StaticAuthorizer{
+		rules: rules,
+	}
+}
+
+// Authorize allows the request if any rule matches its subject, tenant and action.
+func (a *StaticAuthorizer) Authorize(ctx context.Context, req Request) error {
+	for _, rule := range a.rules {
+		if matches(rule.Subjects, req.Subject) &&
+			matches(rule.Tenants, req.TenantID) &&
+			matches(rule.Actions, req.Action) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s on tenant '%s'", ErrDenied, req.Action, req.TenantID)
+}
+
+// matches reports whether the given value, or the Wildcard, is listed. Empty values only match the
+// Wildcard.
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == Wildcard || (value != "" && v == value) {
+			return true
+		}
+	}
+
+	return false
+}
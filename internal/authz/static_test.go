@@ -0,0 +1,107 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestStaticAuthorizer checks that requests are allowed only when a policy rule matches their subject,
+// tenant and action.
+func TestStaticAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
+
+	authorizer := NewStaticAuthorizer([]PolicyRule{
+		{
+			Subjects: []string{"urn:example:user/tenant-admin"},
+			Tenants:  []string{tenantID},
+			Actions:  []string{ActionIssuersRead, ActionIssuersWrite},
+		},
+		{
+			Subjects: []string{"urn:example:user/operator"},
+			Tenants:  []string{Wildcard},
+			Actions:  []string{Wildcard},
+		},
+		{
+			Subjects: []string{Wildcard},
+			Tenants:  []string{tenantID},
+			Actions:  []string{ActionIssuersRead},
+		},
+	})
+
+	runFn := func(ctx context.Context, req Request) testingx.TestResult[any] {
+		return testingx.TestResult[any]{
+			Err: authorizer.Authorize(ctx, req),
+		}
+	}
+
+	checkAllowed := func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+		assert.NoError(t, res.Err)
+	}
+
+	checkDenied := func(ctx context.Context, t *testing.T, res testingx.TestResult[any]) {
+		assert.ErrorIs(t, res.Err, ErrDenied)
+	}
+
+	testCases := []testingx.TestCase[Request, any]{
+		{
+			Name: "TenantAdmin",
+			Input: Request{
+				Subject:  "urn:example:user/tenant-admin",
+				Action:   ActionIssuersWrite,
+				TenantID: tenantID,
+			},
+			CheckFn: checkAllowed,
+		},
+		{
+			Name: "OtherTenant",
+			Input: Request{
+				Subject:  "urn:example:user/tenant-admin",
+				Action:   ActionIssuersWrite,
+				TenantID: "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+			},
+			CheckFn: checkDenied,
+		},
+		{
+			Name: "NoTenant",
+			Input: Request{
+				Subject: "urn:example:user/tenant-admin",
+				Action:  ActionSigningKeysWrite,
+			},
+			CheckFn: checkDenied,
+		},
+		{
+			Name: "WildcardTenant",
+			Input: Request{
+				Subject: "urn:example:user/operator",
+				Action:  ActionSigningKeysWrite,
+			},
+			CheckFn: checkAllowed,
+		},
+		{
+			Name: "WildcardSubject",
+			Input: Request{
+				Subject:  "urn:example:user/anyone",
+				Action:   ActionIssuersRead,
+				TenantID: tenantID,
+			},
+			CheckFn: checkAllowed,
+		},
+		{
+			Name: "WildcardSubjectWrite",
+			Input: Request{
+				Subject:  "urn:example:user/anyone",
+				Action:   ActionIssuersWrite,
+				TenantID: tenantID,
+			},
+			CheckFn: checkDenied,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
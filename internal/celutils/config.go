@@ -0,0 +1,58 @@
+package celutils
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxCost is the maximum cost of a CEL expression used when none is configured.
+	DefaultMaxCost uint64 = 1000000
+	// DefaultEvalTimeout is the maximum evaluation time of a CEL expression used when none is configured.
+	DefaultEvalTimeout = 100 * time.Millisecond
+)
+
+var (
+	configMu sync.RWMutex
+	config   = Config{
+		MaxCost:     DefaultMaxCost,
+		EvalTimeout: DefaultEvalTimeout,
+	}
+)
+
+// Config represents the configuration for CEL expressions evaluated by identity-api.
+type Config struct {
+	// HMACKey is the server key used by the hmacSHA256 CEL function.
+	HMACKey string
+	// MaxCost is the maximum cost of a CEL expression. It is enforced against the estimated worst-case
+	// cost when an expression is parsed with CheckCost, and against the actual cost during evaluation.
+	// Defaults to DefaultMaxCost.
+	MaxCost uint64
+	// EvalTimeout is the maximum time a single CEL expression may spend evaluating. Defaults to
+	// DefaultEvalTimeout.
+	EvalTimeout time.Duration
+}
+
+// Configure applies the given config to the CEL environment. It should be called before any
+// expressions are compiled or evaluated.
+func Configure(c Config) {
+	if c.MaxCost == 0 {
+		c.MaxCost = DefaultMaxCost
+	}
+
+	if c.EvalTimeout == 0 {
+		c.EvalTimeout = DefaultEvalTimeout
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config = c
+}
+
+func getConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return config
+}
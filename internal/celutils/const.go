@@ -6,4 +6,64 @@ const (
 
 	// CELVariableSubSHA256 is the name of the subSHA256 variable in CEL expressions.
 	CELVariableSubSHA256 = "subSHA256"
+
+	// CELVariableIssuer is the name of the issuer variable in CEL expressions.
+	CELVariableIssuer = "issuer"
+
+	// CELVariableClientID is the name of the client_id variable in CEL expressions.
+	CELVariableClientID = "client_id"
+
+	// CELVariableRequestedScopes is the name of the requested_scopes variable in CEL expressions.
+	CELVariableRequestedScopes = "requested_scopes"
+
+	// CELVariableAudience is the name of the audience variable in CEL expressions.
+	CELVariableAudience = "audience"
+
+	// CELVariableUserInfo is the name of the userinfo variable in CEL expressions.
+	CELVariableUserInfo = "userinfo"
+
+	// CELVariableRoles is the name of the roles variable in CEL expressions.
+	CELVariableRoles = "roles"
+)
+
+const (
+	// CELFunctionSHA256 is the name of the function returning the hex-encoded SHA-256 digest of a string.
+	CELFunctionSHA256 = "sha256"
+
+	// CELFunctionHMACSHA256 is the name of the function returning the hex-encoded HMAC-SHA256 of a string
+	// keyed with the configured server key.
+	CELFunctionHMACSHA256 = "hmacSHA256"
+
+	// CELFunctionBase64URLEncode is the name of the function encoding bytes as unpadded base64url.
+	CELFunctionBase64URLEncode = "base64url.encode"
+
+	// CELFunctionBase64URLDecode is the name of the function decoding unpadded base64url to bytes.
+	CELFunctionBase64URLDecode = "base64url.decode"
+
+	// CELFunctionURLQueryEscape is the name of the function escaping a string for use in a URL query.
+	CELFunctionURLQueryEscape = "urlQueryEscape"
+
+	// CELFunctionRegexExtract is the name of the function extracting the first regular expression match from a string.
+	CELFunctionRegexExtract = "regexExtract"
+
+	// CELFunctionRegexExtractAll is the name of the function extracting all regular expression matches from a string.
+	CELFunctionRegexExtractAll = "regexExtractAll"
+
+	// CELFunctionRegexReplace is the name of the function replacing all regular expression matches in a string.
+	CELFunctionRegexReplace = "regexReplace"
+
+	// CELFunctionUUIDv5 is the name of the function generating a UUIDv5 from a namespace UUID and a name.
+	CELFunctionUUIDv5 = "uuidv5"
+
+	// CELFunctionNow is the name of the function returning the current time.
+	CELFunctionNow = "now"
+
+	// CELFunctionFromUnix is the name of the function converting Unix seconds to a timestamp.
+	CELFunctionFromUnix = "fromUnix"
+
+	// CELFunctionToUnix is the name of the function converting a timestamp to Unix seconds.
+	CELFunctionToUnix = "toUnix"
+
+	// CELFunctionFormatTimestamp is the name of the function formatting a timestamp using a Go time layout.
+	CELFunctionFormatTimestamp = "formatTimestamp"
 )
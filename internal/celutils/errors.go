@@ -41,3 +41,25 @@ func (e *ErrorCELEval) Is(target error) bool {
 func (e *ErrorCELEval) Unwrap() error {
 	return e.inner
 }
+
+// ErrorCELLimit represents a CEL expression exceeding its cost or time limits, either by estimate when
+// the expression is parsed or at runtime.
+type ErrorCELLimit struct {
+	inner error
+}
+
+func (ErrorCELLimit) Error() string {
+	return "CEL expression exceeded evaluation limits"
+}
+
+// Is returns true if target is a *ErrorCELLimit.
+func (e *ErrorCELLimit) Is(target error) bool {
+	_, ok := target.(*ErrorCELLimit)
+
+	return ok
+}
+
+// Unwrap returns the inner error from CEL cost estimation or evaluation.
+func (e *ErrorCELLimit) Unwrap() error {
+	return e.inner
+}
@@ -0,0 +1,291 @@
+package celutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+	"github.com/google/uuid"
+)
+
+func getHMACKey() []byte {
+	return []byte(getConfig().HMACKey)
+}
+
+// functionLib is a cel.Library providing identity-api's extension functions for CEL expressions.
+type functionLib struct{}
+
+func (functionLib) LibraryName() string {
+	return "identity-api.lib.functions"
+}
+
+func (functionLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function(CELFunctionSHA256,
+			cel.Overload("sha256_string", []*cel.Type{cel.StringType}, cel.StringType,
+				cel.UnaryBinding(sha256String),
+			),
+		),
+		cel.Function(CELFunctionHMACSHA256,
+			cel.Overload("hmac_sha256_string", []*cel.Type{cel.StringType}, cel.StringType,
+				cel.UnaryBinding(hmacSHA256String),
+			),
+		),
+		cel.Function(CELFunctionBase64URLEncode,
+			cel.Overload("base64url_encode_bytes", []*cel.Type{cel.BytesType}, cel.StringType,
+				cel.UnaryBinding(base64URLEncodeBytes),
+			),
+		),
+		cel.Function(CELFunctionBase64URLDecode,
+			cel.Overload("base64url_decode_string", []*cel.Type{cel.StringType}, cel.BytesType,
+				cel.UnaryBinding(base64URLDecodeString),
+			),
+		),
+		cel.Function(CELFunctionURLQueryEscape,
+			cel.Overload("url_query_escape_string", []*cel.Type{cel.StringType}, cel.StringType,
+				cel.UnaryBinding(urlQueryEscape),
+			),
+		),
+		cel.Function(CELFunctionRegexExtract,
+			cel.Overload("regex_extract_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType,
+				cel.FunctionBinding(regexBinding(CELFunctionRegexExtract)),
+			),
+		),
+		cel.Function(CELFunctionRegexExtractAll,
+			cel.Overload("regex_extract_all_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.ListType(cel.StringType),
+				cel.FunctionBinding(regexBinding(CELFunctionRegexExtractAll)),
+			),
+		),
+		cel.Function(CELFunctionRegexReplace,
+			cel.Overload("regex_replace_string_string_string", []*cel.Type{cel.StringType, cel.StringType, cel.StringType}, cel.StringType,
+				cel.FunctionBinding(regexBinding(CELFunctionRegexReplace)),
+			),
+		),
+		cel.Function(CELFunctionUUIDv5,
+			cel.Overload("uuidv5_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType,
+				cel.BinaryBinding(uuidV5),
+			),
+		),
+		cel.Function(CELFunctionNow,
+			cel.Overload("now", []*cel.Type{}, cel.TimestampType,
+				cel.FunctionBinding(now),
+			),
+		),
+		cel.Function(CELFunctionFromUnix,
+			cel.Overload("from_unix_int", []*cel.Type{cel.IntType}, cel.TimestampType,
+				cel.UnaryBinding(fromUnix),
+			),
+		),
+		cel.Function(CELFunctionToUnix,
+			cel.Overload("to_unix_timestamp", []*cel.Type{cel.TimestampType}, cel.IntType,
+				cel.UnaryBinding(toUnix),
+			),
+		),
+		cel.Function(CELFunctionFormatTimestamp,
+			cel.Overload("format_timestamp_timestamp_string", []*cel.Type{cel.TimestampType, cel.StringType}, cel.StringType,
+				cel.BinaryBinding(formatTimestamp),
+			),
+		),
+	}
+}
+
+func (functionLib) ProgramOptions() []cel.ProgramOption {
+	return []cel.ProgramOption{
+		cel.OptimizeRegex(regexOptimizations()...),
+	}
+}
+
+func sha256String(val ref.Val) ref.Val {
+	str := val.(types.String)
+
+	sum := sha256.Sum256([]byte(str))
+
+	return types.String(hex.EncodeToString(sum[:]))
+}
+
+func hmacSHA256String(val ref.Val) ref.Val {
+	str := val.(types.String)
+
+	key := getHMACKey()
+	if len(key) == 0 {
+		return types.NewErr("%s: no HMAC key configured", CELFunctionHMACSHA256)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(str))
+
+	return types.String(hex.EncodeToString(mac.Sum(nil)))
+}
+
+func base64URLEncodeBytes(val ref.Val) ref.Val {
+	b := val.(types.Bytes)
+
+	return types.String(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func base64URLDecodeString(val ref.Val) ref.Val {
+	str := val.(types.String)
+
+	b, err := base64.RawURLEncoding.DecodeString(string(str))
+	if err != nil {
+		return types.NewErr("%s: %s", CELFunctionBase64URLDecode, err)
+	}
+
+	return types.Bytes(b)
+}
+
+func urlQueryEscape(val ref.Val) ref.Val {
+	str := val.(types.String)
+
+	return types.String(url.QueryEscape(string(str)))
+}
+
+// regexFunc implements a function taking a string and a regular expression pattern, followed by any
+// other arguments, using the compiled pattern.
+type regexFunc func(re *regexp.Regexp, args ...ref.Val) ref.Val
+
+// regexFuncs maps each regular expression function to its implementation. Patterns must be constants,
+// which are compiled once when a program is created.
+var regexFuncs = map[string]regexFunc{
+	CELFunctionRegexExtract:    regexExtract,
+	CELFunctionRegexExtractAll: regexExtractAll,
+	CELFunctionRegexReplace:    regexReplace,
+}
+
+// regexBinding returns the binding for the given regular expression function. It compiles the pattern
+// on every call, so it is only used for calls whose pattern was not compiled when the program was created.
+func regexBinding(function string) func(args ...ref.Val) ref.Val {
+	fn := regexFuncs[function]
+
+	return func(args ...ref.Val) ref.Val {
+		pattern := args[1].(types.String)
+
+		re, err := regexp.Compile(string(pattern))
+		if err != nil {
+			return types.NewErr("%s: %s", function, err)
+		}
+
+		return fn(re, args...)
+	}
+}
+
+// regexOptimizations compiles the constant patterns of regular expression function calls when a program
+// is created.
+func regexOptimizations() []*interpreter.RegexOptimization {
+	out := make([]*interpreter.RegexOptimization, 0, len(regexFuncs))
+
+	for function, fn := range regexFuncs {
+		function, fn := function, fn
+
+		out = append(out, &interpreter.RegexOptimization{
+			Function:   function,
+			RegexIndex: 1,
+			Factory: func(call interpreter.InterpretableCall, pattern string) (interpreter.InterpretableCall, error) {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", function, err)
+				}
+
+				impl := func(args ...ref.Val) ref.Val {
+					return fn(re, args...)
+				}
+
+				return interpreter.NewCall(call.ID(), call.Function(), call.OverloadID(), call.Args(), impl), nil
+			},
+		})
+	}
+
+	return out
+}
+
+// regexExtract returns the first capture group of the first match of the given pattern, or the whole match
+// if the pattern has no capture groups. If the pattern does not match, an empty string is returned.
+func regexExtract(re *regexp.Regexp, args ...ref.Val) ref.Val {
+	str := args[0].(types.String)
+
+	match := re.FindStringSubmatch(string(str))
+
+	switch len(match) {
+	case 0:
+		return types.String("")
+	case 1:
+		return types.String(match[0])
+	default:
+		return types.String(match[1])
+	}
+}
+
+// regexExtractAll returns every match of the given pattern, using the first capture group of each match if
+// the pattern has capture groups.
+func regexExtractAll(re *regexp.Regexp, args ...ref.Val) ref.Val {
+	str := args[0].(types.String)
+
+	matches := re.FindAllStringSubmatch(string(str), -1)
+	out := make([]string, len(matches))
+
+	for i, match := range matches {
+		if len(match) > 1 {
+			out[i] = match[1]
+		} else {
+			out[i] = match[0]
+		}
+	}
+
+	return types.NewStringList(types.DefaultTypeAdapter, out)
+}
+
+func regexReplace(re *regexp.Regexp, args ...ref.Val) ref.Val {
+	str := args[0].(types.String)
+	replacement := args[2].(types.String)
+
+	return types.String(re.ReplaceAllString(string(str), string(replacement)))
+}
+
+func uuidV5(namespaceVal, nameVal ref.Val) ref.Val {
+	namespace := namespaceVal.(types.String)
+	name := nameVal.(types.String)
+
+	ns, err := uuid.Parse(string(namespace))
+	if err != nil {
+		return types.NewErr("%s: invalid namespace: %s", CELFunctionUUIDv5, err)
+	}
+
+	return types.String(uuid.NewSHA1(ns, []byte(name)).String())
+}
+
+func now(args ...ref.Val) ref.Val {
+	return types.Timestamp{
+		Time: time.Now().UTC(),
+	}
+}
+
+func fromUnix(val ref.Val) ref.Val {
+	secs := val.(types.Int)
+
+	return types.Timestamp{
+		Time: time.Unix(int64(secs), 0).UTC(),
+	}
+}
+
+func toUnix(val ref.Val) ref.Val {
+	ts := val.(types.Timestamp)
+
+	return types.Int(ts.Unix())
+}
+
+// formatTimestamp formats a timestamp using a Go time layout string.
+func formatTimestamp(tsVal, layoutVal ref.Val) ref.Val {
+	ts := tsVal.(types.Timestamp)
+	layout := layoutVal.(types.String)
+
+	return types.String(ts.Format(string(layout)))
+}
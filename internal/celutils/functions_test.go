@@ -0,0 +1,169 @@
+package celutils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/celutils"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestFunctions checks that identity-api's CEL extension functions type check and evaluate correctly.
+func TestFunctions(t *testing.T) {
+	t.Parallel()
+
+	celutils.Configure(celutils.Config{
+		HMACKey: "supersecret",
+	})
+
+	inputEnv := map[string]any{
+		celutils.CELVariableClaims: map[string]any{
+			"sub":    "foo",
+			"email":  "Foo@Example.com",
+			"groups": "admins,users",
+			"iat":    int64(1677628800),
+		},
+		celutils.CELVariableSubSHA256: "",
+	}
+
+	runFn := func(ctx context.Context, prog string) testingx.TestResult[any] {
+		ast, err := celutils.ParseCEL(prog)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		out, err := celutils.Eval(ctx, ast, inputEnv)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		return testingx.TestResult[any]{
+			Success: out.Value(),
+		}
+	}
+
+	expectValue := func(exp any) func(context.Context, *testing.T, testingx.TestResult[any]) {
+		return func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+			if assert.NoError(t, result.Err) {
+				assert.Equal(t, exp, result.Success)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[string, any]{
+		{
+			Name:    "SHA256",
+			Input:   "sha256(claims.sub)",
+			CheckFn: expectValue("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"),
+		},
+		{
+			Name:    "HMACSHA256",
+			Input:   "hmacSHA256(claims.sub)",
+			CheckFn: expectValue("3698b9cd1c9532d97ba997b7dcc64742d1d525d1953b03e67b84b4fa088a1f4f"),
+		},
+		{
+			Name:    "Base64",
+			Input:   "base64.encode(bytes(claims.sub))",
+			CheckFn: expectValue("Zm9v"),
+		},
+		{
+			Name:    "Base64URL",
+			Input:   "base64url.encode(b'\\xfb\\xff')",
+			CheckFn: expectValue("-_8"),
+		},
+		{
+			Name:    "Base64URLRoundTrip",
+			Input:   "string(base64url.decode(base64url.encode(bytes(claims.email))))",
+			CheckFn: expectValue("Foo@Example.com"),
+		},
+		{
+			Name:    "URLQueryEscape",
+			Input:   "urlQueryEscape('a b&c')",
+			CheckFn: expectValue("a+b%26c"),
+		},
+		{
+			Name:    "LowerASCII",
+			Input:   "claims.email.lowerAscii()",
+			CheckFn: expectValue("foo@example.com"),
+		},
+		{
+			Name:    "SplitJoin",
+			Input:   "claims.groups.split(',').join(' ')",
+			CheckFn: expectValue("admins users"),
+		},
+		{
+			Name:    "RegexExtract",
+			Input:   "regexExtract(claims.email, '@(.*)$')",
+			CheckFn: expectValue("Example.com"),
+		},
+		{
+			Name:    "RegexExtractNoMatch",
+			Input:   "regexExtract(claims.email, '^bar')",
+			CheckFn: expectValue(""),
+		},
+		{
+			Name:  "RegexExtractAll",
+			Input: "regexExtractAll(claims.groups, '[a-z]+')",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				if assert.NoError(t, result.Err) {
+					assert.Equal(t, []string{"admins", "users"}, result.Success)
+				}
+			},
+		},
+		{
+			Name:    "RegexReplace",
+			Input:   "regexReplace(claims.email, '@.*$', '')",
+			CheckFn: expectValue("Foo"),
+		},
+		{
+			Name:  "RegexInvalid",
+			Input: "regexReplace(claims.email, '(', '')",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELParse{})
+			},
+		},
+		{
+			Name:  "RegexPatternNotConstant",
+			Input: "[claims.sub].map(p, regexExtract(claims.email, p))",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELParse{})
+				assert.ErrorIs(t, result.Err, celutils.ErrorRegexPatternNotConstant)
+			},
+		},
+		{
+			Name:    "UUIDv5",
+			Input:   "uuidv5('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'example.com')",
+			CheckFn: expectValue("cfbff0d1-9375-5685-968c-48ce8b15ae17"),
+		},
+		{
+			Name:    "Timestamps",
+			Input:   "toUnix(fromUnix(claims.iat))",
+			CheckFn: expectValue(int64(1677628800)),
+		},
+		{
+			Name:    "FormatTimestamp",
+			Input:   "formatTimestamp(fromUnix(claims.iat), '2006-01-02')",
+			CheckFn: expectValue("2023-03-01"),
+		},
+		{
+			Name:    "Now",
+			Input:   "now() > fromUnix(claims.iat)",
+			CheckFn: expectValue(true),
+		},
+		{
+			Name:  "TypeError",
+			Input: "sha256(1)",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELParse{})
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
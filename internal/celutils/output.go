@@ -0,0 +1,189 @@
+package celutils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// OutputType represents the declared output type of a CEL expression.
+type OutputType string
+
+const (
+	// OutputTypeString declares an expression producing a string.
+	OutputTypeString OutputType = "string"
+	// OutputTypeBool declares an expression producing a bool.
+	OutputTypeBool OutputType = "bool"
+	// OutputTypeInt declares an expression producing an int.
+	OutputTypeInt OutputType = "int"
+	// OutputTypeStringList declares an expression producing a list of strings.
+	OutputTypeStringList OutputType = "list<string>"
+	// OutputTypeMap declares an expression producing a map with string keys.
+	OutputTypeMap OutputType = "map"
+)
+
+var (
+	// ErrorUnknownOutputType is returned when an unsupported output type is declared.
+	ErrorUnknownOutputType = errors.New("unknown output type")
+
+	// ErrorOutputTypeMismatch is returned when an expression does not produce its declared output type.
+	ErrorOutputTypeMismatch = errors.New("output type mismatch")
+)
+
+var outputTypes = map[OutputType]struct {
+	celType    *cel.Type
+	nativeType reflect.Type
+}{
+	OutputTypeString: {
+		celType:    cel.StringType,
+		nativeType: reflect.TypeOf(""),
+	},
+	OutputTypeBool: {
+		celType:    cel.BoolType,
+		nativeType: reflect.TypeOf(false),
+	},
+	OutputTypeInt: {
+		celType:    cel.IntType,
+		nativeType: reflect.TypeOf(int64(0)),
+	},
+	OutputTypeStringList: {
+		celType:    cel.ListType(cel.StringType),
+		nativeType: reflect.TypeOf([]string{}),
+	},
+	OutputTypeMap: {
+		celType: cel.MapType(cel.StringType, cel.DynType),
+	},
+}
+
+// CheckOutputType checks that the given AST may produce the given output type. Expressions whose type is
+// only partially known at parse time (e.g., those reading from claims) are accepted and checked when
+// evaluated, as are expressions producing null.
+func CheckOutputType(ast *cel.Ast, outputType OutputType) error {
+	if outputType == "" {
+		return nil
+	}
+
+	declared, ok := outputTypes[outputType]
+	if !ok {
+		wrapped := ErrorCELParse{
+			inner: fmt.Errorf("%w '%s'", ErrorUnknownOutputType, outputType),
+		}
+
+		return &wrapped
+	}
+
+	observed := ast.OutputType()
+
+	switch {
+	case observed.String() == cel.NullType.String():
+	case declared.celType.IsAssignableType(observed):
+	case observed.IsAssignableType(declared.celType):
+	default:
+		wrapped := ErrorCELParse{
+			inner: fmt.Errorf("%w: expected %s, got %s", ErrorOutputTypeMismatch, outputType, observed),
+		}
+
+		return &wrapped
+	}
+
+	return nil
+}
+
+// ConvertOutput converts the given CEL value to a native Go value suitable for use as a JSON claim,
+// checking it against the given output type if one is declared. Null values are converted to nil.
+func ConvertOutput(val ref.Val, outputType OutputType) (any, error) {
+	if val.Type() == types.NullType {
+		return nil, nil
+	}
+
+	if outputType == "" {
+		return toNative(val)
+	}
+
+	declared, ok := outputTypes[outputType]
+	if !ok {
+		wrapped := ErrorCELEval{
+			inner: fmt.Errorf("%w '%s'", ErrorUnknownOutputType, outputType),
+		}
+
+		return nil, &wrapped
+	}
+
+	if declared.nativeType == nil {
+		if val.Type() != types.MapType {
+			return nil, outputMismatch(outputType, val)
+		}
+
+		return toNative(val)
+	}
+
+	out, err := val.ConvertToNative(declared.nativeType)
+	if err != nil {
+		return nil, outputMismatch(outputType, val)
+	}
+
+	return out, nil
+}
+
+func outputMismatch(outputType OutputType, val ref.Val) error {
+	wrapped := ErrorCELEval{
+		inner: fmt.Errorf("%w: expected %s, got %s", ErrorOutputTypeMismatch, outputType, val.Type().TypeName()),
+	}
+
+	return &wrapped
+}
+
+// toNative recursively converts CEL lists and maps to Go slices and maps, which unlike CEL's own
+// list and map values serialize cleanly to JSON.
+func toNative(val ref.Val) (any, error) {
+	switch v := val.(type) {
+	case types.Null:
+		return nil, nil
+	case traits.Mapper:
+		out := make(map[string]any)
+
+		it := v.Iterator()
+		for it.HasNext() == types.True {
+			key := it.Next()
+
+			keyStr, ok := key.(types.String)
+			if !ok {
+				wrapped := ErrorCELEval{
+					inner: fmt.Errorf("%w: map keys must be strings, got %s", ErrorOutputTypeMismatch, key.Type().TypeName()),
+				}
+
+				return nil, &wrapped
+			}
+
+			elem, err := toNative(v.Get(key))
+			if err != nil {
+				return nil, err
+			}
+
+			out[string(keyStr)] = elem
+		}
+
+		return out, nil
+	case traits.Lister:
+		out := []any{}
+
+		it := v.Iterator()
+		for it.HasNext() == types.True {
+			elem, err := toNative(it.Next())
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, elem)
+		}
+
+		return out, nil
+	default:
+		return val.Value(), nil
+	}
+}
@@ -0,0 +1,171 @@
+package celutils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/celutils"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestOutputTypes checks that declared output types are checked at parse time and converted at evaluation time.
+func TestOutputTypes(t *testing.T) {
+	t.Parallel()
+
+	inputEnv := map[string]any{
+		celutils.CELVariableClaims: map[string]any{
+			"email":  "foo@example.com",
+			"groups": "admins,users",
+			"num":    int64(1),
+			"nested": map[string]any{
+				"roles": []any{"admin"},
+			},
+		},
+	}
+
+	type testInput struct {
+		prog       string
+		outputType celutils.OutputType
+	}
+
+	runFn := func(ctx context.Context, input testInput) testingx.TestResult[any] {
+		ast, err := celutils.ParseCEL(input.prog)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		if err := celutils.CheckOutputType(ast, input.outputType); err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		val, err := celutils.Eval(ctx, ast, inputEnv)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		out, err := celutils.ConvertOutput(val, input.outputType)
+
+		return testingx.TestResult[any]{
+			Success: out,
+			Err:     err,
+		}
+	}
+
+	expectValue := func(exp any) func(context.Context, *testing.T, testingx.TestResult[any]) {
+		return func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+			if assert.NoError(t, result.Err) {
+				assert.Equal(t, exp, result.Success)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[testInput, any]{
+		{
+			Name: "String",
+			Input: testInput{
+				prog:       "claims.email",
+				outputType: celutils.OutputTypeString,
+			},
+			CheckFn: expectValue("foo@example.com"),
+		},
+		{
+			Name: "Bool",
+			Input: testInput{
+				prog:       "claims.num == 1",
+				outputType: celutils.OutputTypeBool,
+			},
+			CheckFn: expectValue(true),
+		},
+		{
+			Name: "Int",
+			Input: testInput{
+				prog:       "claims.num + 1",
+				outputType: celutils.OutputTypeInt,
+			},
+			CheckFn: expectValue(int64(2)),
+		},
+		{
+			Name: "StringList",
+			Input: testInput{
+				prog:       "claims.groups.split(',')",
+				outputType: celutils.OutputTypeStringList,
+			},
+			CheckFn: expectValue([]string{"admins", "users"}),
+		},
+		{
+			Name: "Map",
+			Input: testInput{
+				prog:       "{'email': claims.email, 'roles': claims.nested.roles}",
+				outputType: celutils.OutputTypeMap,
+			},
+			CheckFn: expectValue(map[string]any{
+				"email": "foo@example.com",
+				"roles": []any{"admin"},
+			}),
+		},
+		{
+			Name: "Undeclared",
+			Input: testInput{
+				prog: "[claims.nested, {'num': claims.num}]",
+			},
+			CheckFn: expectValue([]any{
+				map[string]any{
+					"roles": []any{"admin"},
+				},
+				map[string]any{
+					"num": int64(1),
+				},
+			}),
+		},
+		{
+			Name: "Null",
+			Input: testInput{
+				prog:       "has(claims.missing) ? claims.missing : null",
+				outputType: celutils.OutputTypeString,
+			},
+			CheckFn: expectValue(nil),
+		},
+		{
+			Name: "ParseTimeMismatch",
+			Input: testInput{
+				prog:       "claims.groups.split(',')",
+				outputType: celutils.OutputTypeString,
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELParse{})
+				assert.ErrorIs(t, result.Err, celutils.ErrorOutputTypeMismatch)
+			},
+		},
+		{
+			Name: "RuntimeMismatch",
+			Input: testInput{
+				prog:       "claims.num",
+				outputType: celutils.OutputTypeString,
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELEval{})
+				assert.ErrorIs(t, result.Err, celutils.ErrorOutputTypeMismatch)
+			},
+		},
+		{
+			Name: "UnknownType",
+			Input: testInput{
+				prog:       "claims.email",
+				outputType: "float",
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, celutils.ErrorUnknownOutputType)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
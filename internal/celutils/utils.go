@@ -1,18 +1,54 @@
 package celutils
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/common"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/google/cel-go/interpreter"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+const (
+	// estimatedMaxInputSize is the assumed maximum size of input strings, lists and maps when
+	// estimating the cost of an expression.
+	estimatedMaxInputSize = 1024
+	// interruptCheckFrequency is the number of comprehension iterations between checks for
+	// evaluation timeouts.
+	interruptCheckFrequency = 100
 )
 
 var (
 	celEnv *cel.Env
+
+	// ErrorCostEstimateExceeded is returned (wrapped in an *ErrorCELLimit) when the estimated cost
+	// of an expression exceeds the maximum cost.
+	ErrorCostEstimateExceeded = errors.New("estimated cost exceeds maximum cost")
+
+	// ErrorRegexPatternNotConstant is returned (wrapped in an *ErrorCELParse) when the pattern passed to
+	// a regular expression function is not a string constant.
+	ErrorRegexPatternNotConstant = errors.New("regular expression pattern must be a string constant")
 )
 
 func init() {
 	env, err := cel.NewEnv(
 		cel.Variable(CELVariableClaims, cel.MapType(cel.StringType, cel.DynType)),
 		cel.Variable(CELVariableSubSHA256, cel.StringType),
+		cel.Variable(CELVariableIssuer, cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable(CELVariableClientID, cel.StringType),
+		cel.Variable(CELVariableRequestedScopes, cel.ListType(cel.StringType)),
+		cel.Variable(CELVariableAudience, cel.ListType(cel.StringType)),
+		cel.Variable(CELVariableUserInfo, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(CELVariableRoles, cel.ListType(cel.StringType)),
+		ext.Strings(),
+		ext.Encoders(),
+		cel.Lib(functionLib{}),
 	)
 
 	if err != nil {
@@ -22,7 +58,8 @@ func init() {
 	celEnv = env
 }
 
-// ParseCEL parses a CEL expression.
+// ParseCEL parses a CEL expression. Patterns passed to regular expression functions must be valid
+// string constants.
 func ParseCEL(input string) (*cel.Ast, error) {
 	ast, issues := celEnv.Compile(input)
 	if err := issues.Err(); err != nil {
@@ -33,12 +70,105 @@ func ParseCEL(input string) (*cel.Ast, error) {
 		return nil, &wrapped
 	}
 
+	if err := checkRegexPatterns(ast.Expr()); err != nil {
+		wrapped := ErrorCELParse{
+			inner: err,
+		}
+
+		return nil, &wrapped
+	}
+
 	return ast, nil
 }
 
-// Eval evaluates the given AST against the provided input environment.
-func Eval(ast *cel.Ast, inputEnv map[string]any) (ref.Val, error) {
-	prog, err := celEnv.Program(ast)
+// checkRegexPatterns checks that every regular expression function call in the given expression has a
+// constant pattern that compiles.
+func checkRegexPatterns(expr *exprpb.Expr) error {
+	var children []*exprpb.Expr
+
+	switch kind := expr.GetExprKind().(type) {
+	case *exprpb.Expr_CallExpr:
+		call := kind.CallExpr
+
+		if _, ok := regexFuncs[call.GetFunction()]; ok && len(call.GetArgs()) > 1 {
+			pattern, ok := call.GetArgs()[1].GetConstExpr().GetConstantKind().(*exprpb.Constant_StringValue)
+			if !ok {
+				return fmt.Errorf("%s: %w", call.GetFunction(), ErrorRegexPatternNotConstant)
+			}
+
+			if _, err := regexp.Compile(pattern.StringValue); err != nil {
+				return fmt.Errorf("%s: %w", call.GetFunction(), err)
+			}
+		}
+
+		children = append(children, call.GetTarget())
+		children = append(children, call.GetArgs()...)
+	case *exprpb.Expr_SelectExpr:
+		children = append(children, kind.SelectExpr.GetOperand())
+	case *exprpb.Expr_ListExpr:
+		children = append(children, kind.ListExpr.GetElements()...)
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range kind.StructExpr.GetEntries() {
+			children = append(children, entry.GetMapKey(), entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		comp := kind.ComprehensionExpr
+
+		children = append(children,
+			comp.GetIterRange(),
+			comp.GetAccuInit(),
+			comp.GetLoopCondition(),
+			comp.GetLoopStep(),
+			comp.GetResult(),
+		)
+	}
+
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+
+		if err := checkRegexPatterns(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckCost estimates the worst-case cost of evaluating the given AST, returning an *ErrorCELLimit if
+// the estimate exceeds the configured maximum cost. Input sizes are assumed to be bounded by
+// estimatedMaxInputSize.
+func CheckCost(ast *cel.Ast) error {
+	estimate, err := celEnv.EstimateCost(ast, costEstimator{})
+	if err != nil {
+		wrapped := ErrorCELParse{
+			inner: err,
+		}
+
+		return &wrapped
+	}
+
+	maxCost := getConfig().MaxCost
+
+	if estimate.Max > maxCost {
+		wrapped := ErrorCELLimit{
+			inner: fmt.Errorf("%w: estimated %d, maximum %d", ErrorCostEstimateExceeded, estimate.Max, maxCost),
+		}
+
+		return &wrapped
+	}
+
+	return nil
+}
+
+// Program compiles the given AST into a program that can be evaluated repeatedly. The program is
+// limited to the configured maximum cost.
+func Program(ast *cel.Ast) (cel.Program, error) {
+	prog, err := celEnv.Program(ast,
+		cel.CostLimit(getConfig().MaxCost),
+		cel.InterruptCheckFrequency(interruptCheckFrequency),
+	)
 	if err != nil {
 		wrapped := ErrorCELParse{
 			inner: err,
@@ -47,14 +177,86 @@ func Eval(ast *cel.Ast, inputEnv map[string]any) (ref.Val, error) {
 		return nil, &wrapped
 	}
 
-	val, _, err := prog.Eval(inputEnv)
+	return prog, nil
+}
+
+// Eval evaluates the given AST against the provided input environment.
+func Eval(ctx context.Context, ast *cel.Ast, inputEnv map[string]any) (ref.Val, error) {
+	prog, err := Program(ast)
 	if err != nil {
+		return nil, err
+	}
+
+	return EvalProgram(ctx, prog, inputEnv)
+}
+
+// EvalProgram evaluates the given compiled program against the provided input environment. Evaluation
+// is interrupted if it exceeds the configured timeout or the given context is done, in which case an
+// *ErrorCELLimit is returned.
+func EvalProgram(ctx context.Context, prog cel.Program, inputEnv map[string]any) (ref.Val, error) {
+	ctx, cancel := context.WithTimeout(ctx, getConfig().EvalTimeout)
+	defer cancel()
+
+	val, _, err := prog.ContextEval(ctx, inputEnv)
+
+	var cancelled interpreter.EvalCancelledError
+
+	switch {
+	case err == nil:
+		return val, nil
+	case errors.As(err, &cancelled):
+		wrapped := ErrorCELLimit{
+			inner: err,
+		}
+
+		return nil, &wrapped
+	case ctx.Err() != nil:
+		wrapped := ErrorCELLimit{
+			inner: ctx.Err(),
+		}
+
+		return nil, &wrapped
+	default:
 		wrapped := ErrorCELEval{
 			inner: err,
 		}
 
 		return nil, &wrapped
 	}
+}
+
+// costEstimator bounds the size of every input whose size is not known from the expression itself.
+type costEstimator struct{}
+
+func (costEstimator) EstimateSize(element checker.AstNode) *checker.SizeEstimate {
+	return &checker.SizeEstimate{
+		Min: 0,
+		Max: estimatedMaxInputSize,
+	}
+}
+
+// EstimateCallCost estimates the cost of regular expression functions as the cost of compiling the
+// pattern plus the cost of matching it, which is proportional to the product of the input and pattern
+// lengths. The cost of every other function is left to the default estimate.
+func (e costEstimator) EstimateCallCost(function, overloadID string, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	if _, ok := regexFuncs[function]; !ok || len(args) < 2 {
+		return nil
+	}
+
+	compileCost := e.size(args[1]).MultiplyByCostFactor(common.RegexStringLengthCostFactor)
+	strCost := e.size(args[0]).Add(checker.SizeEstimate{Min: 1, Max: 1}).MultiplyByCostFactor(common.StringTraversalCostFactor)
+	matchCost := strCost.Multiply(compileCost)
+
+	return &checker.CallEstimate{
+		CostEstimate: compileCost.Add(matchCost),
+	}
+}
+
+// size returns the size of the given node, if it is known from the expression, or else its estimated size.
+func (e costEstimator) size(node checker.AstNode) checker.SizeEstimate {
+	if size := node.ComputedSize(); size != nil {
+		return *size
+	}
 
-	return val, nil
+	return *e.EstimateSize(node)
 }
@@ -0,0 +1,146 @@
+package celutils_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/celutils"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestCheckCost checks that expressions are rejected when their estimated cost is too high.
+func TestCheckCost(t *testing.T) {
+	t.Parallel()
+
+	runFn := func(ctx context.Context, prog string) testingx.TestResult[any] {
+		ast, err := celutils.ParseCEL(prog)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		return testingx.TestResult[any]{
+			Err: celutils.CheckCost(ast),
+		}
+	}
+
+	testCases := []testingx.TestCase[string, any]{
+		{
+			Name:  "Simple",
+			Input: "'infratographer://example.com/' + subSHA256",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.NoError(t, result.Err)
+			},
+		},
+		{
+			Name:  "Comprehension",
+			Input: "claims.groups.split(',').map(g, g.lowerAscii())",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.NoError(t, result.Err)
+			},
+		},
+		{
+			Name:  "Regex",
+			Input: "regexReplace(claims.email, '@.*$', '')",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.NoError(t, result.Err)
+			},
+		},
+		{
+			Name:  "NestedComprehension",
+			Input: "requested_scopes.map(a, requested_scopes.map(b, requested_scopes.map(c, a + b + c)))",
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELLimit{})
+				assert.ErrorIs(t, result.Err, celutils.ErrorCostEstimateExceeded)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestEvalLimits checks that evaluation is stopped when runtime limits are hit.
+func TestEvalLimits(t *testing.T) {
+	t.Parallel()
+
+	scopes := make([]string, 2000)
+	for i := range scopes {
+		scopes[i] = fmt.Sprintf("scope-%d", i)
+	}
+
+	inputEnv := map[string]any{
+		celutils.CELVariableRequestedScopes: scopes,
+	}
+
+	type testInput struct {
+		prog      string
+		cancelled bool
+	}
+
+	runFn := func(ctx context.Context, input testInput) testingx.TestResult[any] {
+		ast, err := celutils.ParseCEL(input.prog)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		if input.cancelled {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithCancel(ctx)
+			cancel()
+		}
+
+		out, err := celutils.Eval(ctx, ast, inputEnv)
+		if err != nil {
+			return testingx.TestResult[any]{
+				Err: err,
+			}
+		}
+
+		return testingx.TestResult[any]{
+			Success: out.Value(),
+		}
+	}
+
+	testCases := []testingx.TestCase[testInput, any]{
+		{
+			Name: "WithinLimits",
+			Input: testInput{
+				prog: "requested_scopes.filter(s, s.endsWith('-1')).size()",
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				if assert.NoError(t, result.Err) {
+					assert.Equal(t, int64(1), result.Success)
+				}
+			},
+		},
+		{
+			Name: "CostLimit",
+			Input: testInput{
+				prog: "requested_scopes.map(a, requested_scopes.map(b, a + b)).size()",
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELLimit{})
+			},
+		},
+		{
+			Name: "Interrupted",
+			Input: testInput{
+				prog:      "requested_scopes.filter(s, s.endsWith('-1')).size()",
+				cancelled: true,
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[any]) {
+				assert.ErrorIs(t, result.Err, &celutils.ErrorCELLimit{})
+				assert.ErrorIs(t, result.Err, context.Canceled)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -6,6 +6,8 @@ import (
 	"go.infratographer.com/x/loggingx"
 	"go.infratographer.com/x/otelx"
 
+	"go.infratographer.com/identity-api/internal/authz"
+	"go.infratographer.com/identity-api/internal/celutils"
 	"go.infratographer.com/identity-api/internal/fositex"
 	"go.infratographer.com/identity-api/internal/storage"
 )
@@ -17,4 +19,6 @@ var Config struct {
 	OAuth   fositex.Config
 	OTel    otelx.Config
 	Storage storage.Config
+	CEL     celutils.Config
+	APIAuth authz.Config
 }
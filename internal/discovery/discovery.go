@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+const (
+	// WellKnownPath is the path of an issuer's OIDC discovery metadata, relative to the issuer URI.
+	WellKnownPath = "/.well-known/openid-configuration"
+
+	// DefaultCacheTTL is how long discovery metadata is cached by DefaultClient.
+	DefaultCacheTTL = time.Hour
+)
+
+var (
+	// ErrFetchMetadata represents a failure when fetching discovery metadata.
+	ErrFetchMetadata = errors.New("could not fetch discovery metadata")
+
+	// ErrMetadataNotFound is returned when an issuer does not publish discovery metadata.
+	ErrMetadataNotFound = fmt.Errorf("%w: metadata not found", ErrFetchMetadata)
+
+	// ErrIssuerMismatch is returned when the issuer in discovery metadata does not match the issuer it
+	// was fetched for.
+	ErrIssuerMismatch = errors.New("discovery metadata issuer does not match")
+
+	// ErrEndpointNotFound is returned when an endpoint is neither configured for an issuer nor present
+	// in its discovery metadata.
+	ErrEndpointNotFound = errors.New("endpoint not found in discovery metadata")
+
+	// DefaultClient is the discovery client shared by identity-api components.
+	DefaultClient = NewClient(http.DefaultClient, DefaultCacheTTL)
+)
+
+// Metadata represents the subset of OIDC discovery metadata used by identity-api.
+// See https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint,omitempty"`
+	IntrospectionEndpoint string `json:"introspection_endpoint,omitempty"`
+}
+
+type cacheEntry struct {
+	metadata  Metadata
+	expiresAt time.Time
+}
+
+// Client fetches discovery metadata for issuers, caching it for a fixed TTL.
+type Client struct {
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cacheEntry
+}
+
+// NewClient creates a new Client using the given HTTP client. Metadata is cached for the given TTL; a
+// TTL of zero or less disables caching.
+func NewClient(httpClient *http.Client, ttl time.Duration) *Client {
+	return &Client{
+		httpClient: httpClient,
+		ttl:        ttl,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// Fetch returns the discovery metadata for the given issuer URI. Failed fetches are not cached.
+func (c *Client) Fetch(ctx context.Context, iss string) (*Metadata, error) {
+	if md, ok := c.get(iss); ok {
+		return md, nil
+	}
+
+	endpoint := strings.TrimSuffix(iss, "/") + WellKnownPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMetadataNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"unexpected response code %d from request: %w",
+			resp.StatusCode,
+			ErrFetchMetadata,
+		)
+	}
+
+	var md Metadata
+
+	if err := json.NewDecoder(resp.Body).Decode(&md); err != nil {
+		return nil, err
+	}
+
+	if md.Issuer != iss {
+		return nil, fmt.Errorf("%w: expected '%s', got '%s'", ErrIssuerMismatch, iss, md.Issuer)
+	}
+
+	c.put(iss, md)
+
+	return &md, nil
+}
+
+// JWKSURI returns the issuer's configured JWKS URI, or else the jwks_uri from its discovery metadata.
+func (c *Client) JWKSURI(ctx context.Context, issuer *types.Issuer) (string, error) {
+	return c.resolve(ctx, issuer.URI, issuer.JWKSURI, "jwks_uri", func(md *Metadata) string {
+		return md.JWKSURI
+	})
+}
+
+// UserInfoEndpoint returns the issuer's configured UserInfo endpoint, or else the userinfo_endpoint
+// from its discovery metadata.
+func (c *Client) UserInfoEndpoint(ctx context.Context, issuer *types.Issuer) (string, error) {
+	return c.resolve(ctx, issuer.URI, issuer.UserInfoEndpoint, "userinfo_endpoint", func(md *Metadata) string {
+		return md.UserInfoEndpoint
+	})
+}
+
+// IntrospectionEndpoint returns the issuer's configured introspection endpoint, or else the
+// introspection_endpoint from its discovery metadata.
+func (c *Client) IntrospectionEndpoint(ctx context.Context, issuer *types.Issuer) (string, error) {
+	return c.resolve(ctx, issuer.URI, issuer.IntrospectionEndpoint, "introspection_endpoint", func(md *Metadata) string {
+		return md.IntrospectionEndpoint
+	})
+}
+
+func (c *Client) resolve(ctx context.Context, iss, override, name string, fn func(*Metadata) string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	md, err := c.Fetch(ctx, iss)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fn(md)
+	if endpoint == "" {
+		return "", fmt.Errorf("%w: %s", ErrEndpointNotFound, name)
+	}
+
+	return endpoint, nil
+}
+
+func (c *Client) get(iss string) (*Metadata, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.cache[iss]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	md := entry.metadata
+
+	return &md, true
+}
+
+func (c *Client) put(iss string, md Metadata) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache[iss] = cacheEntry{
+		metadata:  md,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
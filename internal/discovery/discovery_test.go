@@ -0,0 +1,193 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func newTestServer(t *testing.T, fn func(iss string) (int, Metadata)) (*httptest.Server, *int32) {
+	var requests int32
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		if r.URL.Path != WellKnownPath {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		status, md := fn(srv.URL + "/")
+
+		w.WriteHeader(status)
+
+		_ = json.NewEncoder(w).Encode(md)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+// TestFetch checks that discovery metadata is fetched, validated and cached.
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newTestServer(t, func(iss string) (int, Metadata) {
+		return http.StatusOK, Metadata{
+			Issuer:           iss,
+			JWKSURI:          iss + "keys",
+			UserInfoEndpoint: iss + "oidc/userinfo",
+		}
+	})
+
+	client := NewClient(srv.Client(), time.Minute)
+	iss := srv.URL + "/"
+
+	for i := 0; i < 2; i++ {
+		md, err := client.Fetch(context.Background(), iss)
+		if assert.NoError(t, err) {
+			assert.Equal(t, iss+"keys", md.JWKSURI)
+			assert.Equal(t, iss+"oidc/userinfo", md.UserInfoEndpoint)
+		}
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// TestFetchErrors checks that invalid discovery metadata is rejected and not cached.
+func TestFetchErrors(t *testing.T) {
+	t.Parallel()
+
+	type testServer func(iss string) (int, Metadata)
+
+	runFn := func(ctx context.Context, fn testServer) testingx.TestResult[*Metadata] {
+		srv, _ := newTestServer(t, fn)
+
+		client := NewClient(srv.Client(), time.Minute)
+
+		md, err := client.Fetch(ctx, srv.URL+"/")
+
+		return testingx.TestResult[*Metadata]{
+			Success: md,
+			Err:     err,
+		}
+	}
+
+	testCases := []testingx.TestCase[testServer, *Metadata]{
+		{
+			Name: "IssuerMismatch",
+			Input: func(iss string) (int, Metadata) {
+				return http.StatusOK, Metadata{Issuer: "https://evil.biz/"}
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*Metadata]) {
+				assert.ErrorIs(t, res.Err, ErrIssuerMismatch)
+			},
+		},
+		{
+			Name: "BadStatus",
+			Input: func(iss string) (int, Metadata) {
+				return http.StatusInternalServerError, Metadata{}
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*Metadata]) {
+				assert.ErrorIs(t, res.Err, ErrFetchMetadata)
+			},
+		},
+		{
+			Name: "NotFound",
+			Input: func(iss string) (int, Metadata) {
+				return http.StatusNotFound, Metadata{}
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*Metadata]) {
+				assert.ErrorIs(t, res.Err, ErrMetadataNotFound)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestResolve checks that configured endpoints take precedence over discovery metadata.
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newTestServer(t, func(iss string) (int, Metadata) {
+		return http.StatusOK, Metadata{
+			Issuer:  iss,
+			JWKSURI: iss + "keys",
+		}
+	})
+
+	client := NewClient(srv.Client(), time.Minute)
+	iss := srv.URL + "/"
+
+	type resolveFn func(context.Context, *types.Issuer) (string, error)
+
+	type resolveInput struct {
+		fn     resolveFn
+		issuer types.Issuer
+	}
+
+	runFn := func(ctx context.Context, input resolveInput) testingx.TestResult[string] {
+		endpoint, err := input.fn(ctx, &input.issuer)
+
+		return testingx.TestResult[string]{
+			Success: endpoint,
+			Err:     err,
+		}
+	}
+
+	testCases := []testingx.TestCase[resolveInput, string]{
+		{
+			Name: "Discovered",
+			Input: resolveInput{
+				fn:     client.JWKSURI,
+				issuer: types.Issuer{URI: iss},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, iss+"keys", res.Success)
+				}
+			},
+		},
+		{
+			Name: "Override",
+			Input: resolveInput{
+				fn: client.UserInfoEndpoint,
+				issuer: types.Issuer{
+					URI:              iss,
+					UserInfoEndpoint: "https://example.com/userinfo",
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, "https://example.com/userinfo", res.Success)
+				}
+			},
+		},
+		{
+			Name: "NotFound",
+			Input: resolveInput{
+				fn:     client.IntrospectionEndpoint,
+				issuer: types.Issuer{URI: iss},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				assert.ErrorIs(t, res.Err, ErrEndpointNotFound)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -0,0 +1,2 @@
+// Package discovery fetches and caches OpenID Connect discovery metadata for upstream issuers.
+package discovery
@@ -2,6 +2,8 @@ package fositex
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/token/jwt"
@@ -13,6 +15,9 @@ import (
 	"go.infratographer.com/identity-api/internal/types"
 )
 
+// DefaultSigningJWKSMaxAge is how long clients may cache the signing JWKS by default.
+const DefaultSigningJWKSMaxAge = 15 * time.Minute
+
 const (
 	// PrivateKeyTypePublic represents a public key type.
 	PrivateKeyTypePublic PrivateKeyType = "public"
@@ -28,6 +33,11 @@ type PrivateKey struct {
 	KeyID     string
 	Algorithm jose.SignatureAlgorithm
 	Path      string
+	// State is the key's rotation state. Defaults to active.
+	State KeyState
+	// ActivateAt is when a pending or active key starts signing tokens. Pending keys without an
+	// activation time are published but never sign tokens.
+	ActivateAt time.Time
 }
 
 // Config represents an application config section for Fosite.
@@ -38,6 +48,126 @@ type Config struct {
 	// When configuring an OAuth provider, the first private key will be used to sign
 	// JWTs.
 	PrivateKeys []PrivateKey
+	// VaultTransit configures signing JWTs with a HashiCorp Vault transit key in place of PrivateKeys.
+	VaultTransit VaultTransitConfig
+	// ManagedKeys configures signing JWTs with keys generated and stored in the storage engine in place
+	// of PrivateKeys.
+	ManagedKeys ManagedKeysConfig
+	// Subject configures the format of the subjects of issued tokens.
+	Subject SubjectConfig
+	// JWKS configures how issuers' JWKS are cached.
+	JWKS JWKSFetcherConfig
+	// JWKSSnapshotMaxStaleness is how old an issuer's last known good JWKS may be and still be used when
+	// its JWKS cannot be fetched. Zero disables the fallback.
+	JWKSSnapshotMaxStaleness time.Duration
+	// SigningJWKSMaxAge is how long clients may cache the published signing JWKS. Defaults to 15 minutes.
+	// It should be shorter than the time between publishing a new key and promoting it to sign tokens.
+	SigningJWKSMaxAge time.Duration
+	// TokenEncryption configures encrypting issued access tokens to their consumers.
+	TokenEncryption TokenEncryptionConfig
+	// TokenExchange configures the audiences and scopes exchanged tokens may be granted.
+	TokenExchange TokenExchangeConfig
+}
+
+// VaultTransitConfig represents the configuration of a HashiCorp Vault transit signing key.
+type VaultTransitConfig struct {
+	// Address is the address of the Vault server, such as https://vault.example.com:8200.
+	Address string
+	// Token is the Vault token used to authenticate.
+	Token string
+	// Mount is the path the transit secrets engine is mounted at. Defaults to "transit".
+	Mount string
+	// Key is the name of the transit key. If empty, Vault is not used.
+	Key string
+	// Algorithm is the JWT signing algorithm, which must match the type of the transit key.
+	Algorithm jose.SignatureAlgorithm
+	// RefreshInterval is how often the transit key's public keys are refreshed. Defaults to 5 minutes.
+	RefreshInterval time.Duration
+	// Timeout is how long a request to Vault may take. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// Enabled reports whether JWTs are signed with a Vault transit key.
+func (c VaultTransitConfig) Enabled() bool {
+	return c.Key != ""
+}
+
+// ManagedKeysConfig represents the configuration of signing keys generated and stored in the storage
+// engine.
+type ManagedKeysConfig struct {
+	// Enabled enables managed signing keys.
+	Enabled bool
+	// KEK is the base64-encoded 256-bit key-encryption key used to encrypt private keys before they are
+	// stored.
+	KEK string
+	// Algorithm is the signing algorithm of generated keys. Defaults to RS256.
+	Algorithm jose.SignatureAlgorithm
+	// RefreshInterval is how often keys are reloaded from storage, so changes made through any replica
+	// are picked up. Defaults to 30 seconds.
+	RefreshInterval time.Duration
+	// MinPublishAge is how long a generated key must have been published before it can be promoted, so
+	// every replica publishes it and verifiers' cached JWKS include it before it signs tokens. Defaults
+	// to the signing JWKS max age plus the refresh interval.
+	MinPublishAge time.Duration
+}
+
+// SubjectConfig represents the configuration of the subjects of issued tokens.
+type SubjectConfig struct {
+	// Template is the default subject template. It must contain exactly one "{id}" placeholder,
+	// which is replaced by the subject's identifier.
+	Template string
+	// TenantTemplates overrides Template for tokens exchanged from the issuers of a tenant, keyed by
+	// tenant ID.
+	TenantTemplates map[string]string
+	// Pairwise configures when pairwise subject identifiers are issued in place of the subject's
+	// user info ID.
+	Pairwise PairwiseSubjectConfig
+}
+
+// PairwiseSubjectConfig represents the configuration of pairwise subject identifiers.
+type PairwiseSubjectConfig struct {
+	// Tenants lists the tenants whose issuers' subjects are issued pairwise subject identifiers.
+	Tenants []string
+	// Clients lists the clients that are issued pairwise subject identifiers.
+	Clients []string
+	// Salt is mixed into pairwise subject identifiers, so they cannot be derived from a user info ID
+	// and sector alone.
+	Salt string
+}
+
+// TokenEncryptionConfig represents the configuration of access tokens encrypted to their consumers.
+type TokenEncryptionConfig struct {
+	// Recipients lists the consumers issued tokens are encrypted to. A token is encrypted to the first
+	// recipient matching its client or one of its requested audiences.
+	Recipients []TokenEncryptionRecipient
+}
+
+// TokenEncryptionRecipient represents a consumer whose tokens are issued as nested JWTs, encrypted to
+// the consumer's public key.
+type TokenEncryptionRecipient struct {
+	// ClientID matches tokens issued to the given client.
+	ClientID string
+	// Audience matches tokens with the given requested audience.
+	Audience string
+	// KeyPath is the path to the consumer's PEM-encoded RSA or ECDSA public key.
+	KeyPath string
+	// KeyID is the key ID set in the header of encrypted tokens, if any.
+	KeyID string
+	// KeyAlgorithm is the key management algorithm. Defaults to RSA-OAEP-256 for RSA keys and
+	// ECDH-ES+A256KW for ECDSA keys.
+	KeyAlgorithm jose.KeyAlgorithm
+	// ContentEncryption is the content encryption algorithm. Defaults to A256GCM.
+	ContentEncryption jose.ContentEncryption
+}
+
+// TokenExchangeConfig represents the configuration of the audiences and scopes exchanged tokens may be
+// granted. Requests for any other audience or scope are rejected.
+type TokenExchangeConfig struct {
+	// AllowedAudiences lists the audiences that may be requested with the audience parameter, such as
+	// the management API's audience. The user info endpoint's audience is always granted.
+	AllowedAudiences []string
+	// AllowedScopes lists the scopes that may be requested with the scope parameter.
+	AllowedScopes []string
 }
 
 // IssuerJWKSURIStrategy represents a strategy for getting the JWKS URI for a given issuer.
@@ -45,6 +175,29 @@ type IssuerJWKSURIStrategy interface {
 	GetIssuerJWKSURI(ctx context.Context, iss string) (string, error)
 }
 
+// IssuerJWKSStrategy represents a strategy for getting a JWKS configured inline for a given issuer.
+// IssuerJWKSURIStrategy implementations may also implement it, in which case an issuer's inline JWKS
+// is used in place of fetching its JWKS URI.
+type IssuerJWKSStrategy interface {
+	// GetIssuerJWKS returns the issuer's inline JWKS, or nil if the issuer's JWKS must be fetched.
+	GetIssuerJWKS(ctx context.Context, iss string) (*jose.JSONWebKeySet, error)
+}
+
+// IssuerJWKSSnapshotStrategy represents a strategy for keeping the last JWKS successfully fetched for an
+// issuer, to fall back to when the issuer's JWKS cannot be fetched.
+type IssuerJWKSSnapshotStrategy interface {
+	// SaveIssuerJWKSSnapshot records a JWKS successfully fetched for the issuer.
+	SaveIssuerJWKSSnapshot(ctx context.Context, iss string, jwks *jose.JSONWebKeySet) error
+	// GetIssuerJWKSSnapshot returns the last JWKS successfully fetched for the issuer, if it is recent
+	// enough to be used in place of the issuer's current JWKS.
+	GetIssuerJWKSSnapshot(ctx context.Context, iss string) (*jose.JSONWebKeySet, error)
+}
+
+// IssuerJWKSSnapshotStrategyProvider represents a provider of an IssuerJWKSSnapshotStrategy.
+type IssuerJWKSSnapshotStrategyProvider interface {
+	GetIssuerJWKSSnapshotStrategy(ctx context.Context) IssuerJWKSSnapshotStrategy
+}
+
 // IssuerJWKSURIStrategyProvider represents a provider for a IssuerJWKSURIStrategy.
 type IssuerJWKSURIStrategyProvider interface {
 	GetIssuerJWKSURIStrategy(ctx context.Context) IssuerJWKSURIStrategy
@@ -55,14 +208,47 @@ type SigningKeyProvider interface {
 	GetSigningKey(ctx context.Context) *jose.JSONWebKey
 }
 
+// Signer represents a signer of JWTs whose private keys are held by a remote service. Signers
+// implement jose.OpaqueSigner, so tokens are signed by using a Signer as the key of a jose.JSONWebKey;
+// Public returns the public key of the key version currently used to sign.
+type Signer interface {
+	jose.OpaqueSigner
+	// JWKS returns the public keys of every version of the signer's key that may have signed valid
+	// tokens.
+	JWKS() *jose.JSONWebKeySet
+	// WithContext returns a jose.OpaqueSigner that signs with the signer's key, using the given context
+	// for requests to the remote service.
+	WithContext(ctx context.Context) jose.OpaqueSigner
+}
+
+// SigningKeyManager represents a manager of signing keys generated and stored in the storage engine.
+type SigningKeyManager interface {
+	// ListSigningKeys returns all managed signing keys, oldest first.
+	ListSigningKeys(ctx context.Context) ([]types.SigningKey, error)
+	// GenerateSigningKey generates a pending signing key with the given algorithm, or the default
+	// algorithm if none is given.
+	GenerateSigningKey(ctx context.Context, alg jose.SignatureAlgorithm) (*types.SigningKey, error)
+	// PromoteSigningKey makes the given key the active signing key.
+	PromoteSigningKey(ctx context.Context, id string) (*types.SigningKey, error)
+	// RetireSigningKey retires the given key, so it is no longer published.
+	RetireSigningKey(ctx context.Context, id string) (*types.SigningKey, error)
+}
+
+// SigningKeyManagerProvider represents a provider of a SigningKeyManager.
+type SigningKeyManagerProvider interface {
+	GetSigningKeyManager(ctx context.Context) SigningKeyManager
+}
+
 // SigningJWKSProvider represents a provider of a valid signing JWKS.
 type SigningJWKSProvider interface {
 	GetSigningJWKS(ctx context.Context) *jose.JSONWebKeySet
+	// GetSigningJWKSMaxAge returns how long clients may cache the signing JWKS.
+	GetSigningJWKSMaxAge(ctx context.Context) time.Duration
 }
 
 // ClaimMappingStrategy represents a strategy for mapping token claims to other claims.
 type ClaimMappingStrategy interface {
-	MapClaims(ctx context.Context, claims *jwt.JWTClaims) (jwt.JWTClaimsContainer, error)
+	MapClaims(ctx context.Context, claims *jwt.JWTClaims, mappingCtx types.ClaimMappingContext) (jwt.JWTClaimsContainer, error)
 }
 
 // ClaimMappingStrategyProvider represents a provider of a claims mapping strategy.
@@ -80,24 +266,85 @@ type UserInfoStrategyProvider interface {
 	GetUserInfoStrategy(ctx context.Context) UserInfoStrategy
 }
 
+// SubjectStrategy represents a strategy for formatting and parsing the subjects of issued tokens.
+type SubjectStrategy interface {
+	// FormatSubject formats the subject for the given user info, exchanged from a token issued by iss
+	// for the given client and audience.
+	FormatSubject(ctx context.Context, iss string, userInfo *types.UserInfo, clientID string, audience []string) (string, error)
+	// ParseSubject parses a subject produced by FormatSubject, returning the ID of the user info it
+	// refers to.
+	ParseSubject(ctx context.Context, sub string) (string, error)
+}
+
+// SubjectStrategyProvider represents a provider of a SubjectStrategy.
+type SubjectStrategyProvider interface {
+	GetSubjectStrategy(ctx context.Context) SubjectStrategy
+}
+
+// TokenEncryptionStrategy represents a strategy for encrypting issued access tokens to their consumers.
+type TokenEncryptionStrategy interface {
+	// GetTokenEncrypter returns the encrypter for tokens issued to the given client and requested
+	// audience, or nil if those tokens are not encrypted.
+	GetTokenEncrypter(ctx context.Context, clientID string, audience []string) (jose.Encrypter, error)
+}
+
+// TokenEncryptionStrategyProvider represents a provider of a TokenEncryptionStrategy.
+type TokenEncryptionStrategyProvider interface {
+	GetTokenEncryptionStrategy(ctx context.Context) TokenEncryptionStrategy
+}
+
+// TokenExchangeConfigProvider represents a provider of a TokenExchangeConfig.
+type TokenExchangeConfigProvider interface {
+	GetTokenExchangeConfig(ctx context.Context) TokenExchangeConfig
+}
+
 // OAuth2Configurator represents an OAuth2 configuration.
 type OAuth2Configurator interface {
 	fosite.Configurator
 	IssuerJWKSURIStrategyProvider
+	IssuerJWKSSnapshotStrategyProvider
 	SigningKeyProvider
 	SigningJWKSProvider
+	SigningKeyManagerProvider
 	ClaimMappingStrategyProvider
 	UserInfoStrategyProvider
+	SubjectStrategyProvider
+	TokenEncryptionStrategyProvider
+	TokenExchangeConfigProvider
+	ServerMetadataProvider
 }
 
 // OAuth2Config represents a Fosite OAuth 2.0 provider configuration.
 type OAuth2Config struct {
 	*fosite.Config
-	SigningKey            *jose.JSONWebKey
-	SigningJWKS           *jose.JSONWebKeySet
+	// SigningKey is the key used to sign tokens, if the config was not built from rotating keys.
+	SigningKey *jose.JSONWebKey
+	// SigningJWKS is the set of published signing keys, if the config was not built from rotating keys.
+	// This includes private keys.
+	SigningJWKS *jose.JSONWebKeySet
+	// Signer is optional. If set, tokens are signed by the Signer in place of SigningKey, and its keys
+	// are published in place of SigningJWKS.
+	Signer Signer
+	// SigningKeyManager is optional. If nil, managed signing keys are not enabled.
+	SigningKeyManager SigningKeyManager
+	// SigningJWKSMaxAge is how long clients may cache the signing JWKS. Defaults to
+	// DefaultSigningJWKSMaxAge.
+	SigningJWKSMaxAge     time.Duration
 	IssuerJWKSURIStrategy IssuerJWKSURIStrategy
-	ClaimMappingStrategy  ClaimMappingStrategy
-	UserInfoStrategy      UserInfoStrategy
+	// IssuerJWKSSnapshotStrategy is optional. If nil, exchanges fail when an issuer's JWKS cannot be
+	// fetched.
+	IssuerJWKSSnapshotStrategy IssuerJWKSSnapshotStrategy
+	ClaimMappingStrategy       ClaimMappingStrategy
+	UserInfoStrategy           UserInfoStrategy
+	SubjectStrategy            SubjectStrategy
+	// TokenEncryptionStrategy is optional. If nil, issued tokens are not encrypted.
+	TokenEncryptionStrategy TokenEncryptionStrategy
+	// TokenExchange lists the audiences and scopes exchanged tokens may be granted.
+	TokenExchange TokenExchangeConfig
+
+	signingKeys          atomic.Pointer[signingKeys]
+	subjectTypes         []string
+	metadataContributors []ServerMetadataContributor
 }
 
 // GetIssuerJWKSURIStrategy returns the config's IssuerJWKSURIStrategy.
@@ -105,16 +352,127 @@ func (c *OAuth2Config) GetIssuerJWKSURIStrategy(ctx context.Context) IssuerJWKSU
 	return c.IssuerJWKSURIStrategy
 }
 
-// GetSigningKey returns the config's signing key.
+// GetIssuerJWKSSnapshotStrategy returns the config's IssuerJWKSSnapshotStrategy.
+func (c *OAuth2Config) GetIssuerJWKSSnapshotStrategy(ctx context.Context) IssuerJWKSSnapshotStrategy {
+	return c.IssuerJWKSSnapshotStrategy
+}
+
+// GetSigningKey returns the config's signing key. When the config has a Signer, the key signs with it
+// using the given context. When the config was built from rotating keys, this is the key that most
+// recently became active.
 func (c *OAuth2Config) GetSigningKey(ctx context.Context) *jose.JSONWebKey {
+	if c.Signer != nil {
+		public := c.Signer.Public()
+
+		return &jose.JSONWebKey{
+			Key:       c.Signer.WithContext(ctx),
+			KeyID:     public.KeyID,
+			Algorithm: public.Algorithm,
+			Use:       "sig",
+		}
+	}
+
+	if keys := c.signingKeys.Load(); keys != nil {
+		return keys.signingKey(time.Now())
+	}
+
 	return c.SigningKey
 }
 
-// GetSigningJWKS returns the config's signing JWKS. This includes private keys.
+type signingKeyContextKey struct{}
+
+// ContextWithSigningKey returns a copy of ctx in which GetPrivateKey returns the given key. Resolving the
+// signing key once and passing it along ensures a token's key ID matches the key that signs it, even if
+// the signing key rotates while the token is being issued.
+func ContextWithSigningKey(ctx context.Context, key *jose.JSONWebKey) context.Context {
+	return context.WithValue(ctx, signingKeyContextKey{}, key)
+}
+
+// GetPrivateKey returns the key fosite's JWT strategies sign tokens with: the key in the context, if
+// any, or else the config's signing key. It can be used as a jwt.GetPrivateKeyFunc.
+func (c *OAuth2Config) GetPrivateKey(ctx context.Context) (any, error) {
+	if key, ok := ctx.Value(signingKeyContextKey{}).(*jose.JSONWebKey); ok {
+		return key, nil
+	}
+
+	return c.GetSigningKey(ctx), nil
+}
+
+// GetSigningJWKS returns the config's signing JWKS. This includes private keys, unless the config has a
+// Signer. The returned set is replaced, not modified, when the config's keys are reloaded.
 func (c *OAuth2Config) GetSigningJWKS(ctx context.Context) *jose.JSONWebKeySet {
+	if c.Signer != nil {
+		return c.Signer.JWKS()
+	}
+
+	if keys := c.signingKeys.Load(); keys != nil {
+		return keys.jwks()
+	}
+
 	return c.SigningJWKS
 }
 
+// GetSigningJWKSMaxAge returns how long clients may cache the signing JWKS. When a rotating key is
+// scheduled to start signing tokens sooner, this is the time until then, so clients revalidate the
+// JWKS as the rotation happens.
+func (c *OAuth2Config) GetSigningJWKSMaxAge(ctx context.Context) time.Duration {
+	maxAge := c.SigningJWKSMaxAge
+	if maxAge == 0 {
+		maxAge = DefaultSigningJWKSMaxAge
+	}
+
+	if keys := c.signingKeys.Load(); keys != nil && c.Signer == nil {
+		now := time.Now()
+
+		if next := keys.nextActivation(now); !next.IsZero() && next.Sub(now) < maxAge {
+			maxAge = next.Sub(now)
+		}
+	}
+
+	return maxAge
+}
+
+// ReloadPrivateKeys replaces the config's rotating keys with the given private keys. If the keys cannot
+// be read or none of them can sign tokens, the current keys are kept and an error is returned.
+func (c *OAuth2Config) ReloadPrivateKeys(keys []PrivateKey) error {
+	parsed, err := parsePrivateKeys(keys, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c.signingKeys.Store(parsed)
+
+	return nil
+}
+
+// ReloadManagedKeys replaces the config's rotating keys with the given managed keys. If none of the keys
+// can sign tokens, the current keys are kept and an error is returned.
+func (c *OAuth2Config) ReloadManagedKeys(keys []ManagedKey) error {
+	rotatingKeys := make([]rotatingKey, len(keys))
+
+	for i, key := range keys {
+		rotatingKeys[i] = rotatingKey{
+			jwk:        key.JWK,
+			state:      key.State,
+			activateAt: key.ActivateAt,
+		}
+	}
+
+	parsed, err := newSigningKeys(rotatingKeys, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c.signingKeys.Store(parsed)
+
+	return nil
+}
+
+// GetSigningKeyManager returns the config's SigningKeyManager.
+func (c *OAuth2Config) GetSigningKeyManager(ctx context.Context) SigningKeyManager {
+	return c.SigningKeyManager
+}
+
 // GetClaimMappingStrategy returns the config's claims mapping strategy.
 func (c *OAuth2Config) GetClaimMappingStrategy(ctx context.Context) ClaimMappingStrategy {
 	return c.ClaimMappingStrategy
@@ -125,6 +483,21 @@ func (c *OAuth2Config) GetUserInfoStrategy(ctx context.Context) UserInfoStrategy
 	return c.UserInfoStrategy
 }
 
+// GetSubjectStrategy returns the config's subject strategy.
+func (c *OAuth2Config) GetSubjectStrategy(ctx context.Context) SubjectStrategy {
+	return c.SubjectStrategy
+}
+
+// GetTokenEncryptionStrategy returns the config's token encryption strategy.
+func (c *OAuth2Config) GetTokenEncryptionStrategy(ctx context.Context) TokenEncryptionStrategy {
+	return c.TokenEncryptionStrategy
+}
+
+// GetTokenExchangeConfig returns the config's token exchange configuration.
+func (c *OAuth2Config) GetTokenExchangeConfig(ctx context.Context) TokenExchangeConfig {
+	return c.TokenExchange
+}
+
 // MustViperFlags sets the flags needed for Fosite to work.
 func MustViperFlags(v *viper.Viper, flags *pflag.FlagSet, defaultListen string) {
 	flags.String("issuer", "", "oauth token issuer")
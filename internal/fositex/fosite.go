@@ -4,6 +4,9 @@ package fositex
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -74,6 +77,26 @@ func readAsymmetricKey[T crypto.Signer](path string) (T, error) {
 	return signer, nil
 }
 
+// ecdsaCurves maps ECDSA signature algorithms to the curves they are defined for.
+var ecdsaCurves = map[jose.SignatureAlgorithm]elliptic.Curve{
+	jose.ES256: elliptic.P256(),
+	jose.ES384: elliptic.P384(),
+	jose.ES512: elliptic.P521(),
+}
+
+func readECDSAKey(path string, alg jose.SignatureAlgorithm) (*ecdsa.PrivateKey, error) {
+	key, err := readAsymmetricKey[*ecdsa.PrivateKey](path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Curve != ecdsaCurves[alg] {
+		return nil, fmt.Errorf("%w: curve %s cannot be used with %s", ErrInvalidKey, key.Curve.Params().Name, alg)
+	}
+
+	return key, nil
+}
+
 func readPrivateKey(key PrivateKey) (jose.JSONWebKey, error) {
 	var (
 		rawKey interface{}
@@ -83,6 +106,10 @@ func readPrivateKey(key PrivateKey) (jose.JSONWebKey, error) {
 	switch key.Algorithm {
 	case jose.RS256, jose.RS384, jose.RS512:
 		rawKey, err = readAsymmetricKey[*rsa.PrivateKey](key.Path)
+	case jose.ES256, jose.ES384, jose.ES512:
+		rawKey, err = readECDSAKey(key.Path, key.Algorithm)
+	case jose.EdDSA:
+		rawKey, err = readAsymmetricKey[ed25519.PrivateKey](key.Path)
 	case jose.HS256, jose.HS384, jose.HS512:
 		rawKey, err = readSymmetricKey(key.Path)
 	default:
@@ -98,46 +125,57 @@ func readPrivateKey(key PrivateKey) (jose.JSONWebKey, error) {
 		Key:       rawKey,
 		KeyID:     key.KeyID,
 		Algorithm: string(key.Algorithm),
+		Use:       "sig",
 	}
 
 	return out, nil
 }
 
-func parsePrivateKeys(keys []PrivateKey) (*jose.JSONWebKey, *jose.JSONWebKeySet, error) {
+func parsePrivateKeys(keys []PrivateKey, now time.Time) (*signingKeys, error) {
 	if len(keys) == 0 {
-		return nil, nil, fmt.Errorf("%w: no private keys provided", ErrInvalidKey)
-	}
-
-	first := keys[0]
-
-	signingKey, err := readPrivateKey(first)
-	if err != nil {
-		return nil, nil, err
+		return nil, fmt.Errorf("%w: no private keys provided", ErrInvalidKey)
 	}
 
-	var jwks jose.JSONWebKeySet
+	rotatingKeys := make([]rotatingKey, 0, len(keys))
 
-	jwks.Keys = append(jwks.Keys, signingKey)
-
-	for _, key := range keys[1:] {
+	for _, key := range keys {
 		jwk, err := readPrivateKey(key)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
-		var jwks jose.JSONWebKeySet
+		state := key.State
+		if state == "" {
+			state = KeyStateActive
+		}
 
-		jwks.Keys = append(jwks.Keys, jwk)
+		rotatingKeys = append(rotatingKeys, rotatingKey{
+			jwk:        jwk,
+			state:      state,
+			activateAt: key.ActivateAt,
+		})
 	}
 
-	return &signingKey, &jwks, nil
+	return newSigningKeys(rotatingKeys, now)
 }
 
 // NewOAuth2Config builds a new OAuth2Config from the given Config.
 func NewOAuth2Config(config Config) (*OAuth2Config, error) {
-	signingKey, jwks, err := parsePrivateKeys(config.PrivateKeys)
-	if err != nil {
-		return nil, err
+	if config.VaultTransit.Enabled() && config.ManagedKeys.Enabled {
+		return nil, fmt.Errorf("%w: vault transit and managed keys cannot both be enabled", ErrInvalidKey)
+	}
+
+	// Keys held by a Vault transit signer or the storage engine are not read here; they are loaded
+	// into the returned config once it is built.
+	var keys *signingKeys
+
+	if !config.VaultTransit.Enabled() && !config.ManagedKeys.Enabled {
+		var err error
+
+		keys, err = parsePrivateKeys(config.PrivateKeys, time.Now())
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	tokenLifespan := time.Second * time.Duration(config.AccessTokenLifespan)
@@ -148,9 +186,14 @@ func NewOAuth2Config(config Config) (*OAuth2Config, error) {
 	}
 
 	out := &OAuth2Config{
-		Config:      fositeConfig,
-		SigningKey:  signingKey,
-		SigningJWKS: jwks,
+		Config:            fositeConfig,
+		SigningJWKSMaxAge: config.SigningJWKSMaxAge,
+		TokenExchange:     config.TokenExchange,
+		subjectTypes:      subjectTypes(config.Subject),
+	}
+
+	if keys != nil {
+		out.signingKeys.Store(keys)
 	}
 
 	return out, nil
@@ -187,6 +230,10 @@ func NewOAuth2Provider(configurator *OAuth2Config, store interface{}, strategy i
 		if ph, ok := res.(fosite.PushedAuthorizeEndpointHandler); ok {
 			config.PushedAuthorizeEndpointHandlers.Append(ph)
 		}
+
+		if mc, ok := res.(ServerMetadataContributor); ok {
+			configurator.metadataContributors = append(configurator.metadataContributors, mc)
+		}
 	}
 
 	return f
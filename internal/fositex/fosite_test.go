@@ -0,0 +1,297 @@
+package fositex
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+func writePKCS8Key(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "privkey.pem")
+
+	block := &pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// TestReadPrivateKey checks that asymmetric signing keys are read for their algorithms, and that the
+// published public keys verify tokens signed by them.
+func TestReadPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runFn := func(ctx context.Context, key PrivateKey) testingx.TestResult[string] {
+		jwk, err := readPrivateKey(key)
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		signer, err := jose.NewSigner(
+			jose.SigningKey{
+				Algorithm: key.Algorithm,
+				Key:       jwk,
+			},
+			nil,
+		)
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		claims := josejwt.Claims{
+			Subject: "sub",
+		}
+
+		token, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		parsed, err := josejwt.ParseSigned(token)
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		public := jwk.Public()
+		if !public.Valid() {
+			return testingx.TestResult[string]{Err: ErrInvalidKey}
+		}
+
+		var out josejwt.Claims
+
+		err = parsed.Claims(public.Key, &out)
+
+		return testingx.TestResult[string]{
+			Success: out.Subject,
+			Err:     err,
+		}
+	}
+
+	checkSuccess := func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+		if assert.NoError(t, res.Err) {
+			assert.Equal(t, "sub", res.Success)
+		}
+	}
+
+	checkInvalid := func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+		assert.ErrorIs(t, res.Err, ErrInvalidKey)
+	}
+
+	testCases := []testingx.TestCase[PrivateKey, string]{
+		{
+			Name: "RS256",
+			Input: PrivateKey{
+				KeyID:     "rsa",
+				Algorithm: jose.RS256,
+				Path:      writePKCS8Key(t, rsaKey),
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "ES256",
+			Input: PrivateKey{
+				KeyID:     "p256",
+				Algorithm: jose.ES256,
+				Path:      writePKCS8Key(t, p256Key),
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "ES384",
+			Input: PrivateKey{
+				KeyID:     "p384",
+				Algorithm: jose.ES384,
+				Path:      writePKCS8Key(t, p384Key),
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "ES512",
+			Input: PrivateKey{
+				KeyID:     "p521",
+				Algorithm: jose.ES512,
+				Path:      writePKCS8Key(t, p521Key),
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "EdDSA",
+			Input: PrivateKey{
+				KeyID:     "ed25519",
+				Algorithm: jose.EdDSA,
+				Path:      writePKCS8Key(t, ed25519Key),
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "CurveMismatch",
+			Input: PrivateKey{
+				KeyID:     "p384",
+				Algorithm: jose.ES256,
+				Path:      writePKCS8Key(t, p384Key),
+			},
+			CheckFn: checkInvalid,
+		},
+		{
+			Name: "KeyTypeMismatch",
+			Input: PrivateKey{
+				KeyID:     "rsa",
+				Algorithm: jose.EdDSA,
+				Path:      writePKCS8Key(t, rsaKey),
+			},
+			CheckFn: checkInvalid,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestReloadPrivateKeys checks that reloading replaces the signing key and JWKS, and that invalid keys
+// keep the current ones.
+func TestReloadPrivateKeys(t *testing.T) {
+	t.Parallel()
+
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldKeyPath := writePKCS8Key(t, oldKey)
+
+	runFn := func(ctx context.Context, keys []PrivateKey) testingx.TestResult[[]string] {
+		config, err := NewOAuth2Config(Config{
+			PrivateKeys: []PrivateKey{
+				{
+					KeyID:     "old",
+					Algorithm: jose.ES256,
+					Path:      oldKeyPath,
+				},
+			},
+		})
+		if err != nil {
+			return testingx.TestResult[[]string]{Err: err}
+		}
+
+		err = config.ReloadPrivateKeys(keys)
+
+		out := []string{config.GetSigningKey(ctx).KeyID}
+
+		for _, key := range config.GetSigningJWKS(ctx).Keys {
+			out = append(out, key.KeyID)
+		}
+
+		return testingx.TestResult[[]string]{
+			Success: out,
+			Err:     err,
+		}
+	}
+
+	testCases := []testingx.TestCase[[]PrivateKey, []string]{
+		{
+			Name: "MissingFile",
+			Input: []PrivateKey{
+				{
+					KeyID:     "new",
+					Algorithm: jose.ES256,
+					Path:      filepath.Join(t.TempDir(), "missing.pem"),
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+				assert.Error(t, res.Err)
+				assert.Equal(t, []string{"old", "old"}, res.Success)
+			},
+		},
+		{
+			Name: "NoActiveKey",
+			Input: []PrivateKey{
+				{
+					KeyID:     "new",
+					Algorithm: jose.ES256,
+					Path:      writePKCS8Key(t, newKey),
+					State:     KeyStatePending,
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+				assert.ErrorIs(t, res.Err, ErrInvalidKey)
+				assert.Equal(t, []string{"old", "old"}, res.Success)
+			},
+		},
+		{
+			Name: "Success",
+			Input: []PrivateKey{
+				{
+					KeyID:     "old",
+					Algorithm: jose.ES256,
+					Path:      oldKeyPath,
+					State:     KeyStateRetiring,
+				},
+				{
+					KeyID:     "new",
+					Algorithm: jose.ES256,
+					Path:      writePKCS8Key(t, newKey),
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+				assert.NoError(t, res.Err)
+				assert.Equal(t, []string{"new", "old", "new"}, res.Success)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -0,0 +1,407 @@
+package fositex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ory/fosite"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	// DefaultJWKSTTL is how long a JWKS is cached when its response does not specify a max-age.
+	DefaultJWKSTTL = time.Hour
+	// DefaultJWKSMinTTL is the minimum time a JWKS is cached, regardless of its response's Cache-Control.
+	DefaultJWKSMinTTL = time.Minute
+	// DefaultJWKSMaxTTL is the maximum time a JWKS is cached, regardless of its response's Cache-Control.
+	DefaultJWKSMaxTTL = 24 * time.Hour
+	// DefaultJWKSRefreshInterval is how often cached JWKS that are about to expire are refreshed.
+	DefaultJWKSRefreshInterval = time.Minute
+	// DefaultJWKSForcedRefreshInterval is the minimum time between forced refreshes of a JWKS.
+	DefaultJWKSForcedRefreshInterval = time.Minute
+	// DefaultJWKSIdleTTL is how long a cached JWKS that is not used is kept before it is evicted.
+	DefaultJWKSIdleTTL = 24 * time.Hour
+	// DefaultJWKSRequestTimeout is how long a request for a JWKS may take.
+	DefaultJWKSRequestTimeout = 10 * time.Second
+)
+
+// ErrFetchJWKS represents a failure when fetching a JWKS.
+var ErrFetchJWKS = errors.New("could not fetch JWKS")
+
+// JWKSFetcherConfig represents the configuration of a JWKSFetcher. Zero values use the defaults.
+type JWKSFetcherConfig struct {
+	// TTL is how long a JWKS is cached when its response does not specify a max-age.
+	TTL time.Duration
+	// MinTTL and MaxTTL bound how long a JWKS is cached.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	// RefreshInterval is how often cached JWKS that are about to expire are refreshed in the background.
+	RefreshInterval time.Duration
+	// ForcedRefreshInterval is the minimum time between forced refreshes of a JWKS, such as when a token
+	// is signed with an unknown key ID.
+	ForcedRefreshInterval time.Duration
+	// IdleTTL is how long a cached JWKS that is not used is kept before it is evicted, rather than
+	// refreshed in the background.
+	IdleTTL time.Duration
+	// RequestTimeout is how long a request for a JWKS may take.
+	RequestTimeout time.Duration
+}
+
+func (c JWKSFetcherConfig) withDefaults() JWKSFetcherConfig {
+	if c.TTL == 0 {
+		c.TTL = DefaultJWKSTTL
+	}
+
+	if c.MinTTL == 0 {
+		c.MinTTL = DefaultJWKSMinTTL
+	}
+
+	if c.MaxTTL == 0 {
+		c.MaxTTL = DefaultJWKSMaxTTL
+	}
+
+	if c.RefreshInterval == 0 {
+		c.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+
+	if c.ForcedRefreshInterval == 0 {
+		c.ForcedRefreshInterval = DefaultJWKSForcedRefreshInterval
+	}
+
+	if c.IdleTTL == 0 {
+		c.IdleTTL = DefaultJWKSIdleTTL
+	}
+
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = DefaultJWKSRequestTimeout
+	}
+
+	return c
+}
+
+// JWKSFetcherStats represents counters describing the use of a JWKSFetcher's cache.
+type JWKSFetcherStats struct {
+	// Hits counts resolutions served from the cache.
+	Hits uint64
+	// Misses counts resolutions that required a fetch.
+	Misses uint64
+	// Fetches counts requests made to JWKS locations.
+	Fetches uint64
+	// FetchErrors counts requests to JWKS locations that failed.
+	FetchErrors uint64
+	// ForcedRefreshes counts forced refreshes that were allowed.
+	ForcedRefreshes uint64
+	// RateLimitedRefreshes counts forced refreshes that were served from the cache because the JWKS was
+	// refreshed too recently.
+	RateLimitedRefreshes uint64
+	// BackgroundRefreshes counts JWKS refreshed in the background.
+	BackgroundRefreshes uint64
+	// Evictions counts cached JWKS evicted because they were not used within the idle TTL.
+	Evictions uint64
+	// Entries is the number of cached JWKS.
+	Entries int
+}
+
+type jwksEntry struct {
+	set       *jose.JSONWebKeySet
+	fetchedAt time.Time
+	expiresAt time.Time
+
+	// lastUsed is when the entry was last resolved, in Unix nanoseconds. It is accessed atomically.
+	lastUsed int64
+}
+
+func (e *jwksEntry) touch(now time.Time) {
+	atomic.StoreInt64(&e.lastUsed, now.UnixNano())
+}
+
+func (e *jwksEntry) idleSince(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&e.lastUsed)))
+}
+
+type jwksCall struct {
+	done  chan struct{}
+	entry *jwksEntry
+	err   error
+}
+
+// JWKSFetcher is a fosite.JWKSFetcherStrategy that caches JWKS for as long as their responses'
+// Cache-Control allows, rate limits forced refreshes, refreshes cached JWKS in the background before
+// they expire, evicts cached JWKS that are no longer used, and collapses concurrent fetches of the same
+// location into one request.
+type JWKSFetcher struct {
+	config     JWKSFetcherConfig
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu       sync.RWMutex
+	entries  map[string]*jwksEntry
+	inflight map[string]*jwksCall
+
+	hits                 uint64
+	misses               uint64
+	fetches              uint64
+	fetchErrors          uint64
+	forcedRefreshes      uint64
+	rateLimitedRefreshes uint64
+	backgroundRefreshes  uint64
+	evictions            uint64
+}
+
+// implement the fosite.JWKSFetcherStrategy interface
+var _ fosite.JWKSFetcherStrategy = (*JWKSFetcher)(nil)
+
+// NewJWKSFetcher creates a new JWKSFetcher using the given HTTP client. Each request is bounded by the
+// configured request timeout, in addition to any timeout of the client.
+func NewJWKSFetcher(config JWKSFetcherConfig, httpClient *http.Client) *JWKSFetcher {
+	return &JWKSFetcher{
+		config:     config.withDefaults(),
+		httpClient: httpClient,
+		now:        time.Now,
+		entries:    make(map[string]*jwksEntry),
+		inflight:   make(map[string]*jwksCall),
+	}
+}
+
+// Resolve returns the JWKS at the given location. If ignoreCache is true, the JWKS is fetched again
+// unless it was fetched within the forced refresh interval.
+func (f *JWKSFetcher) Resolve(ctx context.Context, location string, ignoreCache bool) (*jose.JSONWebKeySet, error) {
+	now := f.now()
+
+	f.mu.RLock()
+	entry, ok := f.entries[location]
+	f.mu.RUnlock()
+
+	switch {
+	case !ok || now.After(entry.expiresAt):
+		atomic.AddUint64(&f.misses, 1)
+	case !ignoreCache:
+		atomic.AddUint64(&f.hits, 1)
+		entry.touch(now)
+
+		return entry.set, nil
+	case now.Sub(entry.fetchedAt) < f.config.ForcedRefreshInterval:
+		atomic.AddUint64(&f.rateLimitedRefreshes, 1)
+		entry.touch(now)
+
+		return entry.set, nil
+	default:
+		atomic.AddUint64(&f.forcedRefreshes, 1)
+	}
+
+	entry, err := f.fetch(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.touch(now)
+
+	return entry.set, nil
+}
+
+// Stats returns the fetcher's cache statistics.
+func (f *JWKSFetcher) Stats() JWKSFetcherStats {
+	f.mu.RLock()
+	entries := len(f.entries)
+	f.mu.RUnlock()
+
+	return JWKSFetcherStats{
+		Hits:                 atomic.LoadUint64(&f.hits),
+		Misses:               atomic.LoadUint64(&f.misses),
+		Fetches:              atomic.LoadUint64(&f.fetches),
+		FetchErrors:          atomic.LoadUint64(&f.fetchErrors),
+		ForcedRefreshes:      atomic.LoadUint64(&f.forcedRefreshes),
+		RateLimitedRefreshes: atomic.LoadUint64(&f.rateLimitedRefreshes),
+		BackgroundRefreshes:  atomic.LoadUint64(&f.backgroundRefreshes),
+		Evictions:            atomic.LoadUint64(&f.evictions),
+		Entries:              entries,
+	}
+}
+
+// StartBackgroundRefresh refreshes cached JWKS that will expire before the next refresh interval until
+// the given context is canceled. JWKS that fail to refresh remain cached until they expire. JWKS that
+// have not been used within the idle TTL are evicted instead of refreshed.
+func (f *JWKSFetcher) StartBackgroundRefresh(ctx context.Context) {
+	ticker := time.NewTicker(f.config.RefreshInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.refreshExpiring(ctx)
+			}
+		}
+	}()
+}
+
+func (f *JWKSFetcher) refreshExpiring(ctx context.Context) {
+	now := f.now()
+	deadline := now.Add(f.config.RefreshInterval)
+
+	var locations []string
+
+	f.mu.Lock()
+
+	for location, entry := range f.entries {
+		switch {
+		case entry.idleSince(now) > f.config.IdleTTL:
+			delete(f.entries, location)
+			atomic.AddUint64(&f.evictions, 1)
+		case entry.expiresAt.Before(deadline):
+			locations = append(locations, location)
+		}
+	}
+
+	f.mu.Unlock()
+
+	for _, location := range locations {
+		if _, err := f.fetch(ctx, location); err == nil {
+			atomic.AddUint64(&f.backgroundRefreshes, 1)
+		}
+	}
+}
+
+// fetch fetches the JWKS at the given location and caches it. Concurrent fetches of the same location
+// share a single request, which is not tied to any caller's context so that one caller giving up does
+// not fail the others; each caller stops waiting when its own context is done. A refreshed entry keeps
+// the last use time of the entry it replaces.
+func (f *JWKSFetcher) fetch(ctx context.Context, location string) (*jwksEntry, error) {
+	f.mu.Lock()
+
+	call, ok := f.inflight[location]
+	if !ok {
+		call = &jwksCall{
+			done: make(chan struct{}),
+		}
+
+		f.inflight[location] = call
+
+		go f.do(call, location)
+	}
+
+	f.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.entry, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// do makes the shared request for a call and caches its result.
+func (f *JWKSFetcher) do(call *jwksCall, location string) {
+	call.entry, call.err = f.request(context.Background(), location)
+
+	f.mu.Lock()
+
+	delete(f.inflight, location)
+
+	if call.err == nil {
+		if old, ok := f.entries[location]; ok {
+			call.entry.lastUsed = atomic.LoadInt64(&old.lastUsed)
+		} else {
+			call.entry.lastUsed = call.entry.fetchedAt.UnixNano()
+		}
+
+		f.entries[location] = call.entry
+	}
+
+	f.mu.Unlock()
+
+	close(call.done)
+}
+
+func (f *JWKSFetcher) request(ctx context.Context, location string) (*jwksEntry, error) {
+	atomic.AddUint64(&f.fetches, 1)
+
+	entry, err := f.doRequest(ctx, location)
+	if err != nil {
+		atomic.AddUint64(&f.fetchErrors, 1)
+
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (f *JWKSFetcher) doRequest(ctx context.Context, location string) (*jwksEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"unexpected response code %d from request: %w",
+			resp.StatusCode,
+			ErrFetchJWKS,
+		)
+	}
+
+	var set jose.JSONWebKeySet
+
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	now := f.now()
+
+	entry := &jwksEntry{
+		set:       &set,
+		fetchedAt: now,
+		expiresAt: now.Add(f.ttl(resp.Header.Get("Cache-Control"))),
+	}
+
+	return entry, nil
+}
+
+// ttl returns how long a response with the given Cache-Control header may be cached, bounded by the
+// configured minimum and maximum TTLs.
+func (f *JWKSFetcher) ttl(cacheControl string) time.Duration {
+	ttl := f.config.TTL
+
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "no-cache", "no-store":
+			return f.config.MinTTL
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	switch {
+	case ttl < f.config.MinTTL:
+		return f.config.MinTTL
+	case ttl > f.config.MaxTTL:
+		return f.config.MaxTTL
+	default:
+		return ttl
+	}
+}
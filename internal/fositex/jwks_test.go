@@ -0,0 +1,360 @@
+package fositex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newJWKSTestServer(t *testing.T, cacheControl string, release <-chan struct{}) (*httptest.Server, *int32) {
+	var requests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+
+		if release != nil {
+			<-release
+		}
+
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		jwks := jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{
+					KeyID: string(rune('a' + n - 1)),
+					Key:   []byte("secret"),
+					Use:   "sig",
+				},
+			},
+		}
+
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func newTestJWKSFetcher(srv *httptest.Server) (*JWKSFetcher, *testClock) {
+	clock := &testClock{now: time.Now()}
+
+	fetcher := NewJWKSFetcher(JWKSFetcherConfig{}, srv.Client())
+	fetcher.now = clock.Now
+
+	return fetcher, clock
+}
+
+// TestJWKSFetcherTTL checks that JWKS are cached for as long as their Cache-Control allows.
+func TestJWKSFetcherTTL(t *testing.T) {
+	t.Parallel()
+
+	type ttlInput struct {
+		cacheControl string
+		advance      time.Duration
+	}
+
+	runFn := func(ctx context.Context, input ttlInput) testingx.TestResult[int32] {
+		srv, requests := newJWKSTestServer(t, input.cacheControl, nil)
+		fetcher, clock := newTestJWKSFetcher(srv)
+
+		if _, err := fetcher.Resolve(ctx, srv.URL, false); err != nil {
+			return testingx.TestResult[int32]{Err: err}
+		}
+
+		clock.Advance(input.advance)
+
+		_, err := fetcher.Resolve(ctx, srv.URL, false)
+
+		return testingx.TestResult[int32]{
+			Success: atomic.LoadInt32(requests),
+			Err:     err,
+		}
+	}
+
+	expectRequests := func(n int32) func(context.Context, *testing.T, testingx.TestResult[int32]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[int32]) {
+			if assert.NoError(t, res.Err) {
+				assert.Equal(t, n, res.Success)
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[ttlInput, int32]{
+		{
+			Name: "DefaultTTLCached",
+			Input: ttlInput{
+				advance: 59 * time.Minute,
+			},
+			CheckFn: expectRequests(1),
+		},
+		{
+			Name: "DefaultTTLExpired",
+			Input: ttlInput{
+				advance: 61 * time.Minute,
+			},
+			CheckFn: expectRequests(2),
+		},
+		{
+			Name: "MaxAgeExpired",
+			Input: ttlInput{
+				cacheControl: "public, max-age=300",
+				advance:      6 * time.Minute,
+			},
+			CheckFn: expectRequests(2),
+		},
+		{
+			Name: "MaxAgeBelowMinimum",
+			Input: ttlInput{
+				cacheControl: "max-age=5",
+				advance:      30 * time.Second,
+			},
+			CheckFn: expectRequests(1),
+		},
+		{
+			Name: "MaxAgeAboveMaximum",
+			Input: ttlInput{
+				cacheControl: "max-age=604800",
+				advance:      25 * time.Hour,
+			},
+			CheckFn: expectRequests(2),
+		},
+		{
+			Name: "NoStore",
+			Input: ttlInput{
+				cacheControl: "no-store",
+				advance:      2 * time.Minute,
+			},
+			CheckFn: expectRequests(2),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestJWKSFetcherForcedRefresh checks that forced refreshes are rate limited.
+func TestJWKSFetcherForcedRefresh(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv, requests := newJWKSTestServer(t, "", nil)
+	fetcher, clock := newTestJWKSFetcher(srv)
+
+	jwks, err := fetcher.Resolve(ctx, srv.URL, false)
+	require.NoError(t, err)
+	assert.Len(t, jwks.Key("a"), 1)
+
+	jwks, err = fetcher.Resolve(ctx, srv.URL, true)
+	require.NoError(t, err)
+	assert.Len(t, jwks.Key("a"), 1)
+
+	clock.Advance(2 * time.Minute)
+
+	jwks, err = fetcher.Resolve(ctx, srv.URL, true)
+	require.NoError(t, err)
+	assert.Len(t, jwks.Key("b"), 1)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	stats := fetcher.Stats()
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.ForcedRefreshes)
+	assert.Equal(t, uint64(1), stats.RateLimitedRefreshes)
+	assert.Equal(t, uint64(2), stats.Fetches)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+// TestJWKSFetcherConcurrent checks that concurrent fetches of the same JWKS share a single request.
+func TestJWKSFetcherConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	release := make(chan struct{})
+
+	srv, requests := newJWKSTestServer(t, "", release)
+	fetcher, _ := newTestJWKSFetcher(srv)
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, callers)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := fetcher.Resolve(ctx, srv.URL, false)
+			errs <- err
+		}()
+	}
+
+	// Wait for the first request to reach the server before letting it respond.
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(requests) == 1
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// TestJWKSFetcherCanceledCaller checks that a caller giving up on a shared fetch does not fail the
+// fetch for the other callers.
+func TestJWKSFetcherCanceledCaller(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	srv, requests := newJWKSTestServer(t, "", release)
+	fetcher, _ := newTestJWKSFetcher(srv)
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+
+	canceledErr := make(chan error, 1)
+
+	go func() {
+		_, err := fetcher.Resolve(canceledCtx, srv.URL, false)
+		canceledErr <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(requests) == 1
+	}, time.Second, time.Millisecond)
+
+	waiterErr := make(chan error, 1)
+
+	go func() {
+		_, err := fetcher.Resolve(context.Background(), srv.URL, false)
+		waiterErr <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		return fetcher.Stats().Misses == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-canceledErr, context.Canceled)
+
+	close(release)
+
+	assert.NoError(t, <-waiterErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// TestJWKSFetcherEviction checks that cached JWKS are evicted once they go unused for the idle TTL.
+func TestJWKSFetcherEviction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv, _ := newJWKSTestServer(t, "", nil)
+	fetcher, clock := newTestJWKSFetcher(srv)
+
+	idle, used := srv.URL+"/idle", srv.URL+"/used"
+
+	for _, location := range []string{idle, used} {
+		_, err := fetcher.Resolve(ctx, location, false)
+		require.NoError(t, err)
+	}
+
+	clock.Advance(DefaultJWKSIdleTTL - time.Hour)
+
+	_, err := fetcher.Resolve(ctx, used, false)
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Hour)
+
+	fetcher.refreshExpiring(ctx)
+
+	stats := fetcher.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 1, stats.Entries)
+
+	fetcher.mu.RLock()
+	_, ok := fetcher.entries[used]
+	fetcher.mu.RUnlock()
+
+	assert.True(t, ok)
+}
+
+// TestJWKSFetcherCollector checks that a fetcher's cache statistics are exported as metrics.
+func TestJWKSFetcherCollector(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv, _ := newJWKSTestServer(t, "", nil)
+	fetcher, _ := newTestJWKSFetcher(srv)
+
+	for i := 0; i < 2; i++ {
+		_, err := fetcher.Resolve(ctx, srv.URL, false)
+		require.NoError(t, err)
+	}
+
+	expected := `
+# HELP identityapi_jwks_fetcher_entries Number of cached JWKS.
+# TYPE identityapi_jwks_fetcher_entries gauge
+identityapi_jwks_fetcher_entries 1
+# HELP identityapi_jwks_fetcher_hits_total JWKS resolutions served from the cache.
+# TYPE identityapi_jwks_fetcher_hits_total counter
+identityapi_jwks_fetcher_hits_total 1
+# HELP identityapi_jwks_fetcher_misses_total JWKS resolutions that required a fetch.
+# TYPE identityapi_jwks_fetcher_misses_total counter
+identityapi_jwks_fetcher_misses_total 1
+`
+
+	err := testutil.CollectAndCompare(
+		NewJWKSFetcherCollector(fetcher),
+		strings.NewReader(expected),
+		"identityapi_jwks_fetcher_entries",
+		"identityapi_jwks_fetcher_hits_total",
+		"identityapi_jwks_fetcher_misses_total",
+	)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,87 @@
+package fositex
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const jwksMetricsNamespace = "identityapi_jwks_fetcher"
+
+type jwksFetcherMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(JWKSFetcherStats) float64
+}
+
+func newJWKSFetcherMetric(name, help string, valueType prometheus.ValueType, value func(JWKSFetcherStats) float64) jwksFetcherMetric {
+	return jwksFetcherMetric{
+		desc:      prometheus.NewDesc(prometheus.BuildFQName(jwksMetricsNamespace, "", name), help, nil, nil),
+		valueType: valueType,
+		value:     value,
+	}
+}
+
+type jwksFetcherCollector struct {
+	fetcher *JWKSFetcher
+	metrics []jwksFetcherMetric
+}
+
+// implement the prometheus.Collector interface
+var _ prometheus.Collector = (*jwksFetcherCollector)(nil)
+
+// NewJWKSFetcherCollector creates a prometheus.Collector exporting the given fetcher's cache statistics.
+func NewJWKSFetcherCollector(fetcher *JWKSFetcher) prometheus.Collector {
+	counter := func(name, help string, value func(JWKSFetcherStats) uint64) jwksFetcherMetric {
+		return newJWKSFetcherMetric(name, help, prometheus.CounterValue, func(stats JWKSFetcherStats) float64 {
+			return float64(value(stats))
+		})
+	}
+
+	return &jwksFetcherCollector{
+		fetcher: fetcher,
+		metrics: []jwksFetcherMetric{
+			counter("hits_total", "JWKS resolutions served from the cache.", func(s JWKSFetcherStats) uint64 {
+				return s.Hits
+			}),
+			counter("misses_total", "JWKS resolutions that required a fetch.", func(s JWKSFetcherStats) uint64 {
+				return s.Misses
+			}),
+			counter("fetches_total", "Requests made to JWKS locations.", func(s JWKSFetcherStats) uint64 {
+				return s.Fetches
+			}),
+			counter("fetch_errors_total", "Requests to JWKS locations that failed.", func(s JWKSFetcherStats) uint64 {
+				return s.FetchErrors
+			}),
+			counter("forced_refreshes_total", "Forced JWKS refreshes that were allowed.", func(s JWKSFetcherStats) uint64 {
+				return s.ForcedRefreshes
+			}),
+			counter("rate_limited_refreshes_total", "Forced JWKS refreshes served from the cache.", func(s JWKSFetcherStats) uint64 {
+				return s.RateLimitedRefreshes
+			}),
+			counter("background_refreshes_total", "JWKS refreshed in the background.", func(s JWKSFetcherStats) uint64 {
+				return s.BackgroundRefreshes
+			}),
+			counter("evictions_total", "Cached JWKS evicted after going unused.", func(s JWKSFetcherStats) uint64 {
+				return s.Evictions
+			}),
+			newJWKSFetcherMetric("entries", "Number of cached JWKS.", prometheus.GaugeValue, func(s JWKSFetcherStats) float64 {
+				return float64(s.Entries)
+			}),
+		},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *jwksFetcherCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *jwksFetcherCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.fetcher.Stats()
+
+	for _, metric := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value(stats))
+	}
+}
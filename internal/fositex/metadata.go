@@ -0,0 +1,93 @@
+package fositex
+
+import (
+	"context"
+)
+
+const (
+	// SubjectTypePublic is the subject type for subjects that are the same for every client.
+	SubjectTypePublic = "public"
+	// SubjectTypePairwise is the subject type for subjects that differ per client or audience.
+	SubjectTypePairwise = "pairwise"
+)
+
+// ServerMetadata represents the authorization server metadata published by identity-api, per
+// RFC 8414 and OpenID Connect Discovery 1.0.
+type ServerMetadata struct {
+	Issuer           string `json:"issuer"`
+	TokenEndpoint    string `json:"token_endpoint"`
+	JWKSURI          string `json:"jwks_uri"`
+	UserInfoEndpoint string `json:"userinfo_endpoint,omitempty"`
+	// ResponseTypesSupported is required by both specifications. identity-api has no authorization
+	// endpoint, so it is empty unless an endpoint handler contributes response types.
+	ResponseTypesSupported     []string `json:"response_types_supported"`
+	GrantTypesSupported        []string `json:"grant_types_supported"`
+	SubjectTokenTypesSupported []string `json:"subject_token_types_supported,omitempty"`
+	SubjectTypesSupported      []string `json:"subject_types_supported"`
+	// IDTokenSigningAlgValuesSupported lists the algorithms of the published signing keys, which sign
+	// every JWT identity-api issues.
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// ServerMetadataContributor is implemented by endpoint handlers that add to the server metadata, such
+// as the grant types they support.
+type ServerMetadataContributor interface {
+	ContributeServerMetadata(md *ServerMetadata)
+}
+
+// ServerMetadataProvider represents a provider of server metadata.
+type ServerMetadataProvider interface {
+	// GetServerMetadata returns the server metadata derived from the config and its registered endpoint
+	// handlers. Endpoint URLs are left to the caller, which knows where endpoints are routed.
+	GetServerMetadata(ctx context.Context) ServerMetadata
+}
+
+// GetServerMetadata returns the config's server metadata.
+func (c *OAuth2Config) GetServerMetadata(ctx context.Context) ServerMetadata {
+	md := ServerMetadata{
+		Issuer:                           c.GetAccessTokenIssuer(ctx),
+		ResponseTypesSupported:           []string{},
+		GrantTypesSupported:              []string{},
+		SubjectTokenTypesSupported:       []string{},
+		SubjectTypesSupported:            append([]string{}, c.subjectTypes...),
+		IDTokenSigningAlgValuesSupported: []string{},
+	}
+
+	if len(md.SubjectTypesSupported) == 0 {
+		md.SubjectTypesSupported = []string{SubjectTypePublic}
+	}
+
+	// Only the algorithms of keys published in the JWKS are listed. Symmetric keys are never published,
+	// since tokens they sign cannot be verified by relying parties.
+	if jwks := c.GetSigningJWKS(ctx); jwks != nil {
+		seen := make(map[string]bool, len(jwks.Keys))
+
+		for _, key := range jwks.Keys {
+			if key.Algorithm == "" || seen[key.Algorithm] {
+				continue
+			}
+
+			if public := key.Public(); !public.Valid() {
+				continue
+			}
+
+			seen[key.Algorithm] = true
+
+			md.IDTokenSigningAlgValuesSupported = append(md.IDTokenSigningAlgValuesSupported, key.Algorithm)
+		}
+	}
+
+	for _, contributor := range c.metadataContributors {
+		contributor.ContributeServerMetadata(&md)
+	}
+
+	return md
+}
+
+func subjectTypes(config SubjectConfig) []string {
+	if len(config.Pairwise.Tenants) > 0 || len(config.Pairwise.Clients) > 0 {
+		return []string{SubjectTypePublic, SubjectTypePairwise}
+	}
+
+	return []string{SubjectTypePublic}
+}
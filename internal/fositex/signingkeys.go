@@ -0,0 +1,141 @@
+package fositex
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	// KeyStatePending represents a key that is published but does not sign tokens until its activation
+	// time. Pending keys without an activation time are only published.
+	KeyStatePending KeyState = "pending"
+	// KeyStateActive represents a key that signs tokens once its activation time, if any, has passed.
+	KeyStateActive KeyState = "active"
+	// KeyStateRetiring represents a key that no longer signs tokens but is still published, so tokens
+	// it signed can be verified until they expire.
+	KeyStateRetiring KeyState = "retiring"
+	// KeyStateRetired represents a key that is neither published nor used to sign tokens.
+	KeyStateRetired KeyState = "retired"
+)
+
+// KeyState represents the rotation state of a signing key.
+type KeyState string
+
+// ManagedKey represents a signing key whose lifecycle is managed outside of the config file, such as a
+// key generated and stored in the storage engine.
+type ManagedKey struct {
+	JWK        jose.JSONWebKey
+	State      KeyState
+	ActivateAt time.Time
+}
+
+// rotatingKey represents a signing key and its place in the key rotation lifecycle.
+type rotatingKey struct {
+	jwk        jose.JSONWebKey
+	state      KeyState
+	activateAt time.Time
+}
+
+// signsAt reports whether the key may sign tokens at the given time.
+func (k rotatingKey) signsAt(now time.Time) bool {
+	switch k.state {
+	case KeyStateActive:
+		return !now.Before(k.activateAt)
+	case KeyStatePending:
+		return !k.activateAt.IsZero() && !now.Before(k.activateAt)
+	default:
+		return false
+	}
+}
+
+// signingKeys represents a set of signing keys at different stages of rotation.
+type signingKeys struct {
+	keys      []rotatingKey
+	published *jose.JSONWebKeySet
+}
+
+// newSigningKeys creates a new signingKeys, checking that some key can sign tokens at the given time.
+func newSigningKeys(keys []rotatingKey, now time.Time) (*signingKeys, error) {
+	for _, key := range keys {
+		switch key.state {
+		case KeyStatePending, KeyStateActive, KeyStateRetiring, KeyStateRetired:
+		default:
+			return nil, fmt.Errorf("%w: unknown state '%s' for key %s", ErrInvalidKey, key.state, key.jwk.KeyID)
+		}
+	}
+
+	out := &signingKeys{
+		keys: keys,
+		published: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{},
+		},
+	}
+
+	for _, key := range keys {
+		if key.state != KeyStateRetired {
+			out.published.Keys = append(out.published.Keys, key.jwk)
+		}
+	}
+
+	if out.signingKey(now) == nil {
+		return nil, fmt.Errorf("%w: no active signing key", ErrInvalidKey)
+	}
+
+	return out, nil
+}
+
+// signingKey returns the key that signs tokens at the given time, which is the key that most recently
+// became active. Keys that became active at the same time are preferred in the order they were given.
+func (k *signingKeys) signingKey(now time.Time) *jose.JSONWebKey {
+	var out *rotatingKey
+
+	for i := range k.keys {
+		key := &k.keys[i]
+
+		if !key.signsAt(now) {
+			continue
+		}
+
+		if out == nil || key.activateAt.After(out.activateAt) {
+			out = key
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	jwk := out.jwk
+
+	return &jwk
+}
+
+// nextActivation returns the earliest time after now that a key starts signing tokens, or the zero time
+// if no key is scheduled to.
+func (k *signingKeys) nextActivation(now time.Time) time.Time {
+	var out time.Time
+
+	for _, key := range k.keys {
+		if key.state != KeyStatePending && key.state != KeyStateActive {
+			continue
+		}
+
+		if !key.activateAt.After(now) {
+			continue
+		}
+
+		if out.IsZero() || key.activateAt.Before(out) {
+			out = key.activateAt
+		}
+	}
+
+	return out
+}
+
+// jwks returns the published keys, which are all keys that are not retired. Pending and retiring keys
+// are published so tokens signed by them can be verified during rotation.
+func (k *signingKeys) jwks() *jose.JSONWebKeySet {
+	return k.published
+}
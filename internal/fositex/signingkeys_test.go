@@ -0,0 +1,135 @@
+package fositex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+func testRotatingKey(kid string, state KeyState, activateAt time.Time) rotatingKey {
+	return rotatingKey{
+		jwk: jose.JSONWebKey{
+			Key:       []byte(kid),
+			KeyID:     kid,
+			Algorithm: string(jose.HS256),
+		},
+		state:      state,
+		activateAt: activateAt,
+	}
+}
+
+func jwksKeyIDs(jwks *jose.JSONWebKeySet) []string {
+	out := make([]string, len(jwks.Keys))
+
+	for i, key := range jwks.Keys {
+		out[i] = key.KeyID
+	}
+
+	return out
+}
+
+// TestSigningKeys checks that the signing key switches at activation times and that only unretired keys
+// are published.
+func TestSigningKeys(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	keys, err := newSigningKeys([]rotatingKey{
+		testRotatingKey("retired", KeyStateRetired, time.Time{}),
+		testRotatingKey("retiring", KeyStateRetiring, time.Time{}),
+		testRotatingKey("current", KeyStateActive, time.Time{}),
+		testRotatingKey("next", KeyStatePending, now.Add(time.Hour)),
+		testRotatingKey("prepublished", KeyStatePending, time.Time{}),
+	}, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"retiring", "current", "next", "prepublished"}, jwksKeyIDs(keys.jwks()))
+	assert.Equal(t, now.Add(time.Hour), keys.nextActivation(now))
+	assert.True(t, keys.nextActivation(now.Add(time.Hour)).IsZero())
+
+	runFn := func(ctx context.Context, at time.Time) testingx.TestResult[string] {
+		key := keys.signingKey(at)
+		if key == nil {
+			return testingx.TestResult[string]{}
+		}
+
+		return testingx.TestResult[string]{
+			Success: key.KeyID,
+		}
+	}
+
+	expectKey := func(kid string) func(context.Context, *testing.T, testingx.TestResult[string]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+			assert.Equal(t, kid, res.Success)
+		}
+	}
+
+	testCases := []testingx.TestCase[time.Time, string]{
+		{
+			Name:    "BeforeActivation",
+			Input:   now.Add(59 * time.Minute),
+			CheckFn: expectKey("current"),
+		},
+		{
+			Name:    "AtActivation",
+			Input:   now.Add(time.Hour),
+			CheckFn: expectKey("next"),
+		},
+		{
+			Name:    "AfterActivation",
+			Input:   now.Add(24 * time.Hour),
+			CheckFn: expectKey("next"),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestSigningKeysInvalid checks that key sets without a usable signing key are rejected.
+func TestSigningKeysInvalid(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	runFn := func(ctx context.Context, keys []rotatingKey) testingx.TestResult[*signingKeys] {
+		out, err := newSigningKeys(keys, now)
+
+		return testingx.TestResult[*signingKeys]{
+			Success: out,
+			Err:     err,
+		}
+	}
+
+	checkFn := func(ctx context.Context, t *testing.T, res testingx.TestResult[*signingKeys]) {
+		assert.ErrorIs(t, res.Err, ErrInvalidKey)
+	}
+
+	testCases := []testingx.TestCase[[]rotatingKey, *signingKeys]{
+		{
+			Name: "NoActiveKey",
+			Input: []rotatingKey{
+				testRotatingKey("retiring", KeyStateRetiring, time.Time{}),
+				testRotatingKey("next", KeyStatePending, now.Add(time.Hour)),
+			},
+			CheckFn: checkFn,
+		},
+		{
+			Name: "UnknownState",
+			Input: []rotatingKey{
+				testRotatingKey("current", KeyStateActive, time.Time{}),
+				testRotatingKey("unknown", KeyState("expired"), time.Time{}),
+			},
+			CheckFn: checkFn,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -0,0 +1,21 @@
+package jwks
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewSnapshotCollector creates a prometheus.Collector exporting the number of times the given strategy
+// used a snapshot in place of an issuer's current JWKS.
+func NewSnapshotCollector(s *SnapshotStrategy) prometheus.Collector {
+	return prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: "identityapi",
+			Subsystem: "jwks_snapshot",
+			Name:      "fallbacks_total",
+			Help:      "JWKS snapshots used in place of an issuer's current JWKS.",
+		},
+		func() float64 {
+			return float64(s.Fallbacks())
+		},
+	)
+}
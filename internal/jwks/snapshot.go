@@ -0,0 +1,122 @@
+package jwks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// ErrSnapshotTooStale is returned when an issuer's last known good JWKS is too old to be used.
+var ErrSnapshotTooStale = errors.New("JWKS snapshot is too stale")
+
+// SnapshotStrategy is a fositex.IssuerJWKSSnapshotStrategy that stores issuers' last known good JWKS
+// using a types.JWKSSnapshotService.
+type SnapshotStrategy struct {
+	issuerSvc    types.IssuerService
+	snapshotSvc  types.JWKSSnapshotService
+	maxStaleness time.Duration
+	logger       *zap.SugaredLogger
+	now          func() time.Time
+
+	mu    sync.Mutex
+	saved map[string]*jose.JSONWebKeySet
+
+	fallbacks uint64
+}
+
+// implement the fositex.IssuerJWKSSnapshotStrategy interface
+var _ fositex.IssuerJWKSSnapshotStrategy = (*SnapshotStrategy)(nil)
+
+// NewSnapshotStrategy creates a new SnapshotStrategy. Snapshots older than maxStaleness are not used.
+func NewSnapshotStrategy(
+	issuerSvc types.IssuerService,
+	snapshotSvc types.JWKSSnapshotService,
+	maxStaleness time.Duration,
+	logger *zap.SugaredLogger,
+) *SnapshotStrategy {
+	return &SnapshotStrategy{
+		issuerSvc:    issuerSvc,
+		snapshotSvc:  snapshotSvc,
+		maxStaleness: maxStaleness,
+		logger:       logger,
+		now:          time.Now,
+		saved:        make(map[string]*jose.JSONWebKeySet),
+	}
+}
+
+// SaveIssuerJWKSSnapshot stores the given JWKS as the issuer's last known good JWKS. JWKS fetchers return
+// the same *jose.JSONWebKeySet until they fetch the JWKS again, so a JWKS that was already saved is not
+// stored again.
+func (s *SnapshotStrategy) SaveIssuerJWKSSnapshot(ctx context.Context, iss string, jwks *jose.JSONWebKeySet) error {
+	s.mu.Lock()
+	saved := s.saved[iss] == jwks
+	s.mu.Unlock()
+
+	if saved {
+		return nil
+	}
+
+	issuer, err := s.issuerSvc.GetIssuerByURI(ctx, iss)
+	if err != nil {
+		return err
+	}
+
+	snapshot := types.JWKSSnapshot{
+		IssuerID:  issuer.ID,
+		JWKS:      jwks,
+		FetchedAt: s.now(),
+	}
+
+	if err := s.snapshotSvc.StoreJWKSSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.saved[iss] = jwks
+	s.mu.Unlock()
+
+	return nil
+}
+
+// GetIssuerJWKSSnapshot returns the issuer's last known good JWKS if it is no older than the max
+// staleness. Each use of a snapshot is logged and counted.
+func (s *SnapshotStrategy) GetIssuerJWKSSnapshot(ctx context.Context, iss string) (*jose.JSONWebKeySet, error) {
+	issuer, err := s.issuerSvc.GetIssuerByURI(ctx, iss)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.snapshotSvc.GetJWKSSnapshot(ctx, issuer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	age := s.now().Sub(snapshot.FetchedAt)
+	if age > s.maxStaleness {
+		return nil, fmt.Errorf("%w: fetched %s ago", ErrSnapshotTooStale, age.Round(time.Second))
+	}
+
+	atomic.AddUint64(&s.fallbacks, 1)
+
+	s.logger.Warnw(
+		"using last known good JWKS for issuer",
+		"issuer", iss,
+		"fetched_at", snapshot.FetchedAt,
+	)
+
+	return snapshot.JWKS, nil
+}
+
+// Fallbacks returns the number of times a snapshot was used in place of an issuer's current JWKS.
+func (s *SnapshotStrategy) Fallbacks() uint64 {
+	return atomic.LoadUint64(&s.fallbacks)
+}
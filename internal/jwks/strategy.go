@@ -5,6 +5,9 @@ import (
 	"context"
 	"fmt"
 
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/discovery"
 	"go.infratographer.com/identity-api/internal/fositex"
 	"go.infratographer.com/identity-api/internal/types"
 )
@@ -16,12 +19,19 @@ var (
 
 type issuerJWKSURIStrategy struct {
 	issuerSvc types.IssuerService
+	discovery *discovery.Client
 }
 
-// NewIssuerJWKSURIStrategy creates a new fosite.IssuerJWKSURIStrategy.
-func NewIssuerJWKSURIStrategy(issuerSvc types.IssuerService) fositex.IssuerJWKSURIStrategy {
+// implement the fositex.IssuerJWKSStrategy interface
+var _ fositex.IssuerJWKSStrategy = issuerJWKSURIStrategy{}
+
+// NewIssuerJWKSURIStrategy creates a new fosite.IssuerJWKSURIStrategy. Issuers without a configured
+// JWKS URI use the jwks_uri from their discovery metadata, fetched with the given discovery client.
+// The strategy also implements fositex.IssuerJWKSStrategy for issuers with an inline JWKS.
+func NewIssuerJWKSURIStrategy(issuerSvc types.IssuerService, discoveryClient *discovery.Client) fositex.IssuerJWKSURIStrategy {
 	out := issuerJWKSURIStrategy{
 		issuerSvc: issuerSvc,
+		discovery: discoveryClient,
 	}
 
 	return out
@@ -33,5 +43,14 @@ func (s issuerJWKSURIStrategy) GetIssuerJWKSURI(ctx context.Context, iss string)
 		return "", err
 	}
 
-	return issuer.JWKSURI, nil
+	return s.discovery.JWKSURI(ctx, issuer)
+}
+
+func (s issuerJWKSURIStrategy) GetIssuerJWKS(ctx context.Context, iss string) (*jose.JSONWebKeySet, error) {
+	issuer, err := s.issuerSvc.GetIssuerByURI(ctx, iss)
+	if err != nil {
+		return nil, err
+	}
+
+	return issuer.JWKS, nil
 }
@@ -0,0 +1,2 @@
+// Package keymanager generates token signing keys and stores them, encrypted, in the storage engine.
+package keymanager
@@ -0,0 +1,413 @@
+package keymanager
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/storage"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+const (
+	// DefaultAlgorithm is the default signing algorithm of generated keys.
+	DefaultAlgorithm = jose.RS256
+	// DefaultRefreshInterval is how often keys are reloaded from storage by default.
+	DefaultRefreshInterval = 30 * time.Second
+
+	rsaKeyBits = 3072
+)
+
+var (
+	// ErrInvalidKEK is returned when the configured key-encryption key is not a base64-encoded 256-bit key.
+	ErrInvalidKEK = errors.New("key-encryption key must be a base64-encoded 256-bit key")
+
+	// ErrUnsupportedAlgorithm is returned when keys cannot be generated for a signing algorithm.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing key algorithm")
+
+	// ErrInvalidStateTransition is returned when a key cannot be moved to the requested state.
+	ErrInvalidStateTransition = errors.New("invalid signing key state transition")
+
+	// ErrDecryptKey is returned when a stored private key cannot be decrypted.
+	ErrDecryptKey = errors.New("unable to decrypt signing key")
+
+	// ErrKeyNotPublished is returned when a pending key has not been published long enough to be
+	// promoted.
+	ErrKeyNotPublished = errors.New("signing key has not been published long enough to be promoted")
+)
+
+// ecdsaCurves maps ECDSA signature algorithms to the curves keys are generated with.
+var ecdsaCurves = map[jose.SignatureAlgorithm]elliptic.Curve{
+	jose.ES256: elliptic.P256(),
+	jose.ES384: elliptic.P384(),
+	jose.ES512: elliptic.P521(),
+}
+
+// KeyReloader represents a consumer of managed signing keys, such as an OAuth2Config.
+type KeyReloader interface {
+	ReloadManagedKeys(keys []fositex.ManagedKey) error
+	// GetSigningJWKSMaxAge returns how long clients may cache the signing JWKS.
+	GetSigningJWKSMaxAge(ctx context.Context) time.Duration
+}
+
+// Manager is a fositex.SigningKeyManager that stores signing keys in the storage engine. Private keys
+// are encrypted with AES-256-GCM using the configured key-encryption key.
+type Manager struct {
+	config   fositex.ManagedKeysConfig
+	kek      cipher.AEAD
+	store    types.SigningKeyService
+	reloader KeyReloader
+	logger   *zap.SugaredLogger
+	now      func() time.Time
+}
+
+// implement the fositex.SigningKeyManager interface
+var _ fositex.SigningKeyManager = (*Manager)(nil)
+
+// NewManager creates a new Manager, which loads stored keys into the given reloader.
+func NewManager(config fositex.ManagedKeysConfig, store types.SigningKeyService, reloader KeyReloader, logger *zap.SugaredLogger) (*Manager, error) {
+	kek, err := base64.StdEncoding.DecodeString(config.KEK)
+	if err != nil || len(kek) != 32 {
+		return nil, ErrInvalidKEK
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Algorithm == "" {
+		config.Algorithm = DefaultAlgorithm
+	}
+
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = DefaultRefreshInterval
+	}
+
+	if config.MinPublishAge == 0 {
+		config.MinPublishAge = reloader.GetSigningJWKSMaxAge(context.Background()) + config.RefreshInterval
+	}
+
+	out := &Manager{
+		config:   config,
+		kek:      aead,
+		store:    store,
+		reloader: reloader,
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	return out, nil
+}
+
+// Init generates and promotes a signing key if none are stored, then loads the stored keys.
+func (m *Manager) Init(ctx context.Context) error {
+	keys, err := m.store.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		key, err := m.generate(ctx, "")
+		if err != nil {
+			return err
+		}
+
+		// No tokens have been signed yet, so the initial key need not be published before it is used.
+		if _, err := m.promote(ctx, key, false); err != nil {
+			return err
+		}
+
+		m.logger.Infow("generated initial signing key", "kid", key.ID)
+	}
+
+	return m.Refresh(ctx)
+}
+
+// Refresh loads the stored keys into the reloader. If a key cannot be decrypted or no key can sign
+// tokens, the reloader keeps its current keys.
+func (m *Manager) Refresh(ctx context.Context) error {
+	keys, err := m.store.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	managedKeys := make([]fositex.ManagedKey, 0, len(keys))
+
+	for _, key := range keys {
+		state := fositex.KeyState(key.State)
+
+		// Retired keys are neither published nor used, so they are not decrypted.
+		if state == fositex.KeyStateRetired {
+			continue
+		}
+
+		privateKey, err := m.decrypt(key)
+		if err != nil {
+			return err
+		}
+
+		managedKeys = append(managedKeys, fositex.ManagedKey{
+			JWK: jose.JSONWebKey{
+				Key:       privateKey,
+				KeyID:     key.ID,
+				Algorithm: key.Algorithm,
+				Use:       "sig",
+			},
+			State:      state,
+			ActivateAt: key.ActivatedAt,
+		})
+	}
+
+	return m.reloader.ReloadManagedKeys(managedKeys)
+}
+
+// StartBackgroundRefresh reloads stored keys every refresh interval until the given context is
+// canceled, so keys generated, promoted or retired through other replicas are picked up.
+func (m *Manager) StartBackgroundRefresh(ctx context.Context) {
+	ticker := time.NewTicker(m.config.RefreshInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Refresh(ctx); err != nil {
+					m.logger.Errorw("unable to refresh signing keys, keeping current signing keys", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// refreshAfterCommit reloads the stored keys once the change in the context's transaction commits, so
+// changes made through this replica take effect without waiting for the next background refresh.
+func (m *Manager) refreshAfterCommit(ctx context.Context) {
+	storage.AfterCommit(ctx, func() {
+		if err := m.Refresh(context.Background()); err != nil {
+			m.logger.Errorw("unable to refresh signing keys, keeping current signing keys", "error", err)
+		}
+	})
+}
+
+// ListSigningKeys returns all stored signing keys, oldest first.
+func (m *Manager) ListSigningKeys(ctx context.Context) ([]types.SigningKey, error) {
+	return m.store.ListSigningKeys(ctx)
+}
+
+// GenerateSigningKey generates and stores a pending signing key with the given algorithm, or the
+// configured algorithm if none is given.
+func (m *Manager) GenerateSigningKey(ctx context.Context, alg jose.SignatureAlgorithm) (*types.SigningKey, error) {
+	out, err := m.generate(ctx, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	m.refreshAfterCommit(ctx)
+
+	return out, nil
+}
+
+func (m *Manager) generate(ctx context.Context, alg jose.SignatureAlgorithm) (*types.SigningKey, error) {
+	if alg == "" {
+		alg = m.config.Algorithm
+	}
+
+	privateKey, err := generateKey(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	kid := uuid.New().String()
+
+	nonce := make([]byte, m.kek.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	key := types.SigningKey{
+		ID:        kid,
+		Algorithm: string(alg),
+		State:     string(fositex.KeyStatePending),
+		PublicKey: &jose.JSONWebKey{
+			Key:       privateKey.Public(),
+			KeyID:     kid,
+			Algorithm: string(alg),
+			Use:       "sig",
+		},
+		// The key ID is authenticated with the private key, so stored keys cannot be swapped.
+		EncryptedPrivateKey: m.kek.Seal(nonce, nonce, der, []byte(kid)),
+		CreatedAt:           m.now().UTC(),
+	}
+
+	return m.store.CreateSigningKey(ctx, key)
+}
+
+// PromoteSigningKey makes the given pending or retiring key the active signing key. Keys that were
+// active become retiring, so they stay published until tokens they signed expire. Pending keys can only
+// be promoted once they have been published for the minimum publish age.
+func (m *Manager) PromoteSigningKey(ctx context.Context, id string) (*types.SigningKey, error) {
+	key, err := m.store.GetSigningKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := m.promote(ctx, key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	m.refreshAfterCommit(ctx)
+
+	return out, nil
+}
+
+func (m *Manager) promote(ctx context.Context, key *types.SigningKey, checkPublished bool) (*types.SigningKey, error) {
+	now := m.now().UTC()
+
+	switch fositex.KeyState(key.State) {
+	case fositex.KeyStateActive:
+		return key, nil
+	case fositex.KeyStatePending:
+		if publishedAt := key.CreatedAt.Add(m.config.MinPublishAge); checkPublished && now.Before(publishedAt) {
+			return nil, fmt.Errorf("%w: %s can be promoted after %s", ErrKeyNotPublished, key.ID, publishedAt.Format(time.RFC3339))
+		}
+	case fositex.KeyStateRetiring:
+	default:
+		return nil, fmt.Errorf("%w: %s keys cannot be promoted", ErrInvalidStateTransition, key.State)
+	}
+
+	keys, err := m.store.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	retiring := string(fositex.KeyStateRetiring)
+
+	for _, other := range keys {
+		if other.State != string(fositex.KeyStateActive) {
+			continue
+		}
+
+		update := types.SigningKeyUpdate{
+			State: &retiring,
+		}
+
+		if _, err := m.store.UpdateSigningKey(ctx, other.ID, update); err != nil {
+			return nil, err
+		}
+	}
+
+	active := string(fositex.KeyStateActive)
+
+	update := types.SigningKeyUpdate{
+		State:       &active,
+		ActivatedAt: &now,
+	}
+
+	return m.store.UpdateSigningKey(ctx, key.ID, update)
+}
+
+// RetireSigningKey retires the given key, so it is no longer published. The active signing key cannot
+// be retired; another key must be promoted first.
+func (m *Manager) RetireSigningKey(ctx context.Context, id string) (*types.SigningKey, error) {
+	key, err := m.store.GetSigningKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch fositex.KeyState(key.State) {
+	case fositex.KeyStateRetired:
+		return key, nil
+	case fositex.KeyStatePending, fositex.KeyStateRetiring:
+	default:
+		return nil, fmt.Errorf("%w: %s keys cannot be retired", ErrInvalidStateTransition, key.State)
+	}
+
+	retired := string(fositex.KeyStateRetired)
+
+	update := types.SigningKeyUpdate{
+		State: &retired,
+	}
+
+	out, err := m.store.UpdateSigningKey(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+
+	m.refreshAfterCommit(ctx)
+
+	return out, nil
+}
+
+func (m *Manager) decrypt(key types.SigningKey) (crypto.Signer, error) {
+	nonceSize := m.kek.NonceSize()
+
+	if len(key.EncryptedPrivateKey) < nonceSize {
+		return nil, fmt.Errorf("%w: %s", ErrDecryptKey, key.ID)
+	}
+
+	nonce, ciphertext := key.EncryptedPrivateKey[:nonceSize], key.EncryptedPrivateKey[nonceSize:]
+
+	der, err := m.kek.Open(nil, nonce, ciphertext, []byte(key.ID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecryptKey, key.ID)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDecryptKey, key.ID)
+	}
+
+	return signer, nil
+}
+
+func generateKey(alg jose.SignatureAlgorithm) (crypto.Signer, error) {
+	switch alg {
+	case jose.RS256, jose.RS384, jose.RS512:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case jose.ES256, jose.ES384, jose.ES512:
+		return ecdsa.GenerateKey(ecdsaCurves[alg], rand.Reader)
+	case jose.EdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+
+		return key, err
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+}
@@ -0,0 +1,269 @@
+package keymanager
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var testKEK = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+// memoryStore is an in-memory types.SigningKeyService.
+type memoryStore struct {
+	mu   sync.Mutex
+	keys map[string]types.SigningKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		keys: map[string]types.SigningKey{},
+	}
+}
+
+func (s *memoryStore) CreateSigningKey(ctx context.Context, key types.SigningKey) (*types.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+
+	return &key, nil
+}
+
+func (s *memoryStore) GetSigningKeyByID(ctx context.Context, id string) (*types.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, types.ErrorSigningKeyNotFound
+	}
+
+	return &key, nil
+}
+
+func (s *memoryStore) UpdateSigningKey(ctx context.Context, id string, update types.SigningKeyUpdate) (*types.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, types.ErrorSigningKeyNotFound
+	}
+
+	if update.State != nil {
+		key.State = *update.State
+	}
+
+	if update.ActivatedAt != nil {
+		key.ActivatedAt = *update.ActivatedAt
+	}
+
+	s.keys[id] = key
+
+	return &key, nil
+}
+
+func (s *memoryStore) ListSigningKeys(ctx context.Context) ([]types.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]types.SigningKey, 0, len(s.keys))
+
+	for _, key := range s.keys {
+		out = append(out, key)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+
+	return out, nil
+}
+
+func newTestManager(t *testing.T, store types.SigningKeyService, kek string) (*Manager, *fositex.OAuth2Config) {
+	oauth2Config := &fositex.OAuth2Config{}
+
+	config := fositex.ManagedKeysConfig{
+		Enabled:   true,
+		KEK:       kek,
+		Algorithm: jose.ES256,
+	}
+
+	manager, err := NewManager(config, store, oauth2Config, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return manager, oauth2Config
+}
+
+func publishedKeyIDs(jwks *jose.JSONWebKeySet) []string {
+	out := make([]string, len(jwks.Keys))
+
+	for i, key := range jwks.Keys {
+		out[i] = key.KeyID
+	}
+
+	return out
+}
+
+// TestManagerRotation checks that generated keys are published before they sign tokens, that pending
+// keys cannot be promoted before verifiers' cached JWKS include them, and that changes are reflected in
+// the loaded keys without waiting for a refresh.
+func TestManagerRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	manager, oauth2Config := newTestManager(t, store, testKEK)
+
+	// Keys must be activated in the past to sign tokens, so the manager's clock starts an hour ago.
+	now := time.Now().Add(-time.Hour)
+	manager.now = func() time.Time { return now }
+
+	if !assert.NoError(t, manager.Init(ctx)) {
+		return
+	}
+
+	initial := oauth2Config.GetSigningKey(ctx)
+	if !assert.NotNil(t, initial) {
+		return
+	}
+
+	assert.Equal(t, string(jose.ES256), initial.Algorithm)
+
+	// Keys are listed in the order they were created, so the next key is created after the initial one.
+	now = now.Add(time.Minute)
+
+	next, err := manager.GenerateSigningKey(ctx, jose.EdDSA)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, initial.KeyID, oauth2Config.GetSigningKey(ctx).KeyID)
+	assert.Equal(t, []string{initial.KeyID, next.ID}, publishedKeyIDs(oauth2Config.GetSigningJWKS(ctx)))
+
+	_, err = manager.RetireSigningKey(ctx, initial.KeyID)
+	assert.ErrorIs(t, err, ErrInvalidStateTransition)
+
+	_, err = manager.PromoteSigningKey(ctx, next.ID)
+	assert.ErrorIs(t, err, ErrKeyNotPublished)
+
+	now = now.Add(fositex.DefaultSigningJWKSMaxAge + DefaultRefreshInterval)
+
+	promoted, err := manager.PromoteSigningKey(ctx, next.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, string(fositex.KeyStateActive), promoted.State)
+	assert.Equal(t, next.ID, oauth2Config.GetSigningKey(ctx).KeyID)
+
+	previous, err := manager.RetireSigningKey(ctx, initial.KeyID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, string(fositex.KeyStateRetired), previous.State)
+
+	_, err = manager.PromoteSigningKey(ctx, initial.KeyID)
+	assert.ErrorIs(t, err, ErrInvalidStateTransition)
+
+	assert.Equal(t, []string{next.ID}, publishedKeyIDs(oauth2Config.GetSigningJWKS(ctx)))
+}
+
+// TestManagerRefreshInvalid checks that keys which cannot be loaded keep the current keys.
+func TestManagerRefreshInvalid(t *testing.T) {
+	t.Parallel()
+
+	runFn := func(ctx context.Context, setup func(ctx context.Context, store *memoryStore) error) testingx.TestResult[string] {
+		store := newMemoryStore()
+
+		manager, oauth2Config := newTestManager(t, store, testKEK)
+
+		if err := manager.Init(ctx); err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		current := oauth2Config.GetSigningKey(ctx).KeyID
+
+		if err := setup(ctx, store); err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		err := manager.Refresh(ctx)
+
+		// The signing key must be unchanged.
+		if oauth2Config.GetSigningKey(ctx).KeyID != current {
+			return testingx.TestResult[string]{}
+		}
+
+		return testingx.TestResult[string]{
+			Success: current,
+			Err:     err,
+		}
+	}
+
+	testCases := []testingx.TestCase[func(context.Context, *memoryStore) error, string]{
+		{
+			Name: "WrongKEK",
+			Input: func(ctx context.Context, store *memoryStore) error {
+				other, _ := newTestManager(t, store, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+
+				_, err := other.GenerateSigningKey(ctx, "")
+
+				return err
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				assert.ErrorIs(t, res.Err, ErrDecryptKey)
+				assert.NotEmpty(t, res.Success)
+			},
+		},
+		{
+			Name: "NoActiveKey",
+			Input: func(ctx context.Context, store *memoryStore) error {
+				keys, err := store.ListSigningKeys(ctx)
+				if err != nil {
+					return err
+				}
+
+				retiring := string(fositex.KeyStateRetiring)
+
+				_, err = store.UpdateSigningKey(ctx, keys[0].ID, types.SigningKeyUpdate{State: &retiring})
+
+				return err
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				assert.ErrorIs(t, res.Err, fositex.ErrInvalidKey)
+				assert.NotEmpty(t, res.Success)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestNewManagerInvalidKEK checks that key-encryption keys which are not 256-bit keys are rejected.
+func TestNewManagerInvalidKEK(t *testing.T) {
+	t.Parallel()
+
+	config := fositex.ManagedKeysConfig{
+		Enabled: true,
+		KEK:     base64.StdEncoding.EncodeToString([]byte("short")),
+	}
+
+	_, err := NewManager(config, newMemoryStore(), &fositex.OAuth2Config{}, zap.NewNop().Sugar())
+	assert.ErrorIs(t, err, ErrInvalidKEK)
+}
@@ -4,67 +4,274 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"sort"
+	"strings"
+	"unicode"
 
+	"github.com/google/uuid"
 	"github.com/ory/fosite/token/jwt"
 
 	"go.infratographer.com/identity-api/internal/celutils"
 	"go.infratographer.com/identity-api/internal/types"
 )
 
+const (
+	// GroupsClaim is the subject token claim upstream groups are read from when mapping roles.
+	GroupsClaim = "groups"
+
+	// RolesClaim is the claim roles mapped from upstream groups are issued in.
+	RolesClaim = "roles"
+)
+
 // ClaimMappingStrategy represents a mapping from external identity claims to identity-api claims.
 type ClaimMappingStrategy struct {
 	issuerSvc types.IssuerService
+	roleSvc   types.GroupRoleMappingService
 }
 
-// NewClaimMappingStrategy creates a ClaimMappingStrategy given an issuer service.
-func NewClaimMappingStrategy(issuerSvc types.IssuerService) ClaimMappingStrategy {
+// NewClaimMappingStrategy creates a ClaimMappingStrategy given an issuer service and a group to role
+// mapping service.
+func NewClaimMappingStrategy(issuerSvc types.IssuerService, roleSvc types.GroupRoleMappingService) ClaimMappingStrategy {
 	out := ClaimMappingStrategy{
 		issuerSvc: issuerSvc,
+		roleSvc:   roleSvc,
 	}
 
 	return out
 }
 
-// MapClaims consumes a set of JWT claims and produces a new set of mapped claims.
-func (m ClaimMappingStrategy) MapClaims(ctx context.Context, claims *jwt.JWTClaims) (jwt.JWTClaimsContainer, error) {
-	if claims.Subject == "" {
-		return nil, ErrorMissingSub
+// ClaimMappingResult represents the result of evaluating every claim mapping for a set of claims.
+type ClaimMappingResult struct {
+	// Claims contains the output of each mapping that evaluated successfully, along with the mapped
+	// roles if there are any and no mapping produces the roles claim itself.
+	Claims map[string]any
+	// Errors contains the evaluation error for each mapping that failed.
+	Errors map[string]error
+}
+
+// Err returns the error for the first failing mapping, ordered by claim name, or nil if every mapping
+// evaluated successfully.
+func (r ClaimMappingResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
 	}
 
-	if claims.Issuer == "" {
-		return nil, ErrorMissingIss
+	keys := make([]string, 0, len(r.Errors))
+	for k := range r.Errors {
+		keys = append(keys, k)
 	}
 
-	iss := claims.Issuer
+	sort.Strings(keys)
 
-	issuer, err := m.issuerSvc.GetIssuerByURI(ctx, iss)
-	if err != nil {
-		return nil, err
+	return r.Errors[keys[0]]
+}
+
+// EvaluateClaimMappings evaluates each of the given compiled mappings against the given claims in the
+// context of the given issuer and exchange, converting each output to a native value checked against its
+// declared output. Unlike MapClaims, evaluation does not stop at the first failing mapping; per-mapping
+// errors are collected in the result.
+func EvaluateClaimMappings(
+	ctx context.Context,
+	claims *jwt.JWTClaims,
+	issuer *types.Issuer,
+	mappings types.ClaimMappingPrograms,
+	outputs types.ClaimMappingOutputs,
+	mappingCtx types.ClaimMappingContext,
+) (ClaimMappingResult, error) {
+	if claims.Subject == "" {
+		return ClaimMappingResult{}, ErrorMissingSub
+	}
+
+	if claims.Issuer == "" {
+		return ClaimMappingResult{}, ErrorMissingIss
 	}
 
 	inputMap := claims.ToMapClaims()
-	outputMap := make(map[string]any, len(issuer.ClaimMappings))
 
 	subSHA256Bytes := sha256.Sum256([]byte(claims.Subject))
 	subSHA256 := hex.EncodeToString(subSHA256Bytes[0:])
 
 	inputEnv := map[string]any{
-		celutils.CELVariableClaims:    inputMap,
-		celutils.CELVariableSubSHA256: subSHA256,
+		celutils.CELVariableClaims:          inputMap,
+		celutils.CELVariableSubSHA256:       subSHA256,
+		celutils.CELVariableIssuer:          issuerEnv(issuer),
+		celutils.CELVariableClientID:        mappingCtx.ClientID,
+		celutils.CELVariableRequestedScopes: nonNilStrings(mappingCtx.RequestedScopes),
+		celutils.CELVariableAudience:        nonNilStrings(mappingCtx.Audience),
+		celutils.CELVariableUserInfo:        userInfoEnv(mappingCtx.UserInfo),
+		celutils.CELVariableRoles:           nonNilStrings(mappingCtx.Roles),
+	}
+
+	out := ClaimMappingResult{
+		Claims: make(map[string]any, len(mappings)),
+		Errors: make(map[string]error),
+	}
+
+	for k, v := range mappings {
+		val, err := celutils.EvalProgram(ctx, v, inputEnv)
+		if err != nil {
+			out.Errors[k] = err
+
+			continue
+		}
+
+		output := outputs[k]
+
+		native, err := celutils.ConvertOutput(val, output.Type)
+		if err != nil {
+			out.Errors[k] = err
+
+			continue
+		}
+
+		if native == nil && output.OmitNull {
+			continue
+		}
+
+		out.Claims[k] = native
+	}
+
+	if _, ok := mappings[RolesClaim]; !ok && len(mappingCtx.Roles) > 0 {
+		out.Claims[RolesClaim] = mappingCtx.Roles
+	}
+
+	return out, nil
+}
+
+// MapClaims consumes a set of JWT claims and produces a new set of mapped claims.
+func (m ClaimMappingStrategy) MapClaims(
+	ctx context.Context,
+	claims *jwt.JWTClaims,
+	mappingCtx types.ClaimMappingContext,
+) (jwt.JWTClaimsContainer, error) {
+	if claims.Subject == "" {
+		return nil, ErrorMissingSub
+	}
+
+	if claims.Issuer == "" {
+		return nil, ErrorMissingIss
 	}
 
-	for k, v := range issuer.ClaimMappings {
-		out, err := celutils.Eval(v, inputEnv)
+	issuer, err := m.issuerSvc.GetIssuerByURI(ctx, claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	mappingCtx.Roles, err = ResolveRoles(ctx, m.roleSvc, issuer, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	programs := issuer.ClaimMappingPrograms
+	if programs == nil {
+		programs, err = issuer.ClaimMappings.Compile()
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		outputMap[k] = out.Value()
+	result, err := EvaluateClaimMappings(ctx, claims, issuer, programs, issuer.ClaimMappingOutputs, mappingCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, err
 	}
 
 	var outputClaims jwt.JWTClaims
 
-	outputClaims.FromMap(outputMap)
+	outputClaims.FromMap(result.Claims)
 
 	return &outputClaims, nil
 }
+
+// ResolveRoles returns the roles mapped from the groups in the given claims for the given issuer.
+func ResolveRoles(
+	ctx context.Context,
+	roleSvc types.GroupRoleMappingService,
+	issuer *types.Issuer,
+	claims *jwt.JWTClaims,
+) ([]string, error) {
+	groups := GroupsFromClaims(claims)
+	if len(groups) == 0 {
+		return []string{}, nil
+	}
+
+	return roleSvc.LookupRolesByGroups(ctx, issuer.ID, groups)
+}
+
+// GroupsFromClaims normalizes the groups claim of the given claims into a list of group values.
+// Upstream issuers send groups in a variety of shapes, so the claim may be a list of strings, a list
+// of objects with a "name", "value" or "id" key, or a single string of comma or space separated groups.
+func GroupsFromClaims(claims *jwt.JWTClaims) []string {
+	var out []string
+
+	switch v := claims.ToMapClaims()[GroupsClaim].(type) {
+	case string:
+		out = strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	case []string:
+		out = v
+	case []any:
+		for _, el := range v {
+			if group := groupFromValue(el); group != "" {
+				out = append(out, group)
+			}
+		}
+	}
+
+	return out
+}
+
+func groupFromValue(v any) string {
+	switch group := v.(type) {
+	case string:
+		return group
+	case map[string]any:
+		for _, key := range []string{"name", "value", "id"} {
+			if s, ok := group[key].(string); ok {
+				return s
+			}
+		}
+	}
+
+	return ""
+}
+
+func issuerEnv(issuer *types.Issuer) map[string]string {
+	return map[string]string{
+		"id":        issuer.ID,
+		"tenant_id": issuer.TenantID,
+		"uri":       issuer.URI,
+		"name":      issuer.Name,
+	}
+}
+
+func userInfoEnv(info *types.UserInfo) map[string]any {
+	if info == nil {
+		return map[string]any{}
+	}
+
+	out := map[string]any{
+		"name":  info.Name,
+		"email": info.Email,
+		"sub":   info.Subject,
+		"iss":   info.Issuer,
+	}
+
+	if info.ID != uuid.Nil {
+		out["id"] = info.ID.String()
+	}
+
+	return out
+}
+
+func nonNilStrings(in []string) []string {
+	if in == nil {
+		return []string{}
+	}
+
+	return in
+}
@@ -0,0 +1,95 @@
+package rfc8693
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite/token/jwt"
+
+	"go.infratographer.com/identity-api/internal/storage"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// BenchmarkMapClaims measures exchange-path claim mapping latency with and without the issuer cache.
+// Uncached runs re-read the issuer, re-unmarshal its mappings and re-compile its programs on every call.
+func BenchmarkMapClaims(b *testing.B) {
+	cm := map[string]string{
+		"plusone":            "1 + claims.num",
+		"infratographer:sub": "'infratographer://example.com/' + subSHA256",
+		"tenant":             "issuer.tenant_id",
+		"email":              "userinfo.email.lowerAscii()",
+		"groups":             "claims.groups.split(',')",
+	}
+
+	mappingCtx := types.ClaimMappingContext{
+		ClientID: "my-client",
+		UserInfo: &types.UserInfo{
+			Email: "Foo@Example.com",
+		},
+	}
+
+	claims := &jwt.JWTClaims{
+		Subject: "foo",
+		Issuer:  "https://example.com/",
+		Extra: map[string]any{
+			"num":    2,
+			"groups": "admins,users",
+		},
+	}
+
+	benchmarks := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{
+			name: "Uncached",
+			ttl:  -1,
+		},
+		{
+			name: "Cached",
+			ttl:  time.Hour,
+		},
+	}
+
+	for _, bm := range benchmarks {
+		bm := bm
+
+		b.Run(bm.name, func(b *testing.B) {
+			cfg := storage.Config{
+				Type:           storage.EngineTypeMemory,
+				IssuerCacheTTL: bm.ttl,
+				SeedData: storage.SeedData{
+					Issuers: []storage.SeedIssuer{
+						{
+							TenantID:      "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+							ID:            "e495a393-ae79-4a02-a78d-9798c7d9d252",
+							Name:          "Example",
+							URI:           "https://example.com/",
+							JWKSURI:       "https://example.com/.well-known/jwks.json",
+							ClaimMappings: cm,
+						},
+					},
+				},
+			}
+
+			storageEngine, err := storage.NewEngine(cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			defer storageEngine.Shutdown()
+
+			strategy := NewClaimMappingStrategy(storageEngine, storageEngine)
+			ctx := context.Background()
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := strategy.MapClaims(ctx, claims, mappingCtx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
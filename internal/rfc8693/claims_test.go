@@ -10,6 +10,7 @@ import (
 	"go.infratographer.com/identity-api/internal/celutils"
 	"go.infratographer.com/identity-api/internal/storage"
 	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
 )
 
 // TestClaimMappingEval checks that claim mapping expressions evaluate correctly.
@@ -19,6 +20,17 @@ func TestClaimMappingEval(t *testing.T) {
 	cm := map[string]string{
 		"plusone":            "1 + claims.num",
 		"infratographer:sub": "'infratographer://example.com/' + subSHA256",
+		"tenant":             "issuer.tenant_id",
+		"client":             "client_id",
+		"email":              "userinfo.email",
+		"is_admin":           "'admin' in roles",
+	}
+
+	mappingCtx := types.ClaimMappingContext{
+		ClientID: "my-client",
+		UserInfo: &types.UserInfo{
+			Email: "foo@example.com",
+		},
 	}
 
 	cfg := storage.Config{
@@ -42,10 +54,33 @@ func TestClaimMappingEval(t *testing.T) {
 		assert.FailNow(t, "initialization failed")
 	}
 
-	strategy := NewClaimMappingStrategy(storageEngine)
+	roleMapping := types.GroupRoleMapping{
+		ID:       "5d0c1b1a-4a4c-4f5c-9e1b-6f3c1a2d7e8f",
+		TenantID: "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+		IssuerID: "e495a393-ae79-4a02-a78d-9798c7d9d252",
+		Group:    "admins",
+		Role:     "admin",
+	}
+
+	dbCtx, err := storageEngine.BeginContext(context.Background())
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "initialization failed")
+	}
+
+	_, err = storageEngine.CreateGroupRoleMapping(dbCtx, roleMapping)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "initialization failed")
+	}
+
+	err = storageEngine.CommitContext(dbCtx)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "initialization failed")
+	}
+
+	strategy := NewClaimMappingStrategy(storageEngine, storageEngine)
 
 	runFn := func(ctx context.Context, claims *jwt.JWTClaims) testingx.TestResult[jwt.JWTClaimsContainer] {
-		out, err := strategy.MapClaims(ctx, claims)
+		out, err := strategy.MapClaims(ctx, claims, mappingCtx)
 
 		return testingx.TestResult[jwt.JWTClaimsContainer]{
 			Success: out,
@@ -98,7 +133,8 @@ func TestClaimMappingEval(t *testing.T) {
 				Subject: "foo",
 				Issuer:  "https://example.com/",
 				Extra: map[string]any{
-					"num": 2,
+					"num":    2,
+					"groups": []any{"admins", "users"},
 				},
 			},
 			CheckFn: func(ctx context.Context, t *testing.T, result testingx.TestResult[jwt.JWTClaimsContainer]) {
@@ -107,6 +143,11 @@ func TestClaimMappingEval(t *testing.T) {
 					Extra: map[string]any{
 						"plusone":            int64(3),
 						"infratographer:sub": "infratographer://example.com/2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+						"tenant":             "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+						"client":             "my-client",
+						"email":              "foo@example.com",
+						"is_admin":           true,
+						"roles":              []string{"admin"},
 					},
 				}
 				assert.Equal(t, expected, result.Success)
@@ -116,3 +157,61 @@ func TestClaimMappingEval(t *testing.T) {
 
 	testingx.RunTests(context.Background(), t, testCases, runFn)
 }
+
+// TestGroupsFromClaims checks that groups claims of various shapes are normalized.
+func TestGroupsFromClaims(t *testing.T) {
+	t.Parallel()
+
+	runFn := func(ctx context.Context, groups any) testingx.TestResult[[]string] {
+		claims := &jwt.JWTClaims{
+			Extra: map[string]any{
+				GroupsClaim: groups,
+			},
+		}
+
+		return testingx.TestResult[[]string]{
+			Success: GroupsFromClaims(claims),
+		}
+	}
+
+	expectGroups := func(exp []string) func(context.Context, *testing.T, testingx.TestResult[[]string]) {
+		return func(ctx context.Context, t *testing.T, result testingx.TestResult[[]string]) {
+			assert.Equal(t, exp, result.Success)
+		}
+	}
+
+	testCases := []testingx.TestCase[any, []string]{
+		{
+			Name:    "List",
+			Input:   []any{"admins", "users"},
+			CheckFn: expectGroups([]string{"admins", "users"}),
+		},
+		{
+			Name:    "StringList",
+			Input:   []string{"admins", "users"},
+			CheckFn: expectGroups([]string{"admins", "users"}),
+		},
+		{
+			Name:    "DelimitedString",
+			Input:   "admins, users ops",
+			CheckFn: expectGroups([]string{"admins", "users", "ops"}),
+		},
+		{
+			Name: "Objects",
+			Input: []any{
+				map[string]any{"name": "admins"},
+				map[string]any{"value": "users"},
+				map[string]any{"id": "ops"},
+				map[string]any{"other": "ignored"},
+			},
+			CheckFn: expectGroups([]string{"admins", "users", "ops"}),
+		},
+		{
+			Name:    "Missing",
+			Input:   nil,
+			CheckFn: expectGroups(nil),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -0,0 +1,160 @@
+package rfc8693
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+const (
+	// DefaultRSAKeyAlgorithm is the key management algorithm used for RSA keys when none is configured.
+	DefaultRSAKeyAlgorithm = jose.RSA_OAEP_256
+	// DefaultECDSAKeyAlgorithm is the key management algorithm used for ECDSA keys when none is configured.
+	DefaultECDSAKeyAlgorithm = jose.ECDH_ES_A256KW
+	// DefaultContentEncryption is the content encryption algorithm used when none is configured.
+	DefaultContentEncryption = jose.A256GCM
+)
+
+// ErrInvalidTokenEncryptionRecipient is returned when a token encryption recipient is misconfigured.
+var ErrInvalidTokenEncryptionRecipient = errors.New("invalid token encryption recipient")
+
+type tokenEncryptionRecipient struct {
+	clientID  string
+	audience  string
+	encrypter jose.Encrypter
+}
+
+func (r tokenEncryptionRecipient) matches(clientID string, audience []string) bool {
+	if r.clientID != "" && r.clientID == clientID {
+		return true
+	}
+
+	if r.audience == "" {
+		return false
+	}
+
+	for _, aud := range audience {
+		if aud == r.audience {
+			return true
+		}
+	}
+
+	return false
+}
+
+type tokenEncryptionStrategy struct {
+	recipients []tokenEncryptionRecipient
+}
+
+// implement the fositex.TokenEncryptionStrategy interface
+var _ fositex.TokenEncryptionStrategy = (*tokenEncryptionStrategy)(nil)
+
+// NewTokenEncryptionStrategy creates a new token encryption strategy, which encrypts tokens to the
+// configured recipients' public keys.
+func NewTokenEncryptionStrategy(config fositex.TokenEncryptionConfig) (fositex.TokenEncryptionStrategy, error) {
+	recipients := make([]tokenEncryptionRecipient, len(config.Recipients))
+
+	for i, recipient := range config.Recipients {
+		if recipient.ClientID == "" && recipient.Audience == "" {
+			return nil, fmt.Errorf("%w: client ID or audience required", ErrInvalidTokenEncryptionRecipient)
+		}
+
+		encrypter, err := newTokenEncrypter(recipient)
+		if err != nil {
+			return nil, err
+		}
+
+		recipients[i] = tokenEncryptionRecipient{
+			clientID:  recipient.ClientID,
+			audience:  recipient.Audience,
+			encrypter: encrypter,
+		}
+	}
+
+	out := &tokenEncryptionStrategy{
+		recipients: recipients,
+	}
+
+	return out, nil
+}
+
+// GetTokenEncrypter returns the encrypter of the first recipient matching the given client or
+// requested audience, or nil if none match.
+func (s *tokenEncryptionStrategy) GetTokenEncrypter(ctx context.Context, clientID string, audience []string) (jose.Encrypter, error) {
+	for _, recipient := range s.recipients {
+		if recipient.matches(clientID, audience) {
+			return recipient.encrypter, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func newTokenEncrypter(recipient fositex.TokenEncryptionRecipient) (jose.Encrypter, error) {
+	key, err := readPublicKey(recipient.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := recipient.KeyAlgorithm
+
+	if alg == "" {
+		switch key.(type) {
+		case *rsa.PublicKey:
+			alg = DefaultRSAKeyAlgorithm
+		case *ecdsa.PublicKey:
+			alg = DefaultECDSAKeyAlgorithm
+		default:
+			return nil, fmt.Errorf("%w: unsupported public key type %T", ErrInvalidTokenEncryptionRecipient, key)
+		}
+	}
+
+	enc := recipient.ContentEncryption
+	if enc == "" {
+		enc = DefaultContentEncryption
+	}
+
+	rcpt := jose.Recipient{
+		Algorithm: alg,
+		Key:       key,
+		KeyID:     recipient.KeyID,
+	}
+
+	// The encrypted token is a nested JWT, so its content type is JWT.
+	opts := (&jose.EncrypterOptions{}).WithType("JWT").WithContentType("JWT")
+
+	encrypter, err := jose.NewEncrypter(enc, rcpt, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenEncryptionRecipient, err)
+	}
+
+	return encrypter, nil
+}
+
+func readPublicKey(path string) (any, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, rest := pem.Decode(bytes)
+
+	switch {
+	case block == nil, block.Type != "PUBLIC KEY":
+		return nil, fmt.Errorf("%w: invalid public key", ErrInvalidTokenEncryptionRecipient)
+	case len(rest) > 0:
+		return nil, fmt.Errorf("%w: extra data in public key", ErrInvalidTokenEncryptionRecipient)
+	default:
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,219 @@
+package rfc8693
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+const testSignedToken = "header.payload.signature"
+
+type staticAccessTokenStrategy struct {
+	oauth2.AccessTokenStrategy
+}
+
+func (s staticAccessTokenStrategy) GenerateAccessToken(ctx context.Context, requester fosite.Requester) (string, string, error) {
+	return testSignedToken, "signature", nil
+}
+
+func writePublicKey(t *testing.T, key any) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// TestTokenEncryption checks that tokens issued to a registered consumer are encrypted to its key, and
+// that other tokens are issued as signed JWTs.
+func TestTokenEncryption(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fositex.TokenEncryptionConfig{
+		Recipients: []fositex.TokenEncryptionRecipient{
+			{
+				ClientID: "rsa-client",
+				KeyPath:  writePublicKey(t, &rsaKey.PublicKey),
+				KeyID:    "rsa",
+			},
+			{
+				Audience: "https://ec.example.com",
+				KeyPath:  writePublicKey(t, &ecKey.PublicKey),
+			},
+		},
+	}
+
+	strategy, err := NewTokenEncryptionStrategy(cfg)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "initialization failed")
+	}
+
+	handler := &TokenExchangeHandler{
+		accessTokenStrategy: staticAccessTokenStrategy{},
+		config: &fositex.OAuth2Config{
+			Config:                  &fosite.Config{},
+			TokenEncryptionStrategy: strategy,
+		},
+	}
+
+	type tokenInput struct {
+		clientID string
+		audience []string
+	}
+
+	runFn := func(ctx context.Context, input tokenInput) testingx.TestResult[string] {
+		requester := fosite.NewAccessRequest(nil)
+		requester.Client = &fosite.DefaultClient{ID: input.clientID}
+		requester.RequestedAudience = input.audience
+
+		responder := fosite.NewAccessResponse()
+
+		err := handler.PopulateTokenEndpointResponse(ctx, requester, responder)
+
+		return testingx.TestResult[string]{
+			Success: responder.GetAccessToken(),
+			Err:     err,
+		}
+	}
+
+	checkDecrypt := func(key any, alg jose.KeyAlgorithm, kid string) func(context.Context, *testing.T, testingx.TestResult[string]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+			if !assert.NoError(t, res.Err) {
+				return
+			}
+
+			jwe, err := jose.ParseEncrypted(res.Success)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, string(alg), jwe.Header.Algorithm)
+			assert.Equal(t, kid, jwe.Header.KeyID)
+			assert.Equal(t, "JWT", jwe.Header.ExtraHeaders[jose.HeaderContentType])
+
+			plaintext, err := jwe.Decrypt(key)
+			if assert.NoError(t, err) {
+				assert.Equal(t, testSignedToken, string(plaintext))
+			}
+		}
+	}
+
+	testCases := []testingx.TestCase[tokenInput, string]{
+		{
+			Name:    "ClientRSA",
+			Input:   tokenInput{clientID: "rsa-client"},
+			CheckFn: checkDecrypt(rsaKey, DefaultRSAKeyAlgorithm, "rsa"),
+		},
+		{
+			Name:    "AudienceECDSA",
+			Input:   tokenInput{audience: []string{"https://other.example.com", "https://ec.example.com"}},
+			CheckFn: checkDecrypt(ecKey, DefaultECDSAKeyAlgorithm, ""),
+		},
+		{
+			Name:    "FirstMatch",
+			Input:   tokenInput{clientID: "rsa-client", audience: []string{"https://ec.example.com"}},
+			CheckFn: checkDecrypt(rsaKey, DefaultRSAKeyAlgorithm, "rsa"),
+		},
+		{
+			Name:  "Unencrypted",
+			Input: tokenInput{clientID: "other-client", audience: []string{"https://other.example.com"}},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, testSignedToken, res.Success)
+				}
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestNewTokenEncryptionStrategyInvalid checks that misconfigured recipients are rejected.
+func TestNewTokenEncryptionStrategyInvalid(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := writePublicKey(t, &rsaKey.PublicKey)
+
+	runFn := func(ctx context.Context, recipient fositex.TokenEncryptionRecipient) testingx.TestResult[fositex.TokenEncryptionStrategy] {
+		cfg := fositex.TokenEncryptionConfig{
+			Recipients: []fositex.TokenEncryptionRecipient{recipient},
+		}
+
+		strategy, err := NewTokenEncryptionStrategy(cfg)
+
+		return testingx.TestResult[fositex.TokenEncryptionStrategy]{
+			Success: strategy,
+			Err:     err,
+		}
+	}
+
+	checkInvalid := func(ctx context.Context, t *testing.T, res testingx.TestResult[fositex.TokenEncryptionStrategy]) {
+		assert.ErrorIs(t, res.Err, ErrInvalidTokenEncryptionRecipient)
+	}
+
+	testCases := []testingx.TestCase[fositex.TokenEncryptionRecipient, fositex.TokenEncryptionStrategy]{
+		{
+			Name:    "NoMatch",
+			Input:   fositex.TokenEncryptionRecipient{KeyPath: keyPath},
+			CheckFn: checkInvalid,
+		},
+		{
+			Name: "AlgorithmMismatch",
+			Input: fositex.TokenEncryptionRecipient{
+				ClientID:     "client",
+				KeyPath:      keyPath,
+				KeyAlgorithm: jose.ECDH_ES_A256KW,
+			},
+			CheckFn: checkInvalid,
+		},
+		{
+			Name: "MissingKey",
+			Input: fositex.TokenEncryptionRecipient{
+				ClientID: "client",
+				KeyPath:  filepath.Join(t.TempDir(), "missing.pem"),
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[fositex.TokenEncryptionStrategy]) {
+				assert.ErrorIs(t, res.Err, os.ErrNotExist)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -4,7 +4,7 @@ package rfc8693
 import (
 	"context"
 	"errors"
-	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -12,6 +12,7 @@ import (
 	"github.com/ory/fosite/handler/oauth2"
 	"github.com/ory/fosite/token/jwt"
 	"github.com/ory/x/errorsx"
+	"gopkg.in/square/go-jose.v2"
 
 	"go.infratographer.com/identity-api/internal/fositex"
 	"go.infratographer.com/identity-api/internal/storage"
@@ -33,7 +34,7 @@ const (
 	ParamActorTokenType = "actor_token_type"
 	// ClaimClientID is the claim for the client ID.
 	ClaimClientID = "client_id"
-	// SubjectPrefix is the prefix added to the beginning of a token before the userID.
+	// SubjectPrefix is the prefix of subjects formatted with the default subject template.
 	SubjectPrefix = "urn:infratographer:user"
 
 	responseIssuedTokenType = "issued_token_type"
@@ -42,8 +43,44 @@ const (
 var (
 	// ErrJWKSURIStrategyNotDefined is returned when the issuer JWKS URI strategy is not defined.
 	ErrJWKSURIStrategyNotDefined = errors.New("no issuer JWKS URI strategy defined")
+
+	// ErrInvalidTarget is returned when a requested audience is not one exchanged tokens may be granted,
+	// per RFC 8693 section 2.2.2.
+	ErrInvalidTarget = &fosite.RFC6749Error{
+		ErrorField:       "invalid_target",
+		DescriptionField: "The requested audience is invalid, unknown, or not allowed.",
+		CodeField:        http.StatusBadRequest,
+	}
 )
 
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkRequestedTargets checks the requested audiences and scopes against the ones exchanged tokens may
+// be granted.
+func checkRequestedTargets(config fositex.TokenExchangeConfig, requester fosite.AccessRequester) error {
+	for _, aud := range requester.GetRequestedAudience() {
+		if !contains(config.AllowedAudiences, aud) {
+			return errorsx.WithStack(ErrInvalidTarget.WithHintf("Audience '%s' is not allowed.", aud))
+		}
+	}
+
+	for _, scope := range requester.GetRequestedScopes() {
+		if !contains(config.AllowedScopes, scope) {
+			return errorsx.WithStack(fosite.ErrInvalidScope.WithHintf("Scope '%s' is not allowed.", scope))
+		}
+	}
+
+	return nil
+}
+
 func findMatchingKey(ctx context.Context, config fositex.OAuth2Configurator, token *jwt.Token) (interface{}, error) {
 	var claims jwt.JWTClaims
 
@@ -56,6 +93,13 @@ func findMatchingKey(ctx context.Context, config fositex.OAuth2Configurator, tok
 		}
 	}
 
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, &jwt.ValidationError{
+			Errors: jwt.ValidationErrorMalformed,
+		}
+	}
+
 	jwksURIStrategy := config.GetIssuerJWKSURIStrategy(ctx)
 	if jwksURIStrategy == nil {
 		return nil, &jwt.ValidationError{
@@ -64,33 +108,47 @@ func findMatchingKey(ctx context.Context, config fositex.OAuth2Configurator, tok
 		}
 	}
 
-	jwksURI, err := jwksURIStrategy.GetIssuerJWKSURI(ctx, issuer)
-	if err != nil {
-		return nil, &jwt.ValidationError{
-			Errors: jwt.ValidationErrorIssuer,
-			Inner:  err,
+	// Issuers with an inline JWKS are verified against it without fetching anything.
+	if jwksStrategy, ok := jwksURIStrategy.(fositex.IssuerJWKSStrategy); ok {
+		jwks, err := jwksStrategy.GetIssuerJWKS(ctx, issuer)
+		if err != nil {
+			return nil, &jwt.ValidationError{
+				Errors: jwt.ValidationErrorIssuer,
+				Inner:  err,
+			}
+		}
+
+		if jwks != nil {
+			if key, ok := findSigningKey(jwks, kid); ok {
+				return key, nil
+			}
+
+			return nil, &jwt.ValidationError{
+				Errors: jwt.ValidationErrorSignatureInvalid,
+			}
 		}
 	}
 
-	jwks, err := config.GetJWKSFetcherStrategy(ctx).Resolve(ctx, jwksURI, false)
+	jwksURI, err := jwksURIStrategy.GetIssuerJWKSURI(ctx, issuer)
 	if err != nil {
 		return nil, &jwt.ValidationError{
-			Errors: jwt.ValidationErrorUnverifiable,
+			Errors: jwt.ValidationErrorIssuer,
 			Inner:  err,
 		}
 	}
 
-	kid, ok := token.Header["kid"].(string)
-	if !ok {
-		return nil, &jwt.ValidationError{
-			Errors: jwt.ValidationErrorMalformed,
+	// If the key ID is unknown, the issuer may have rotated its keys since the JWKS was cached, so
+	// the JWKS is refetched once before the token is rejected.
+	for _, ignoreCache := range []bool{false, true} {
+		jwks, err := resolveJWKS(ctx, config, issuer, jwksURI, ignoreCache)
+		if err != nil {
+			return nil, &jwt.ValidationError{
+				Errors: jwt.ValidationErrorUnverifiable,
+				Inner:  err,
+			}
 		}
-	}
-
-	keys := jwks.Key(kid)
 
-	for _, key := range keys {
-		if key.Use == "sig" {
+		if key, ok := findSigningKey(jwks, kid); ok {
 			return key, nil
 		}
 	}
@@ -102,6 +160,41 @@ func findMatchingKey(ctx context.Context, config fositex.OAuth2Configurator, tok
 	return nil, err
 }
 
+// resolveJWKS fetches the issuer's JWKS from the given URI. Fetched JWKS are saved as the issuer's last
+// known good JWKS, which is used in its place if it cannot be fetched.
+func resolveJWKS(ctx context.Context, config fositex.OAuth2Configurator, iss, jwksURI string, ignoreCache bool) (*jose.JSONWebKeySet, error) {
+	jwks, err := config.GetJWKSFetcherStrategy(ctx).Resolve(ctx, jwksURI, ignoreCache)
+
+	snapshotStrategy := config.GetIssuerJWKSSnapshotStrategy(ctx)
+	if snapshotStrategy == nil {
+		return jwks, err
+	}
+
+	if err != nil {
+		snapshot, snapshotErr := snapshotStrategy.GetIssuerJWKSSnapshot(ctx, iss)
+		if snapshotErr != nil {
+			return nil, err
+		}
+
+		return snapshot, nil
+	}
+
+	// Snapshots are only a fallback, so failing to save one does not fail the exchange.
+	_ = snapshotStrategy.SaveIssuerJWKSSnapshot(ctx, iss, jwks)
+
+	return jwks, nil
+}
+
+func findSigningKey(jwks *jose.JSONWebKeySet, kid string) (jose.JSONWebKey, bool) {
+	for _, key := range jwks.Key(kid) {
+		if key.Use == "sig" {
+			return key, true
+		}
+	}
+
+	return jose.JSONWebKey{}, false
+}
+
 // TokenExchangeHandler contains the logic for the token exchange grant type.
 // it implements the fosite.TokenEndpointHandler interface.
 type TokenExchangeHandler struct {
@@ -113,6 +206,9 @@ type TokenExchangeHandler struct {
 // implement the fosite.TokenEndpointHandler interface
 var _ fosite.TokenEndpointHandler = new(TokenExchangeHandler)
 
+// implement the fositex.ServerMetadataContributor interface
+var _ fositex.ServerMetadataContributor = new(TokenExchangeHandler)
+
 // NewTokenExchangeHandler works as a fositex.Factory to register this handler.
 var _ fositex.Factory = NewTokenExchangeHandler
 
@@ -125,6 +221,13 @@ func NewTokenExchangeHandler(config fositex.OAuth2Configurator, storage any, str
 	}
 }
 
+// ContributeServerMetadata adds the token exchange grant type and supported subject token types to the
+// server metadata.
+func (s *TokenExchangeHandler) ContributeServerMetadata(md *fositex.ServerMetadata) {
+	md.GrantTypesSupported = append(md.GrantTypesSupported, GrantTypeTokenExchange)
+	md.SubjectTokenTypesSupported = append(md.SubjectTokenTypesSupported, TokenTypeJWT)
+}
+
 func (s *TokenExchangeHandler) validateJWT(ctx context.Context, token string, strategy fosite.JWKSFetcherStrategy) (*jwt.Token, error) {
 	// Side effectful key finding isn't great but neither is parsing the JWT twice
 	keyfunc := func(token *jwt.Token) (interface{}, error) {
@@ -164,10 +267,14 @@ func (s *TokenExchangeHandler) getSubjectClaims(ctx context.Context, token strin
 	return &claims, nil
 }
 
-func (s *TokenExchangeHandler) getMappedSubjectClaims(ctx context.Context, claims *jwt.JWTClaims) (jwt.JWTClaimsContainer, error) {
+func (s *TokenExchangeHandler) getMappedSubjectClaims(
+	ctx context.Context,
+	claims *jwt.JWTClaims,
+	mappingCtx types.ClaimMappingContext,
+) (jwt.JWTClaimsContainer, error) {
 	mappingStrategy := s.config.GetClaimMappingStrategy(ctx)
 
-	mappedClaims, err := mappingStrategy.MapClaims(ctx, claims)
+	mappedClaims, err := mappingStrategy.MapClaims(ctx, claims, mappingCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -202,14 +309,13 @@ func (s *TokenExchangeHandler) HandleTokenEndpointRequest(ctx context.Context, r
 		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("Unsupported subject token type '%s'.", subjectTokenType))
 	}
 
-	claims, err := s.getSubjectClaims(ctx, subjectToken)
-	if err != nil {
+	if err := checkRequestedTargets(s.config.GetTokenExchangeConfig(ctx), requester); err != nil {
 		return err
 	}
 
-	mappedClaims, err := s.getMappedSubjectClaims(ctx, claims)
+	claims, err := s.getSubjectClaims(ctx, subjectToken)
 	if err != nil {
-		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("error mapping claims: %s", err))
+		return err
 	}
 
 	issuer := claims.Issuer
@@ -228,7 +334,8 @@ func (s *TokenExchangeHandler) HandleTokenEndpointRequest(ctx context.Context, r
 
 	userInfo, err := s.populateUserInfo(dbCtx, issuer, claims.Subject, subjectToken)
 	if err != nil {
-		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("unable to populate user info: %s", err))
+		rbErr := txManager.RollbackContext(dbCtx)
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("unable to populate user info: %s / rollback error: %s", err, rbErr))
 	}
 
 	userWithID, err := userInfoSvc.StoreUserInfo(dbCtx, *userInfo)
@@ -238,42 +345,57 @@ func (s *TokenExchangeHandler) HandleTokenEndpointRequest(ctx context.Context, r
 		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("unable to store user info: %s / rollback error: %s", err, rbErr))
 	}
 
+	var clientID *string
+
+	maybeClientID := requester.GetClient().GetID()
+	if len(maybeClientID) > 0 {
+		clientID = &maybeClientID
+	}
+
+	// Pairwise subject identifiers are stored as they are formatted, so this must happen in the transaction.
+	subject, err := s.config.GetSubjectStrategy(ctx).FormatSubject(
+		dbCtx,
+		issuer,
+		userWithID,
+		maybeClientID,
+		requester.GetRequestedAudience(),
+	)
+	if err != nil {
+		rbErr := txManager.RollbackContext(dbCtx)
+		return errorsx.WithStack(fosite.ErrServerError.WithHintf("unable to format subject: %s / rollback error: %s", err, rbErr))
+	}
+
 	err = txManager.CommitContext(dbCtx)
 
 	if err != nil {
 		return errorsx.WithStack(fosite.ErrServerError.WithHintf("could not commit user info: %s", err))
 	}
 
-	var newClaims jwt.JWTClaims
-	newClaims.Subject = s.formatSubject(userWithID)
-	newClaims.Issuer = s.config.GetAccessTokenIssuer(ctx)
+	mappingCtx := types.ClaimMappingContext{
+		ClientID:        maybeClientID,
+		RequestedScopes: requester.GetRequestedScopes(),
+		Audience:        requester.GetRequestedAudience(),
+		UserInfo:        userWithID,
+	}
 
-	for k, v := range mappedClaims.ToMapClaims() {
-		newClaims.Add(k, v)
+	// Mapping runs after the transaction is committed so the issuer (and its compiled mappings) can be
+	// served from the issuer cache.
+	mappedClaims, err := s.getMappedSubjectClaims(ctx, claims, mappingCtx)
+	if err != nil {
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("error mapping claims: %s", err))
 	}
 
+	newClaims := BuildTokenClaims(s.config.GetAccessTokenIssuer(ctx), subject, mappedClaims, clientID)
+
 	expiry := time.Now().Add(s.config.GetAccessTokenLifespan(ctx))
 	expiryMap := map[fosite.TokenType]time.Time{
 		fosite.AccessToken: expiry,
 	}
 
-	var clientID *string
-
-	maybeClientID := requester.GetClient().GetID()
-	if len(maybeClientID) > 0 {
-		clientID = &maybeClientID
-	}
-
-	newClaims.Add(ClaimClientID, clientID)
-
-	kid := s.config.GetSigningKey(ctx).KeyID
-
-	headers := jwt.Headers{}
-	headers.Add("kid", kid)
-
+	// The kid header is set when the token is generated, by which time the signing key may have rotated.
 	session := oauth2.JWTSession{
-		JWTHeader: &headers,
-		JWTClaims: &newClaims,
+		JWTHeader: &jwt.Headers{},
+		JWTClaims: newClaims,
 		ExpiresAt: expiryMap,
 		Subject:   claims.Subject,
 	}
@@ -283,27 +405,79 @@ func (s *TokenExchangeHandler) HandleTokenEndpointRequest(ctx context.Context, r
 		return errorsx.WithStack(fosite.ErrServerError.WithHintf("failed to build userinfo audience: %s", err))
 	}
 
+	for _, aud := range requester.GetRequestedAudience() {
+		requester.GrantAudience(aud)
+	}
+
+	for _, scope := range requester.GetRequestedScopes() {
+		requester.GrantScope(scope)
+	}
+
 	requester.GrantAudience(userInfoAud)
 	requester.SetSession(&session)
 
 	return nil
 }
 
-// PopulateTokenEndpointResponse populates the response with a token.
+// PopulateTokenEndpointResponse populates the response with a token. Tokens issued to a consumer with a
+// registered encryption key are issued as nested JWTs encrypted to that key.
 func (s *TokenExchangeHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	// The signing key is resolved once, so the token's kid names the key that signs it.
+	if key := s.config.GetSigningKey(ctx); key != nil {
+		if session, ok := requester.GetSession().(*oauth2.JWTSession); ok && session.JWTHeader != nil {
+			session.JWTHeader.Add("kid", key.KeyID)
+		}
+
+		ctx = fositex.ContextWithSigningKey(ctx, key)
+	}
+
 	token, _, err := s.accessTokenStrategy.GenerateAccessToken(ctx, requester)
 	if err != nil {
 		return err
 	}
 
+	token, err = s.encryptToken(ctx, requester, token)
+	if err != nil {
+		return err
+	}
+
 	responder.SetAccessToken(token)
 	responder.SetExtra(responseIssuedTokenType, TokenTypeJWT)
 	responder.SetTokenType(fosite.BearerAccessToken)
+	responder.SetScopes(requester.GetGrantedScopes())
 	responder.SetExpiresIn(s.config.GetAccessTokenLifespan(ctx))
 
 	return nil
 }
 
+func (s *TokenExchangeHandler) encryptToken(ctx context.Context, requester fosite.AccessRequester, token string) (string, error) {
+	encryptionStrategy := s.config.GetTokenEncryptionStrategy(ctx)
+	if encryptionStrategy == nil {
+		return token, nil
+	}
+
+	encrypter, err := encryptionStrategy.GetTokenEncrypter(ctx, requester.GetClient().GetID(), requester.GetRequestedAudience())
+	if err != nil {
+		return "", errorsx.WithStack(fosite.ErrServerError.WithHintf("failed to get token encrypter: %s", err))
+	}
+
+	if encrypter == nil {
+		return token, nil
+	}
+
+	jwe, err := encrypter.Encrypt([]byte(token))
+	if err != nil {
+		return "", errorsx.WithStack(fosite.ErrServerError.WithHintf("failed to encrypt token: %s", err))
+	}
+
+	out, err := jwe.CompactSerialize()
+	if err != nil {
+		return "", errorsx.WithStack(fosite.ErrServerError.WithHintf("failed to encrypt token: %s", err))
+	}
+
+	return out, nil
+}
+
 // CanSkipClientAuth always returns true, as client auth is not required for token exchange.
 func (s *TokenExchangeHandler) CanSkipClientAuth(ctx context.Context, requester fosite.AccessRequester) bool {
 	return true
@@ -323,7 +497,6 @@ func (s *TokenExchangeHandler) populateUserInfo(ctx context.Context, issuer stri
 		// issuers userinfo endpoint, but if some other error
 		// came back bail.
 		if !errors.Is(err, types.ErrUserInfoNotFound) {
-			fmt.Println("couldn't find issuer in lookup")
 			return nil, err
 		}
 	} else {
@@ -332,13 +505,24 @@ func (s *TokenExchangeHandler) populateUserInfo(ctx context.Context, issuer stri
 
 	userInfo, err = userInfoSvc.FetchUserInfoFromIssuer(ctx, issuer, token)
 	if err != nil {
-		fmt.Println("failed to fetch userinfo")
 		return nil, err
 	}
 
 	return userInfo, nil
 }
 
-func (s *TokenExchangeHandler) formatSubject(info *types.UserInfo) string {
-	return fmt.Sprintf("%s/%s", SubjectPrefix, info.ID)
+// BuildTokenClaims builds the claims for an identity-api access token, combining the issuer and subject with
+// the claims produced by claim mapping and the requesting client ID, if any.
+func BuildTokenClaims(issuer, subject string, mappedClaims jwt.JWTClaimsContainer, clientID *string) *jwt.JWTClaims {
+	var newClaims jwt.JWTClaims
+	newClaims.Subject = subject
+	newClaims.Issuer = issuer
+
+	for k, v := range mappedClaims.ToMapClaims() {
+		newClaims.Add(k, v)
+	}
+
+	newClaims.Add(ClaimClientID, clientID)
+
+	return &newClaims
 }
@@ -0,0 +1,92 @@
+package rfc8693
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+// rotatingAccessTokenStrategy rotates the config's signing key before generating a token, as if the key
+// rotated while the token was being issued.
+type rotatingAccessTokenStrategy struct {
+	oauth2.AccessTokenStrategy
+
+	config *fositex.OAuth2Config
+	next   *jose.JSONWebKey
+}
+
+func (s rotatingAccessTokenStrategy) GenerateAccessToken(ctx context.Context, requester fosite.Requester) (string, string, error) {
+	s.config.SigningKey = s.next
+
+	return s.AccessTokenStrategy.GenerateAccessToken(ctx, requester)
+}
+
+func newTestSigningKey(t *testing.T, kid string) *jose.JSONWebKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &jose.JSONWebKey{
+		Key:       key,
+		KeyID:     kid,
+		Algorithm: string(jose.ES256),
+		Use:       "sig",
+	}
+}
+
+// TestPopulateTokenEndpointResponseSigningKey checks that a token's kid names the key that signed it,
+// even if the signing key rotates while the token is being issued.
+func TestPopulateTokenEndpointResponseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	first := newTestSigningKey(t, "first")
+	second := newTestSigningKey(t, "second")
+
+	config := &fositex.OAuth2Config{
+		Config:     &fosite.Config{},
+		SigningKey: first,
+	}
+
+	handler := &TokenExchangeHandler{
+		accessTokenStrategy: rotatingAccessTokenStrategy{
+			AccessTokenStrategy: compose.NewOAuth2JWTStrategy(config.GetPrivateKey, nil, config),
+			config:              config,
+			next:                second,
+		},
+		config: config,
+	}
+
+	requester := fosite.NewAccessRequest(&oauth2.JWTSession{
+		JWTHeader: &jwt.Headers{},
+		JWTClaims: &jwt.JWTClaims{Subject: "user"},
+		ExpiresAt: map[fosite.TokenType]time.Time{
+			fosite.AccessToken: time.Now().Add(time.Hour),
+		},
+	})
+	requester.Client = &fosite.DefaultClient{}
+
+	responder := fosite.NewAccessResponse()
+
+	err := handler.PopulateTokenEndpointResponse(context.Background(), requester, responder)
+	require.NoError(t, err)
+
+	token, err := jose.ParseSigned(responder.GetAccessToken())
+	require.NoError(t, err)
+
+	assert.Equal(t, first.KeyID, token.Signatures[0].Header.KeyID)
+
+	_, err = token.Verify(first.Public().Key)
+	assert.NoError(t, err)
+}
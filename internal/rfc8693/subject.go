@@ -0,0 +1,252 @@
+package rfc8693
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+const (
+	// SubjectIDPlaceholder is the placeholder in a subject template that is replaced by the subject's identifier.
+	SubjectIDPlaceholder = "{id}"
+	// DefaultSubjectTemplate is the subject template used when none is configured.
+	DefaultSubjectTemplate = SubjectPrefix + "/" + SubjectIDPlaceholder
+)
+
+var (
+	// ErrInvalidSubjectTemplate is returned when a subject template does not contain exactly one
+	// identifier placeholder.
+	ErrInvalidSubjectTemplate = errors.New("subject template must contain exactly one " + SubjectIDPlaceholder + " placeholder")
+
+	// ErrMalformedSubject is returned when a subject does not match any configured subject template.
+	ErrMalformedSubject = errors.New("malformed subject")
+
+	pairwiseSubjectNamespace = uuid.MustParse("cce5cac5-cf24-44c4-a373-cd4a2d1e4a3e")
+)
+
+// SubjectTemplate represents a format for token subjects, made up of a prefix and suffix surrounding
+// the subject's identifier.
+type SubjectTemplate struct {
+	prefix string
+	suffix string
+}
+
+// NewSubjectTemplate creates a SubjectTemplate from the given template, which must contain exactly one
+// SubjectIDPlaceholder.
+func NewSubjectTemplate(tmpl string) (SubjectTemplate, error) {
+	if strings.Count(tmpl, SubjectIDPlaceholder) != 1 {
+		return SubjectTemplate{}, fmt.Errorf("%w: '%s'", ErrInvalidSubjectTemplate, tmpl)
+	}
+
+	prefix, suffix, _ := strings.Cut(tmpl, SubjectIDPlaceholder)
+
+	out := SubjectTemplate{
+		prefix: prefix,
+		suffix: suffix,
+	}
+
+	return out, nil
+}
+
+// Format formats a subject containing the given identifier.
+func (t SubjectTemplate) Format(id string) string {
+	return t.prefix + id + t.suffix
+}
+
+// Parse returns the identifier contained in the given subject. Identifiers are either user info IDs
+// or pairwise subject identifiers, both of which are UUIDs.
+func (t SubjectTemplate) Parse(sub string) (string, error) {
+	if len(sub) < len(t.prefix)+len(t.suffix) ||
+		!strings.HasPrefix(sub, t.prefix) ||
+		!strings.HasSuffix(sub, t.suffix) {
+		return "", ErrMalformedSubject
+	}
+
+	id := sub[len(t.prefix) : len(sub)-len(t.suffix)]
+
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrMalformedSubject
+	}
+
+	return id, nil
+}
+
+// PairwiseSubjectID derives the pairwise subject identifier for the given user info ID and sector.
+func PairwiseSubjectID(userInfoID uuid.UUID, sector, salt string) uuid.UUID {
+	name := strings.Join([]string{sector, userInfoID.String(), salt}, "\x00")
+
+	return uuid.NewSHA1(pairwiseSubjectNamespace, []byte(name))
+}
+
+// PairwiseSector returns the sector a subject is identified to: the client performing the exchange if
+// there is one, or else the requested audience.
+func PairwiseSector(clientID string, audience []string) string {
+	if clientID != "" {
+		return clientID
+	}
+
+	sorted := make([]string, len(audience))
+	copy(sorted, audience)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, " ")
+}
+
+// SubjectStrategy formats and parses token subjects using a default subject template, which may be
+// overridden per tenant. Subjects exchanged from the issuers of pairwise tenants, or for pairwise
+// clients, contain a pairwise subject identifier in place of the user info ID.
+type SubjectStrategy struct {
+	issuerSvc       types.IssuerService
+	pairwiseSvc     types.PairwiseSubjectService
+	defaultTemplate SubjectTemplate
+	tenantIDs       []string
+	tenantTemplates map[string]SubjectTemplate
+	pairwiseTenants map[string]bool
+	pairwiseClients map[string]bool
+	pairwiseSalt    string
+}
+
+// implement the fositex.SubjectStrategy interface
+var _ fositex.SubjectStrategy = SubjectStrategy{}
+
+// NewSubjectStrategy creates a SubjectStrategy from the given config. The issuer service is used to
+// find the tenant of a subject token's issuer when tenant templates or pairwise tenants are configured,
+// and the pairwise subject service is used to store and resolve pairwise subject identifiers.
+func NewSubjectStrategy(
+	config fositex.SubjectConfig,
+	issuerSvc types.IssuerService,
+	pairwiseSvc types.PairwiseSubjectService,
+) (SubjectStrategy, error) {
+	tmpl := config.Template
+	if tmpl == "" {
+		tmpl = DefaultSubjectTemplate
+	}
+
+	defaultTemplate, err := NewSubjectTemplate(tmpl)
+	if err != nil {
+		return SubjectStrategy{}, err
+	}
+
+	tenantIDs := make([]string, 0, len(config.TenantTemplates))
+	tenantTemplates := make(map[string]SubjectTemplate, len(config.TenantTemplates))
+
+	for tenantID, tmpl := range config.TenantTemplates {
+		tenantTemplate, err := NewSubjectTemplate(tmpl)
+		if err != nil {
+			return SubjectStrategy{}, fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+
+		tenantIDs = append(tenantIDs, tenantID)
+		tenantTemplates[tenantID] = tenantTemplate
+	}
+
+	sort.Strings(tenantIDs)
+
+	out := SubjectStrategy{
+		issuerSvc:       issuerSvc,
+		pairwiseSvc:     pairwiseSvc,
+		defaultTemplate: defaultTemplate,
+		tenantIDs:       tenantIDs,
+		tenantTemplates: tenantTemplates,
+		pairwiseTenants: stringSet(config.Pairwise.Tenants),
+		pairwiseClients: stringSet(config.Pairwise.Clients),
+		pairwiseSalt:    config.Pairwise.Salt,
+	}
+
+	return out, nil
+}
+
+// FormatSubject formats the subject for the given user info using the template for the tenant of the
+// issuer iss, falling back to the default template. If the tenant or client is configured for pairwise
+// subjects, the subject contains a pairwise subject identifier for the exchange's sector, which is
+// stored so it can be resolved by ParseSubject. Storing requires a transaction in the context.
+func (s SubjectStrategy) FormatSubject(
+	ctx context.Context,
+	iss string,
+	userInfo *types.UserInfo,
+	clientID string,
+	audience []string,
+) (string, error) {
+	tmpl := s.defaultTemplate
+	pairwise := s.pairwiseClients[clientID]
+
+	if len(s.tenantTemplates) > 0 || len(s.pairwiseTenants) > 0 {
+		issuer, err := s.issuerSvc.GetIssuerByURI(ctx, iss)
+		if err != nil {
+			return "", err
+		}
+
+		if tenantTemplate, ok := s.tenantTemplates[issuer.TenantID]; ok {
+			tmpl = tenantTemplate
+		}
+
+		pairwise = pairwise || s.pairwiseTenants[issuer.TenantID]
+	}
+
+	sector := PairwiseSector(clientID, audience)
+
+	// Without a sector there is no relying party to identify the subject to.
+	if !pairwise || sector == "" {
+		return tmpl.Format(userInfo.ID.String()), nil
+	}
+
+	subject := types.PairwiseSubject{
+		ID:         PairwiseSubjectID(userInfo.ID, sector, s.pairwiseSalt).String(),
+		UserInfoID: userInfo.ID.String(),
+		Sector:     sector,
+	}
+
+	if _, err := s.pairwiseSvc.StorePairwiseSubject(ctx, subject); err != nil {
+		return "", err
+	}
+
+	return tmpl.Format(subject.ID), nil
+}
+
+// ParseSubject returns the ID of the user info the given subject refers to, trying each tenant template
+// in order of tenant ID before the default template. Pairwise subject identifiers are resolved to the
+// user info ID they were derived from.
+func (s SubjectStrategy) ParseSubject(ctx context.Context, sub string) (string, error) {
+	id, err := s.parseID(sub)
+	if err != nil {
+		return "", err
+	}
+
+	subject, err := s.pairwiseSvc.LookupPairwiseSubject(ctx, id)
+
+	switch {
+	case err == nil:
+		return subject.UserInfoID, nil
+	case errors.Is(err, types.ErrorPairwiseSubjectNotFound):
+		return id, nil
+	default:
+		return "", err
+	}
+}
+
+func (s SubjectStrategy) parseID(sub string) (string, error) {
+	for _, tenantID := range s.tenantIDs {
+		if id, err := s.tenantTemplates[tenantID].Parse(sub); err == nil {
+			return id, nil
+		}
+	}
+
+	return s.defaultTemplate.Parse(sub)
+}
+
+func stringSet(values []string) map[string]bool {
+	out := make(map[string]bool, len(values))
+
+	for _, v := range values {
+		out[v] = true
+	}
+
+	return out
+}
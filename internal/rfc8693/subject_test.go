@@ -0,0 +1,277 @@
+package rfc8693
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+type staticIssuerService struct {
+	types.IssuerService
+	issuers map[string]*types.Issuer
+}
+
+func (s staticIssuerService) GetIssuerByURI(ctx context.Context, uri string) (*types.Issuer, error) {
+	iss, ok := s.issuers[uri]
+	if !ok {
+		return nil, types.ErrorIssuerNotFound
+	}
+
+	return iss, nil
+}
+
+type memoryPairwiseSubjectService struct {
+	mu       sync.Mutex
+	subjects map[string]types.PairwiseSubject
+}
+
+func (s *memoryPairwiseSubjectService) StorePairwiseSubject(ctx context.Context, subject types.PairwiseSubject) (*types.PairwiseSubject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subjects[subject.ID] = subject
+
+	return &subject, nil
+}
+
+func (s *memoryPairwiseSubjectService) LookupPairwiseSubject(ctx context.Context, id string) (*types.PairwiseSubject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subject, ok := s.subjects[id]
+	if !ok {
+		return nil, types.ErrorPairwiseSubjectNotFound
+	}
+
+	return &subject, nil
+}
+
+// TestSubjectStrategy checks that subjects are formatted and parsed using the configured templates.
+func TestSubjectStrategy(t *testing.T) {
+	t.Parallel()
+
+	tenantID := "b8bfd705-b768-47a4-85a0-fe006f5bcfca"
+	pairwiseTenantID := "3f0b8c8e-5d6a-4a8e-9d43-1f2f0c9d7a11"
+	userID := uuid.MustParse("0b5c5fbb-1b3a-4b63-9b5d-4cf2c94ebf42")
+
+	issuerSvc := staticIssuerService{
+		issuers: map[string]*types.Issuer{
+			"https://default.example.com/": {
+				TenantID: "00000000-0000-0000-0000-000000000000",
+			},
+			"https://tenant.example.com/": {
+				TenantID: tenantID,
+			},
+			"https://pairwise.example.com/": {
+				TenantID: pairwiseTenantID,
+			},
+		},
+	}
+
+	pairwiseSvc := &memoryPairwiseSubjectService{
+		subjects: map[string]types.PairwiseSubject{},
+	}
+
+	cfg := fositex.SubjectConfig{
+		TenantTemplates: map[string]string{
+			tenantID: "urn:example:tenant:" + SubjectIDPlaceholder + ":user",
+		},
+		Pairwise: fositex.PairwiseSubjectConfig{
+			Tenants: []string{pairwiseTenantID},
+			Clients: []string{"private-client"},
+			Salt:    "salt",
+		},
+	}
+
+	strategy, err := NewSubjectStrategy(cfg, issuerSvc, pairwiseSvc)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "initialization failed")
+	}
+
+	t.Run("FormatSubject", func(t *testing.T) {
+		t.Parallel()
+
+		type formatInput struct {
+			iss      string
+			clientID string
+			audience []string
+		}
+
+		runFn := func(ctx context.Context, input formatInput) testingx.TestResult[string] {
+			sub, err := strategy.FormatSubject(ctx, input.iss, &types.UserInfo{ID: userID}, input.clientID, input.audience)
+
+			return testingx.TestResult[string]{
+				Success: sub,
+				Err:     err,
+			}
+		}
+
+		testCases := []testingx.TestCase[formatInput, string]{
+			{
+				Name:  "Default",
+				Input: formatInput{iss: "https://default.example.com/", clientID: "public-client"},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, SubjectPrefix+"/"+userID.String(), res.Success)
+					}
+				},
+			},
+			{
+				Name:  "TenantOverride",
+				Input: formatInput{iss: "https://tenant.example.com/"},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, "urn:example:tenant:"+userID.String()+":user", res.Success)
+					}
+				},
+			},
+			{
+				Name:  "UnknownIssuer",
+				Input: formatInput{iss: "https://evil.biz/"},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					assert.ErrorIs(t, res.Err, types.ErrorIssuerNotFound)
+				},
+			},
+			{
+				Name:  "PairwiseClient",
+				Input: formatInput{iss: "https://default.example.com/", clientID: "private-client"},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					pairwiseID := PairwiseSubjectID(userID, "private-client", "salt").String()
+
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, SubjectPrefix+"/"+pairwiseID, res.Success)
+
+						id, err := strategy.ParseSubject(ctx, res.Success)
+						if assert.NoError(t, err) {
+							assert.Equal(t, userID.String(), id)
+						}
+					}
+				},
+			},
+			{
+				Name:  "PairwiseTenantAudience",
+				Input: formatInput{iss: "https://pairwise.example.com/", audience: []string{"b", "a"}},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					pairwiseID := PairwiseSubjectID(userID, "a b", "salt").String()
+
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, SubjectPrefix+"/"+pairwiseID, res.Success)
+					}
+				},
+			},
+			{
+				Name:  "PairwiseTenantNoSector",
+				Input: formatInput{iss: "https://pairwise.example.com/"},
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, SubjectPrefix+"/"+userID.String(), res.Success)
+					}
+				},
+			},
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("ParseSubject", func(t *testing.T) {
+		t.Parallel()
+
+		runFn := func(ctx context.Context, sub string) testingx.TestResult[string] {
+			id, err := strategy.ParseSubject(ctx, sub)
+
+			return testingx.TestResult[string]{
+				Success: id,
+				Err:     err,
+			}
+		}
+
+		expectID := func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+			if assert.NoError(t, res.Err) {
+				assert.Equal(t, userID.String(), res.Success)
+			}
+		}
+
+		expectMalformed := func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+			assert.ErrorIs(t, res.Err, ErrMalformedSubject)
+		}
+
+		testCases := []testingx.TestCase[string, string]{
+			{
+				Name:    "Default",
+				Input:   SubjectPrefix + "/" + userID.String(),
+				CheckFn: expectID,
+			},
+			{
+				Name:    "TenantOverride",
+				Input:   "urn:example:tenant:" + userID.String() + ":user",
+				CheckFn: expectID,
+			},
+			{
+				Name:    "MissingPrefix",
+				Input:   userID.String(),
+				CheckFn: expectMalformed,
+			},
+			{
+				Name:    "PrefixOnly",
+				Input:   SubjectPrefix + "/",
+				CheckFn: expectMalformed,
+			},
+			{
+				Name:    "InvalidID",
+				Input:   SubjectPrefix + "/not-a-uuid",
+				CheckFn: expectMalformed,
+			},
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+}
+
+// TestNewSubjectTemplate checks that subject templates must contain exactly one placeholder.
+func TestNewSubjectTemplate(t *testing.T) {
+	t.Parallel()
+
+	runFn := func(ctx context.Context, tmpl string) testingx.TestResult[SubjectTemplate] {
+		out, err := NewSubjectTemplate(tmpl)
+
+		return testingx.TestResult[SubjectTemplate]{
+			Success: out,
+			Err:     err,
+		}
+	}
+
+	expectInvalid := func(ctx context.Context, t *testing.T, res testingx.TestResult[SubjectTemplate]) {
+		assert.ErrorIs(t, res.Err, ErrInvalidSubjectTemplate)
+	}
+
+	testCases := []testingx.TestCase[string, SubjectTemplate]{
+		{
+			Name:  "Success",
+			Input: DefaultSubjectTemplate,
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[SubjectTemplate]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, SubjectPrefix+"/abc", res.Success.Format("abc"))
+				}
+			},
+		},
+		{
+			Name:    "MissingPlaceholder",
+			Input:   SubjectPrefix,
+			CheckFn: expectInvalid,
+		},
+		{
+			Name:    "RepeatedPlaceholder",
+			Input:   SubjectIDPlaceholder + "/" + SubjectIDPlaceholder,
+			CheckFn: expectInvalid,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -1,7 +1,13 @@
 package routes
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -10,15 +16,52 @@ import (
 	"go.infratographer.com/identity-api/internal/fositex"
 )
 
+// jwksResponse represents the precomputed response for a signing JWKS.
+type jwksResponse struct {
+	signingJWKS *jose.JSONWebKeySet
+	body        []byte
+	etag        string
+}
+
 type jwksHandler struct {
-	logger *zap.SugaredLogger
-	config fositex.OAuth2Configurator
+	logger   *zap.SugaredLogger
+	config   fositex.OAuth2Configurator
+	response atomic.Pointer[jwksResponse]
 }
 
 // Handle processes the request for the JWKS handler.
 func (h *jwksHandler) Handle(ctx *gin.Context) {
+	resp, err := h.getResponse(ctx)
+	if err != nil {
+		h.logger.Errorw("error building JWKS response", "error", err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	maxAge := h.config.GetSigningJWKSMaxAge(ctx)
+
+	ctx.Header("ETag", resp.etag)
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if etagMatches(ctx.GetHeader("If-None-Match"), resp.etag) {
+		ctx.Status(http.StatusNotModified)
+
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", resp.body)
+}
+
+// getResponse returns the response for the current signing JWKS. Responses are only rebuilt when the
+// signing JWKS is replaced, e.g. when signing keys are reloaded.
+func (h *jwksHandler) getResponse(ctx *gin.Context) (*jwksResponse, error) {
 	jwks := h.config.GetSigningJWKS(ctx)
 
+	if resp := h.response.Load(); resp != nil && resp.signingJWKS == jwks {
+		return resp, nil
+	}
+
 	out := jose.JSONWebKeySet{
 		Keys: []jose.JSONWebKey{},
 	}
@@ -29,5 +72,38 @@ func (h *jwksHandler) Handle(ctx *gin.Context) {
 		}
 	}
 
-	ctx.JSON(http.StatusOK, out)
+	body, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+
+	resp := &jwksResponse{
+		signingJWKS: jwks,
+		body:        body,
+		etag:        `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`,
+	}
+
+	h.response.Store(resp)
+
+	return resp, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches the given entity tag. Entity tags are
+// compared weakly, as required for If-None-Match.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
 }
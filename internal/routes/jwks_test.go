@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+// TestJWKS checks that the JWKS is served with caching headers and that conditional requests for an
+// unchanged JWKS are answered with 304 Not Modified.
+func TestJWKS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &fositex.OAuth2Config{
+		SigningJWKS: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{
+					Key:       key,
+					KeyID:     "a",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				},
+			},
+		},
+		SigningJWKSMaxAge: 10 * time.Minute,
+	}
+
+	engine := gin.New()
+	NewRouter(zap.NewNop().Sugar(), config, nil).Routes(engine.Group("/"))
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, jwksPath, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		w := httptest.NewRecorder()
+
+		engine.ServeHTTP(w, req)
+
+		return w
+	}
+
+	first := get("")
+
+	if !assert.Equal(t, http.StatusOK, first.Code) {
+		return
+	}
+
+	etag := first.Header().Get("ETag")
+
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=600", first.Header().Get("Cache-Control"))
+
+	var jwks map[string][]map[string]any
+
+	if assert.NoError(t, json.Unmarshal(first.Body.Bytes(), &jwks)) && assert.Len(t, jwks["keys"], 1) {
+		assert.Equal(t, "a", jwks["keys"][0]["kid"])
+		assert.NotContains(t, jwks["keys"][0], "d")
+	}
+
+	runFn := func(ctx context.Context, ifNoneMatch string) testingx.TestResult[*httptest.ResponseRecorder] {
+		return testingx.TestResult[*httptest.ResponseRecorder]{
+			Success: get(ifNoneMatch),
+		}
+	}
+
+	checkNotModified := func(ctx context.Context, t *testing.T, res testingx.TestResult[*httptest.ResponseRecorder]) {
+		assert.Equal(t, http.StatusNotModified, res.Success.Code)
+		assert.Empty(t, res.Success.Body.Bytes())
+		assert.Equal(t, etag, res.Success.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=600", res.Success.Header().Get("Cache-Control"))
+	}
+
+	checkModified := func(ctx context.Context, t *testing.T, res testingx.TestResult[*httptest.ResponseRecorder]) {
+		assert.Equal(t, http.StatusOK, res.Success.Code)
+		assert.Equal(t, first.Body.Bytes(), res.Success.Body.Bytes())
+		assert.Equal(t, etag, res.Success.Header().Get("ETag"))
+	}
+
+	testCases := []testingx.TestCase[string, *httptest.ResponseRecorder]{
+		{
+			Name:    "Match",
+			Input:   etag,
+			CheckFn: checkNotModified,
+		},
+		{
+			Name:    "WeakMatch",
+			Input:   `"other", W/` + etag,
+			CheckFn: checkNotModified,
+		},
+		{
+			Name:    "Wildcard",
+			Input:   "*",
+			CheckFn: checkNotModified,
+		},
+		{
+			Name:    "NoMatch",
+			Input:   `"other"`,
+			CheckFn: checkModified,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestJWKSChanged checks that a replaced signing JWKS is served with a new entity tag.
+func TestJWKSChanged(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &fositex.OAuth2Config{
+		SigningJWKS: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{Key: key, KeyID: "a", Algorithm: string(jose.ES256)},
+			},
+		},
+	}
+
+	handler := &jwksHandler{
+		logger: zap.NewNop().Sugar(),
+		config: config,
+	}
+
+	engine := gin.New()
+	engine.GET(jwksPath, handler.Handle)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, jwksPath, nil))
+
+		return w
+	}
+
+	before := get()
+
+	assert.Equal(t, "public, max-age=900", before.Header().Get("Cache-Control"))
+
+	config.SigningJWKS = &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{},
+	}
+
+	after := get()
+
+	assert.NotEqual(t, before.Header().Get("ETag"), after.Header().Get("ETag"))
+	assert.JSONEq(t, `{"keys":[]}`, after.Body.String())
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+const (
+	// OpenIDConfigurationPath is the path of the OpenID Connect discovery metadata.
+	OpenIDConfigurationPath = "/.well-known/openid-configuration"
+	// OAuthAuthorizationServerPath is the path of the RFC 8414 authorization server metadata.
+	OAuthAuthorizationServerPath = "/.well-known/oauth-authorization-server"
+
+	userInfoPath = "userinfo"
+)
+
+type metadataHandler struct {
+	logger *zap.SugaredLogger
+	config fositex.OAuth2Configurator
+}
+
+// Handle processes the request for the server metadata handler.
+func (h *metadataHandler) Handle(ctx *gin.Context) {
+	md := h.config.GetServerMetadata(ctx)
+
+	if err := populateEndpoints(&md); err != nil {
+		h.logger.Errorf("Error occurred building server metadata: %+v", err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, md)
+}
+
+// populateEndpoints sets the URLs of the endpoints routed by identity-api relative to the issuer.
+func populateEndpoints(md *fositex.ServerMetadata) error {
+	var err error
+
+	md.TokenEndpoint, err = url.JoinPath(md.Issuer, tokenPath)
+	if err != nil {
+		return err
+	}
+
+	md.JWKSURI, err = url.JoinPath(md.Issuer, jwksPath)
+	if err != nil {
+		return err
+	}
+
+	md.UserInfoEndpoint, err = url.JoinPath(md.Issuer, userInfoPath)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
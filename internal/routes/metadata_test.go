@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/fosite"
+	fositestorage "github.com/ory/fosite/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+type grantTypeContributor string
+
+func (c grantTypeContributor) ContributeServerMetadata(md *fositex.ServerMetadata) {
+	md.GrantTypesSupported = append(md.GrantTypesSupported, string(c))
+}
+
+// TestServerMetadata checks that server metadata is published at both well-known paths.
+func TestServerMetadata(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Symmetric keys are not published, so their algorithms are not listed.
+	config := &fositex.OAuth2Config{
+		Config: &fosite.Config{
+			AccessTokenIssuer: "https://iam.example.com/",
+		},
+		SigningJWKS: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{KeyID: "a", Algorithm: string(jose.RS256), Key: rsaKey},
+				{KeyID: "b", Algorithm: string(jose.RS256), Key: &rsaKey.PublicKey},
+				{KeyID: "c", Algorithm: string(jose.HS256), Key: []byte("secret")},
+			},
+		},
+	}
+
+	factory := func(config fositex.OAuth2Configurator, storage any, strategy any) any {
+		return grantTypeContributor("urn:example:grant")
+	}
+
+	provider := fositex.NewOAuth2Provider(config, fositestorage.NewExampleStore(), nil, factory)
+
+	engine := gin.New()
+	NewRouter(zap.NewNop().Sugar(), config, provider).Routes(engine.Group("/"))
+
+	runFn := func(ctx context.Context, path string) testingx.TestResult[fositex.ServerMetadata] {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		engine.ServeHTTP(w, req)
+
+		var md fositex.ServerMetadata
+
+		err := json.NewDecoder(w.Body).Decode(&md)
+
+		return testingx.TestResult[fositex.ServerMetadata]{
+			Success: md,
+			Err:     err,
+		}
+	}
+
+	expected := fositex.ServerMetadata{
+		Issuer:                           "https://iam.example.com/",
+		TokenEndpoint:                    "https://iam.example.com/token",
+		JWKSURI:                          "https://iam.example.com/jwks.json",
+		UserInfoEndpoint:                 "https://iam.example.com/userinfo",
+		ResponseTypesSupported:           []string{},
+		GrantTypesSupported:              []string{"urn:example:grant"},
+		SubjectTokenTypesSupported:       nil,
+		SubjectTypesSupported:            []string{fositex.SubjectTypePublic},
+		IDTokenSigningAlgValuesSupported: []string{string(jose.RS256)},
+	}
+
+	checkFn := func(ctx context.Context, t *testing.T, res testingx.TestResult[fositex.ServerMetadata]) {
+		if assert.NoError(t, res.Err) {
+			assert.Equal(t, expected, res.Success)
+		}
+	}
+
+	testCases := []testingx.TestCase[string, fositex.ServerMetadata]{
+		{
+			Name:    "OpenIDConfiguration",
+			Input:   OpenIDConfigurationPath,
+			CheckFn: checkFn,
+		},
+		{
+			Name:    "OAuthAuthorizationServer",
+			Input:   OAuthAuthorizationServerPath,
+			CheckFn: checkFn,
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -9,6 +9,11 @@ import (
 	"go.infratographer.com/identity-api/internal/fositex"
 )
 
+const (
+	tokenPath = "/token"
+	jwksPath  = "/jwks.json"
+)
+
 // Router is the router for the application.
 type Router struct {
 	logger   *zap.SugaredLogger
@@ -35,7 +40,13 @@ func (r *Router) Routes(rg *gin.RouterGroup) {
 		logger: r.logger,
 		config: r.config,
 	}
+	md := &metadataHandler{
+		logger: r.logger,
+		config: r.config,
+	}
 
-	rg.POST("/token", tok.Handle)
-	rg.GET("/jwks.json", jwks.Handle)
+	rg.POST(tokenPath, tok.Handle)
+	rg.GET(jwksPath, jwks.Handle)
+	rg.GET(OpenIDConfigurationPath, md.Handle)
+	rg.GET(OAuthAuthorizationServerPath, md.Handle)
 }
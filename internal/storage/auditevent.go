@@ -0,0 +1,300 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var auditEventCols = struct {
+	ID           string
+	TenantID     string
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	RequestID    string
+	Before       string
+	After        string
+	CreatedAt    string
+}{
+	ID:           "id",
+	TenantID:     "tenant_id",
+	Actor:        "actor",
+	Action:       "action",
+	ResourceType: "resource_type",
+	ResourceID:   "resource_id",
+	RequestID:    "request_id",
+	Before:       "before_state",
+	After:        "after_state",
+	CreatedAt:    "created_at",
+}
+
+var (
+	auditEventColumns = []string{
+		auditEventCols.ID,
+		auditEventCols.TenantID,
+		auditEventCols.Actor,
+		auditEventCols.Action,
+		auditEventCols.ResourceType,
+		auditEventCols.ResourceID,
+		auditEventCols.RequestID,
+		auditEventCols.Before,
+		auditEventCols.After,
+		auditEventCols.CreatedAt,
+	}
+	auditEventColumnsStr = strings.Join(auditEventColumns, ", ")
+)
+
+// auditEventCursor represents the position of the last audit event of a page.
+type auditEventCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+}
+
+func (c auditEventCursor) encode() (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeAuditEventCursor(cursor string) (auditEventCursor, error) {
+	var out auditEventCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return out, fmt.Errorf("%w: malformed cursor", types.ErrInvalidAuditEventListOptions)
+	}
+
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("%w: malformed cursor", types.ErrInvalidAuditEventListOptions)
+	}
+
+	return out, nil
+}
+
+// auditEventService represents a SQL-backed audit event service.
+type auditEventService struct {
+	db *sql.DB
+}
+
+func newAuditEventService(config Config, db *sql.DB) (*auditEventService, error) {
+	svc := &auditEventService{
+		db: db,
+	}
+
+	return svc, nil
+}
+
+// RecordAuditEvent records an audit event. Events are only recorded in a transaction in the context, so
+// they are committed or rolled back with the change they describe.
+func (s *auditEventService) RecordAuditEvent(ctx context.Context, event types.AuditEvent) (*types.AuditEvent, error) {
+	tx, err := getContextTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps are stored with microsecond precision, so the returned event matches the stored one.
+	event.CreatedAt = event.CreatedAt.Truncate(time.Microsecond)
+
+	q := fmt.Sprintf(
+		"INSERT INTO audit_events (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		auditEventColumnsStr,
+	)
+
+	_, err = tx.ExecContext(
+		ctx,
+		q,
+		event.ID,
+		nullableString(event.TenantID),
+		event.Actor,
+		string(event.Action),
+		event.ResourceType,
+		event.ResourceID,
+		event.RequestID,
+		nullableJSON(event.Before),
+		nullableJSON(event.After),
+		event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// ListAuditEvents lists a page of the given tenant's audit events, newest first, or of the events not
+// scoped to a tenant if tenantID is empty. Pages are keyed by the time and ID of the last event listed,
+// so events recorded between pages do not shift later pages. This function will use a transaction in the
+// context if one exists.
+func (s *auditEventService) ListAuditEvents(ctx context.Context, tenantID string, opts types.AuditEventListOptions) (*types.AuditEventPage, error) {
+	switch {
+	case opts.Limit <= 0:
+		opts.Limit = types.DefaultAuditEventListLimit
+	case opts.Limit > types.MaxAuditEventListLimit:
+		opts.Limit = types.MaxAuditEventListLimit
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if tenantID == "" {
+		conditions = append(conditions, auditEventCols.TenantID+" IS NULL")
+	} else {
+		args = append(args, tenantID)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", auditEventCols.TenantID, len(args)))
+	}
+
+	if opts.ResourceID != "" {
+		args = append(args, opts.ResourceID)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", auditEventCols.ResourceID, len(args)))
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := decodeAuditEventCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		conditions = append(
+			conditions,
+			fmt.Sprintf("(%s, %s) < ($%d, $%d::UUID)", auditEventCols.CreatedAt, auditEventCols.ID, len(args)-1, len(args)),
+		)
+	}
+
+	// One more event than requested is listed, to tell whether there is a next page.
+	args = append(args, opts.Limit+1)
+
+	q := fmt.Sprintf(
+		"SELECT %s FROM audit_events WHERE %s ORDER BY %s DESC, %s DESC LIMIT $%d",
+		auditEventColumnsStr,
+		strings.Join(conditions, " AND "),
+		auditEventCols.CreatedAt,
+		auditEventCols.ID,
+		len(args),
+	)
+
+	rows, err := s.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	out := &types.AuditEventPage{
+		Events: []types.AuditEvent{},
+	}
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Events = append(out.Events, *event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(out.Events) <= opts.Limit {
+		return out, nil
+	}
+
+	out.Events = out.Events[:opts.Limit]
+	last := out.Events[opts.Limit-1]
+
+	cursor := auditEventCursor{
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+	}
+
+	out.NextCursor, err = cursor.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *auditEventService) query(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		return tx.QueryContext(ctx, q, args...)
+	case ErrorMissingContextTx:
+		return s.db.QueryContext(ctx, q, args...)
+	default:
+		return nil, err
+	}
+}
+
+func scanAuditEvent(row rowScanner) (*types.AuditEvent, error) {
+	var (
+		event         types.AuditEvent
+		tenantID      sql.NullString
+		action        string
+		before, after sql.NullString
+	)
+
+	err := row.Scan(
+		&event.ID,
+		&tenantID,
+		&event.Actor,
+		&action,
+		&event.ResourceType,
+		&event.ResourceID,
+		&event.RequestID,
+		&before,
+		&after,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.TenantID = tenantID.String
+	event.Action = types.AuditAction(action)
+
+	if before.Valid {
+		event.Before = json.RawMessage(before.String)
+	}
+
+	if after.Valid {
+		event.After = json.RawMessage(after.String)
+	}
+
+	return &event, nil
+}
+
+// nullableString returns the given string, or nil if it is empty, so it is stored as NULL.
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// nullableJSON returns the given JSON as a string, or nil if there is none, so it is stored as NULL.
+func nullableJSON(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func TestAuditEventService(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	svc, err := newAuditEventService(Config{}, db)
+	assert.Nil(t, err)
+
+	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
+	issuerID := "e495a393-ae79-4a02-a78d-9798c7d9d252"
+	recordedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := []types.AuditEvent{
+		{
+			ID:           "7c1e8a52-3f0b-4d6e-9a21-5b8c4d7e2f01",
+			TenantID:     tenantID,
+			Actor:        "urn:example:user/admin",
+			Action:       types.AuditActionCreate,
+			ResourceType: types.AuditResourceIssuer,
+			ResourceID:   issuerID,
+			RequestID:    "request-1",
+			After:        json.RawMessage(`{"name":"Example"}`),
+			CreatedAt:    recordedAt,
+		},
+		{
+			ID:           "7c1e8a52-3f0b-4d6e-9a21-5b8c4d7e2f02",
+			TenantID:     tenantID,
+			Actor:        "urn:example:user/admin",
+			Action:       types.AuditActionUpdate,
+			ResourceType: types.AuditResourceIssuer,
+			ResourceID:   issuerID,
+			RequestID:    "request-2",
+			Before:       json.RawMessage(`{"name":"Example"}`),
+			After:        json.RawMessage(`{"name":"Renamed"}`),
+			CreatedAt:    recordedAt.Add(time.Minute),
+		},
+		{
+			ID:           "7c1e8a52-3f0b-4d6e-9a21-5b8c4d7e2f03",
+			TenantID:     tenantID,
+			Actor:        "urn:example:user/admin",
+			Action:       types.AuditActionCreate,
+			ResourceType: types.AuditResourceRoleMapping,
+			ResourceID:   "0b6b2b5e-0c0a-4c36-8e8b-3a0d2f1f6a01",
+			RequestID:    "request-3",
+			After:        json.RawMessage(`{"group":"admins","role":"admin"}`),
+			CreatedAt:    recordedAt.Add(2 * time.Minute),
+		},
+		{
+			ID:           "7c1e8a52-3f0b-4d6e-9a21-5b8c4d7e2f04",
+			Actor:        "urn:example:user/admin",
+			Action:       types.AuditActionCreate,
+			ResourceType: types.AuditResourceSigningKey,
+			ResourceID:   "key-1",
+			RequestID:    "request-4",
+			After:        json.RawMessage(`{"state":"pending"}`),
+			CreatedAt:    recordedAt.Add(3 * time.Minute),
+		},
+	}
+
+	setupFn := func(ctx context.Context) context.Context {
+		ctx, err := beginTxContext(ctx, db)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+
+		for _, event := range events {
+			_, err = svc.RecordAuditEvent(ctx, event)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+		}
+
+		return ctx
+	}
+
+	cleanupFn := func(ctx context.Context) {
+		err := rollbackContextTx(ctx)
+		assert.NoError(t, err)
+	}
+
+	t.Run("RecordWithoutTransaction", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := svc.RecordAuditEvent(context.Background(), events[0])
+		assert.ErrorIs(t, err, ErrorMissingContextTx)
+	})
+
+	t.Run("ListAuditEvents", func(t *testing.T) {
+		t.Parallel()
+
+		type input struct {
+			tenantID string
+			opts     types.AuditEventListOptions
+			// paginate lists the next page after the first, if any.
+			paginate bool
+		}
+
+		testCases := []testingx.TestCase[input, *types.AuditEventPage]{
+			{
+				Name: "NewestFirst",
+				Input: input{
+					tenantID: tenantID,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					if !assert.NoError(t, res.Err) || !assert.Len(t, res.Success.Events, 3) {
+						return
+					}
+
+					assert.Equal(t, events[2].ID, res.Success.Events[0].ID)
+					assert.Equal(t, events[0].ID, res.Success.Events[2].ID)
+					assert.JSONEq(t, string(events[1].Before), string(res.Success.Events[1].Before))
+					assert.Nil(t, res.Success.Events[2].Before)
+					assert.Empty(t, res.Success.NextCursor)
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "Paginated",
+				Input: input{
+					tenantID: tenantID,
+					opts:     types.AuditEventListOptions{Limit: 2},
+					paginate: true,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					if assert.NoError(t, res.Err) && assert.Len(t, res.Success.Events, 1) {
+						assert.Equal(t, events[0].ID, res.Success.Events[0].ID)
+						assert.Empty(t, res.Success.NextCursor)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "ResourceID",
+				Input: input{
+					tenantID: tenantID,
+					opts:     types.AuditEventListOptions{ResourceID: issuerID},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					if assert.NoError(t, res.Err) && assert.Len(t, res.Success.Events, 2) {
+						assert.Equal(t, types.AuditActionUpdate, res.Success.Events[0].Action)
+						assert.Equal(t, "request-2", res.Success.Events[0].RequestID)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "OtherTenant",
+				Input: input{
+					tenantID: "b8bfd705-b768-47a4-85a0-fe006f5bcfca",
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					if assert.NoError(t, res.Err) {
+						assert.Empty(t, res.Success.Events)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "Global",
+				Input: input{
+					tenantID: "",
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					if assert.NoError(t, res.Err) && assert.Len(t, res.Success.Events, 1) {
+						assert.Equal(t, events[3].ID, res.Success.Events[0].ID)
+						assert.Empty(t, res.Success.Events[0].TenantID)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "InvalidCursor",
+				Input: input{
+					tenantID: tenantID,
+					opts:     types.AuditEventListOptions{Cursor: "not a cursor"},
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.AuditEventPage]) {
+					assert.ErrorIs(t, res.Err, types.ErrInvalidAuditEventListOptions)
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, in input) testingx.TestResult[*types.AuditEventPage] {
+			page, err := svc.ListAuditEvents(ctx, in.tenantID, in.opts)
+
+			if err == nil && in.paginate {
+				in.opts.Cursor = page.NextCursor
+
+				page, err = svc.ListAuditEvents(ctx, in.tenantID, in.opts)
+			}
+
+			return testingx.TestResult[*types.AuditEventPage]{
+				Success: page,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+}
@@ -1,6 +1,10 @@
 package storage
 
-import "go.infratographer.com/x/crdbx"
+import (
+	"time"
+
+	"go.infratographer.com/x/crdbx"
+)
 
 // Config represents the storage configuration for identity-api.
 type Config struct {
@@ -8,4 +12,7 @@ type Config struct {
 	Tracing  bool
 	CRDB     crdbx.Config
 	SeedData SeedData
+	// IssuerCacheTTL is how long issuers read outside of a transaction are cached. Defaults to one
+	// minute if unset; a negative value disables the cache.
+	IssuerCacheTTL time.Duration
 }
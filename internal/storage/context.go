@@ -3,12 +3,21 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"sync"
 )
 
 type contextKey int
 
 var txKey contextKey
 
+// txContext represents a transaction in a context, along with the functions to run once it commits.
+type txContext struct {
+	tx *sql.Tx
+
+	mu       sync.Mutex
+	onCommit []func()
+}
+
 func beginTxContext(ctx context.Context, db *sql.DB) (context.Context, error) {
 	tx, err := db.BeginTx(ctx, nil)
 
@@ -16,14 +25,23 @@ func beginTxContext(ctx context.Context, db *sql.DB) (context.Context, error) {
 		return nil, err
 	}
 
-	out := context.WithValue(ctx, txKey, tx)
+	out := context.WithValue(ctx, txKey, &txContext{tx: tx})
 
 	return out, nil
 }
 
 func getContextTx(ctx context.Context) (*sql.Tx, error) {
+	txCtx, err := getTxContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return txCtx.tx, nil
+}
+
+func getTxContext(ctx context.Context) (*txContext, error) {
 	switch v := ctx.Value(txKey).(type) {
-	case *sql.Tx:
+	case *txContext:
 		return v, nil
 	case nil:
 		return nil, ErrorMissingContextTx
@@ -32,20 +50,59 @@ func getContextTx(ctx context.Context) (*sql.Tx, error) {
 	}
 }
 
+// afterCommit registers fn to run once the transaction in the context commits, or runs it immediately
+// if there is no transaction. Functions registered on a transaction which is rolled back never run.
+func afterCommit(ctx context.Context, fn func()) {
+	txCtx, err := getTxContext(ctx)
+	if err != nil {
+		fn()
+
+		return
+	}
+
+	txCtx.mu.Lock()
+	defer txCtx.mu.Unlock()
+
+	txCtx.onCommit = append(txCtx.onCommit, fn)
+}
+
+// AfterCommit registers fn to run once the transaction in the context commits, or runs it immediately
+// if there is no transaction. Functions registered on a transaction which is rolled back never run.
+func AfterCommit(ctx context.Context, fn func()) {
+	afterCommit(ctx, fn)
+}
+
 func commitContextTx(ctx context.Context) error {
-	tx, err := getContextTx(ctx)
+	txCtx, err := getTxContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	if err := txCtx.tx.Commit(); err != nil {
+		return err
+	}
+
+	txCtx.mu.Lock()
+	hooks := txCtx.onCommit
+	txCtx.onCommit = nil
+	txCtx.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+
+	return nil
 }
 
 func rollbackContextTx(ctx context.Context) error {
-	tx, err := getContextTx(ctx)
+	txCtx, err := getTxContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tx.Rollback()
+	txCtx.mu.Lock()
+	txCtx.onCommit = nil
+	txCtx.mu.Unlock()
+
+	return txCtx.tx.Rollback()
 }
@@ -10,6 +10,11 @@ import (
 type crdbEngine struct {
 	*issuerService
 	*userInfoService
+	*groupRoleMappingService
+	*pairwiseSubjectService
+	*jwksSnapshotService
+	*signingKeyService
+	*auditEventService
 	db *sql.DB
 }
 
@@ -24,15 +29,45 @@ func newCRDBEngine(config Config) (*crdbEngine, error) {
 		return nil, err
 	}
 
-	userInfoSvc, err := newUserInfoService(config, db)
+	userInfoSvc, err := newUserInfoService(config, db, issSvc)
+	if err != nil {
+		return nil, err
+	}
+
+	groupRoleMappingSvc, err := newGroupRoleMappingService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	pairwiseSubjectSvc, err := newPairwiseSubjectService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksSnapshotSvc, err := newJWKSSnapshotService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKeySvc, err := newSigningKeyService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	auditEventSvc, err := newAuditEventService(config, db)
 	if err != nil {
 		return nil, err
 	}
 
 	out := &crdbEngine{
-		issuerService:   issSvc,
-		userInfoService: userInfoSvc,
-		db:              db,
+		issuerService:           issSvc,
+		userInfoService:         userInfoSvc,
+		groupRoleMappingService: groupRoleMappingSvc,
+		pairwiseSubjectService:  pairwiseSubjectSvc,
+		jwksSnapshotService:     jwksSnapshotSvc,
+		signingKeyService:       signingKeySvc,
+		auditEventService:       auditEventSvc,
+		db:                      db,
 	}
 
 	return out, nil
@@ -4,12 +4,14 @@ import "context"
 
 // SeedIssuer represents the seed data for a single issuer.
 type SeedIssuer struct {
-	TenantID      string
-	ID            string
-	Name          string
-	URI           string
-	JWKSURI       string
-	ClaimMappings map[string]string
+	TenantID              string
+	ID                    string
+	Name                  string
+	URI                   string
+	JWKSURI               string
+	UserInfoEndpoint      string
+	IntrospectionEndpoint string
+	ClaimMappings         map[string]string
 }
 
 // SeedData represents the seed data for an identity-api instance on startup.
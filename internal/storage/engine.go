@@ -28,6 +28,11 @@ type TransactionManager interface {
 type Engine interface {
 	types.IssuerService
 	types.UserInfoService
+	types.GroupRoleMappingService
+	types.PairwiseSubjectService
+	types.JWKSSnapshotService
+	types.SigningKeyService
+	types.AuditEventService
 	TransactionManager
 	Shutdown()
 }
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var groupRoleMappingCols = struct {
+	ID       string
+	TenantID string
+	IssuerID string
+	Group    string
+	Role     string
+}{
+	ID:       "id",
+	TenantID: "tenant_id",
+	IssuerID: "issuer_id",
+	Group:    "group_value",
+	Role:     "role",
+}
+
+var (
+	groupRoleMappingColumns = []string{
+		groupRoleMappingCols.ID,
+		groupRoleMappingCols.TenantID,
+		groupRoleMappingCols.IssuerID,
+		groupRoleMappingCols.Group,
+		groupRoleMappingCols.Role,
+	}
+	groupRoleMappingColumnsStr = strings.Join(groupRoleMappingColumns, ", ")
+)
+
+// groupRoleMappingService represents a SQL-backed group to role mapping service.
+type groupRoleMappingService struct {
+	db *sql.DB
+}
+
+func newGroupRoleMappingService(config Config, db *sql.DB) (*groupRoleMappingService, error) {
+	svc := &groupRoleMappingService{
+		db: db,
+	}
+
+	return svc, nil
+}
+
+// CreateGroupRoleMapping creates a group to role mapping.
+func (s *groupRoleMappingService) CreateGroupRoleMapping(ctx context.Context, mapping types.GroupRoleMapping) (*types.GroupRoleMapping, error) {
+	tx, err := getContextTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(`INSERT INTO group_role_mappings (%s) VALUES ($1, $2, $3, $4, $5)`, groupRoleMappingColumnsStr)
+
+	_, err = tx.ExecContext(
+		ctx,
+		q,
+		mapping.ID,
+		mapping.TenantID,
+		mapping.IssuerID,
+		mapping.Group,
+		mapping.Role,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+// ListGroupRoleMappings lists the group to role mappings for the given issuer. This function will use
+// a transaction in the context if one exists.
+func (s *groupRoleMappingService) ListGroupRoleMappings(ctx context.Context, issuerID string) ([]types.GroupRoleMapping, error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM group_role_mappings WHERE %s = $1 ORDER BY %s, %s",
+		groupRoleMappingColumnsStr,
+		groupRoleMappingCols.IssuerID,
+		groupRoleMappingCols.Group,
+		groupRoleMappingCols.Role,
+	)
+
+	rows, err := s.query(ctx, q, issuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	out := []types.GroupRoleMapping{}
+
+	for rows.Next() {
+		var mapping types.GroupRoleMapping
+
+		err := rows.Scan(&mapping.ID, &mapping.TenantID, &mapping.IssuerID, &mapping.Group, &mapping.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, mapping)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// DeleteGroupRoleMapping deletes the group to role mapping with the given ID from the given issuer
+// and returns the deleted mapping.
+func (s *groupRoleMappingService) DeleteGroupRoleMapping(ctx context.Context, issuerID, id string) (*types.GroupRoleMapping, error) {
+	tx, err := getContextTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(
+		"DELETE FROM group_role_mappings WHERE %s = $1 AND %s = $2 RETURNING %s",
+		groupRoleMappingCols.IssuerID,
+		groupRoleMappingCols.ID,
+		groupRoleMappingColumnsStr,
+	)
+
+	var mapping types.GroupRoleMapping
+
+	err = tx.QueryRowContext(ctx, q, issuerID, id).Scan(
+		&mapping.ID,
+		&mapping.TenantID,
+		&mapping.IssuerID,
+		&mapping.Group,
+		&mapping.Role,
+	)
+
+	switch {
+	case err == nil:
+		return &mapping, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, types.ErrorGroupRoleMappingNotFound
+	default:
+		return nil, err
+	}
+}
+
+// LookupRolesByGroups returns the sorted, distinct roles mapped from the given groups for the given
+// issuer. This function will use a transaction in the context if one exists.
+func (s *groupRoleMappingService) LookupRolesByGroups(ctx context.Context, issuerID string, groups []string) ([]string, error) {
+	if len(groups) == 0 {
+		return []string{}, nil
+	}
+
+	params := make([]string, len(groups))
+	args := make([]any, 0, len(groups)+1)
+
+	args = append(args, issuerID)
+
+	for i, group := range groups {
+		params[i] = fmt.Sprintf("$%d", i+2)
+
+		args = append(args, group)
+	}
+
+	q := fmt.Sprintf(
+		"SELECT DISTINCT %[1]s FROM group_role_mappings WHERE %[2]s = $1 AND %[3]s IN (%[4]s) ORDER BY %[1]s",
+		groupRoleMappingCols.Role,
+		groupRoleMappingCols.IssuerID,
+		groupRoleMappingCols.Group,
+		strings.Join(params, ", "),
+	)
+
+	rows, err := s.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	out := []string{}
+
+	for rows.Next() {
+		var role string
+
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+
+		out = append(out, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *groupRoleMappingService) query(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		return tx.QueryContext(ctx, q, args...)
+	case ErrorMissingContextTx:
+		return s.db.QueryContext(ctx, q, args...)
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func TestGroupRoleMappingService(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	tenantID := "56a95c1b-33f8-4def-8b6d-ca9fe6976170"
+	issuerID := "e495a393-ae79-4a02-a78d-9798c7d9d252"
+
+	config := Config{
+		SeedData: SeedData{
+			Issuers: []SeedIssuer{
+				{
+					TenantID: tenantID,
+					ID:       issuerID,
+					Name:     "Example",
+					URI:      "https://example.com/",
+					JWKSURI:  "https://example.com/.well-known/jwks.json",
+				},
+			},
+		},
+	}
+
+	issSvc, err := newIssuerService(config, db)
+	assert.Nil(t, err)
+
+	err = issSvc.seedDatabase(context.Background(), config.SeedData.Issuers)
+	assert.Nil(t, err)
+
+	svc, err := newGroupRoleMappingService(config, db)
+	assert.Nil(t, err)
+
+	mappings := []types.GroupRoleMapping{
+		{
+			ID:       "0b6b2b5e-0c0a-4c36-8e8b-3a0d2f1f6a01",
+			TenantID: tenantID,
+			IssuerID: issuerID,
+			Group:    "admins",
+			Role:     "admin",
+		},
+		{
+			ID:       "0b6b2b5e-0c0a-4c36-8e8b-3a0d2f1f6a02",
+			TenantID: tenantID,
+			IssuerID: issuerID,
+			Group:    "admins",
+			Role:     "viewer",
+		},
+		{
+			ID:       "0b6b2b5e-0c0a-4c36-8e8b-3a0d2f1f6a03",
+			TenantID: tenantID,
+			IssuerID: issuerID,
+			Group:    "users",
+			Role:     "viewer",
+		},
+	}
+
+	setupFn := func(ctx context.Context) context.Context {
+		ctx, err := beginTxContext(ctx, db)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+
+		for _, mapping := range mappings {
+			_, err = svc.CreateGroupRoleMapping(ctx, mapping)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+		}
+
+		return ctx
+	}
+
+	cleanupFn := func(ctx context.Context) {
+		err := rollbackContextTx(ctx)
+		assert.NoError(t, err)
+	}
+
+	t.Run("ListGroupRoleMappings", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []testingx.TestCase[string, []types.GroupRoleMapping]{
+			{
+				Name:    "Success",
+				Input:   issuerID,
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]types.GroupRoleMapping]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, mappings, res.Success)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:    "Empty",
+				Input:   "00000000-0000-0000-0000-000000000000",
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]types.GroupRoleMapping]) {
+					if assert.NoError(t, res.Err) {
+						assert.Empty(t, res.Success)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input string) testingx.TestResult[[]types.GroupRoleMapping] {
+			out, err := svc.ListGroupRoleMappings(ctx, input)
+
+			return testingx.TestResult[[]types.GroupRoleMapping]{
+				Success: out,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("LookupRolesByGroups", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []testingx.TestCase[[]string, []string]{
+			{
+				Name:    "Distinct",
+				Input:   []string{"users", "admins", "unknown"},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, []string{"admin", "viewer"}, res.Success)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:    "NoGroups",
+				Input:   []string{},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+					if assert.NoError(t, res.Err) {
+						assert.Empty(t, res.Success)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input []string) testingx.TestResult[[]string] {
+			out, err := svc.LookupRolesByGroups(ctx, issuerID, input)
+
+			return testingx.TestResult[[]string]{
+				Success: out,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+
+	t.Run("DeleteGroupRoleMapping", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []testingx.TestCase[string, *types.GroupRoleMapping]{
+			{
+				Name:    "Success",
+				Input:   mappings[0].ID,
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.GroupRoleMapping]) {
+					if assert.NoError(t, res.Err) {
+						assert.Equal(t, mappings[0], *res.Success)
+
+						roles, err := svc.LookupRolesByGroups(ctx, issuerID, []string{"admins"})
+						assert.NoError(t, err)
+						assert.Equal(t, []string{"viewer"}, roles)
+					}
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:    "NotFound",
+				Input:   "00000000-0000-0000-0000-000000000000",
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.GroupRoleMapping]) {
+					assert.ErrorIs(t, res.Err, types.ErrorGroupRoleMappingNotFound)
+				},
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		runFn := func(ctx context.Context, input string) testingx.TestResult[*types.GroupRoleMapping] {
+			mapping, err := svc.DeleteGroupRoleMapping(ctx, issuerID, input)
+
+			return testingx.TestResult[*types.GroupRoleMapping]{
+				Success: mapping,
+				Err:     err,
+			}
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
+}
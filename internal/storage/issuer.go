@@ -3,27 +3,39 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 
+	"gopkg.in/square/go-jose.v2"
+
 	"go.infratographer.com/identity-api/internal/types"
 )
 
 var issuerCols = struct {
-	TenantID string
-	ID       string
-	Name     string
-	URI      string
-	JWKSURI  string
-	Mappings string
+	TenantID              string
+	ID                    string
+	Name                  string
+	URI                   string
+	JWKSURI               string
+	JWKS                  string
+	UserInfoEndpoint      string
+	IntrospectionEndpoint string
+	Mappings              string
+	MappingOutputs        string
 }{
-	TenantID: "tenant_id",
-	ID:       "id",
-	Name:     "name",
-	URI:      "uri",
-	JWKSURI:  "jwksuri",
-	Mappings: "mappings",
+	TenantID:              "tenant_id",
+	ID:                    "id",
+	Name:                  "name",
+	URI:                   "uri",
+	JWKSURI:               "jwksuri",
+	JWKS:                  "jwks",
+	UserInfoEndpoint:      "userinfo_endpoint",
+	IntrospectionEndpoint: "introspection_endpoint",
+	Mappings:              "mappings",
+	MappingOutputs:        "mapping_outputs",
 }
 
 var (
@@ -33,19 +45,63 @@ var (
 		issuerCols.Name,
 		issuerCols.URI,
 		issuerCols.JWKSURI,
+		issuerCols.JWKS,
+		issuerCols.UserInfoEndpoint,
+		issuerCols.IntrospectionEndpoint,
 		issuerCols.Mappings,
+		issuerCols.MappingOutputs,
 	}
 	issuerColumnsStr = strings.Join(issuerColumns, ", ")
 )
 
+// issuerSortColumns maps the fields issuers can be sorted by to their columns.
+var issuerSortColumns = map[types.IssuerSortField]string{
+	types.IssuerSortName: issuerCols.Name,
+	types.IssuerSortURI:  issuerCols.URI,
+}
+
+// issuerCursor represents the position of the last issuer of a page, in the sort order it was listed in.
+type issuerCursor struct {
+	SortBy     types.IssuerSortField `json:"s"`
+	Descending bool                  `json:"d,omitempty"`
+	Value      string                `json:"v"`
+	ID         string                `json:"i"`
+}
+
+func (c issuerCursor) encode() (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeIssuerCursor(cursor string) (issuerCursor, error) {
+	var out issuerCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return out, fmt.Errorf("%w: malformed cursor", types.ErrInvalidIssuerListOptions)
+	}
+
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("%w: malformed cursor", types.ErrInvalidIssuerListOptions)
+	}
+
+	return out, nil
+}
+
 // issuerService represents a SQL-backed issuer service.
 type issuerService struct {
-	db *sql.DB
+	db    *sql.DB
+	cache *issuerCache
 }
 
 func newIssuerService(config Config, db *sql.DB) (*issuerService, error) {
 	svc := &issuerService{
-		db: db,
+		db:    db,
+		cache: newIssuerCache(config.IssuerCacheTTL),
 	}
 
 	return svc, nil
@@ -92,11 +148,14 @@ func (s *issuerService) CreateIssuer(ctx context.Context, iss types.Issuer) (*ty
 }
 
 // GetIssuerByID gets an issuer by ID. This function will use a transaction in the context if one
-// exists.
+// exists. Lookups outside of a transaction are served from the issuer cache when possible.
 func (s *issuerService) GetIssuerByID(ctx context.Context, id string) (*types.Issuer, error) {
 	query := fmt.Sprintf("SELECT %s FROM issuers WHERE id = $1", issuerColumnsStr)
 
-	var row *sql.Row
+	var (
+		row     *sql.Row
+		version uint64
+	)
 
 	tx, err := getContextTx(ctx)
 
@@ -104,20 +163,38 @@ func (s *issuerService) GetIssuerByID(ctx context.Context, id string) (*types.Is
 	case nil:
 		row = tx.QueryRowContext(ctx, query, id)
 	case ErrorMissingContextTx:
+		if iss, ok := s.cache.getByID(id); ok {
+			return iss, nil
+		}
+
+		version = s.cache.version()
 		row = s.db.QueryRowContext(ctx, query, id)
 	default:
 		return nil, err
 	}
 
-	return s.scanIssuer(row)
+	iss, err := s.scanIssuer(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx == nil {
+		s.cache.put(iss, version)
+	}
+
+	return iss, nil
 }
 
 // GetIssuerByURI looks up the given issuer by URI, returning the issuer if one exists. This function will
-// use a transaction in the context if one exists.
+// use a transaction in the context if one exists. Lookups outside of a transaction are served from the
+// issuer cache when possible.
 func (s *issuerService) GetIssuerByURI(ctx context.Context, uri string) (*types.Issuer, error) {
 	query := fmt.Sprintf("SELECT %s FROM issuers WHERE uri = $1", issuerColumnsStr)
 
-	var row *sql.Row
+	var (
+		row     *sql.Row
+		version uint64
+	)
 
 	tx, err := getContextTx(ctx)
 
@@ -125,12 +202,142 @@ func (s *issuerService) GetIssuerByURI(ctx context.Context, uri string) (*types.
 	case nil:
 		row = tx.QueryRowContext(ctx, query, uri)
 	case ErrorMissingContextTx:
+		if iss, ok := s.cache.getByURI(uri); ok {
+			return iss, nil
+		}
+
+		version = s.cache.version()
 		row = s.db.QueryRowContext(ctx, query, uri)
 	default:
 		return nil, err
 	}
 
-	return s.scanIssuer(row)
+	iss, err := s.scanIssuer(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx == nil {
+		s.cache.put(iss, version)
+	}
+
+	return iss, nil
+}
+
+// ListIssuers lists a page of the given tenant's issuers. Pages are keyed by the sort column and ID of
+// the last issuer listed, so issuers created or deleted between pages do not shift later pages. This
+// function will use a transaction in the context if one exists.
+func (s *issuerService) ListIssuers(ctx context.Context, tenantID string, opts types.IssuerListOptions) (*types.IssuerPage, error) {
+	if opts.SortBy == "" {
+		opts.SortBy = types.IssuerSortName
+	}
+
+	sortCol, ok := issuerSortColumns[opts.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort field '%s'", types.ErrInvalidIssuerListOptions, opts.SortBy)
+	}
+
+	switch {
+	case opts.Limit <= 0:
+		opts.Limit = types.DefaultIssuerListLimit
+	case opts.Limit > types.MaxIssuerListLimit:
+		opts.Limit = types.MaxIssuerListLimit
+	}
+
+	conditions := []string{issuerCols.TenantID + " = $1"}
+	args := []any{tenantID}
+
+	if opts.Name != "" {
+		args = append(args, opts.Name)
+		conditions = append(conditions, fmt.Sprintf("strpos(lower(%s), lower($%d)) > 0", issuerCols.Name, len(args)))
+	}
+
+	if opts.URI != "" {
+		args = append(args, opts.URI)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", issuerCols.URI, len(args)))
+	}
+
+	order, cmp := "ASC", ">"
+	if opts.Descending {
+		order, cmp = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := decodeIssuerCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursor.SortBy != opts.SortBy || cursor.Descending != opts.Descending {
+			return nil, fmt.Errorf("%w: cursor does not match sort order", types.ErrInvalidIssuerListOptions)
+		}
+
+		args = append(args, cursor.Value, cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, %s) %s ($%d, $%d::UUID)", sortCol, issuerCols.ID, cmp, len(args)-1, len(args)))
+	}
+
+	// One more issuer than requested is listed, to tell whether there is a next page.
+	args = append(args, opts.Limit+1)
+
+	q := fmt.Sprintf(
+		"SELECT %s FROM issuers WHERE %s ORDER BY %s %s, %s %s LIMIT $%d",
+		issuerColumnsStr,
+		strings.Join(conditions, " AND "),
+		sortCol,
+		order,
+		issuerCols.ID,
+		order,
+		len(args),
+	)
+
+	rows, err := s.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	out := &types.IssuerPage{
+		Issuers: []types.Issuer{},
+	}
+
+	for rows.Next() {
+		iss, err := s.scanIssuer(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Issuers = append(out.Issuers, *iss)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(out.Issuers) <= opts.Limit {
+		return out, nil
+	}
+
+	out.Issuers = out.Issuers[:opts.Limit]
+	last := out.Issuers[opts.Limit-1]
+
+	cursor := issuerCursor{
+		SortBy:     opts.SortBy,
+		Descending: opts.Descending,
+		Value:      last.Name,
+		ID:         last.ID,
+	}
+
+	if opts.SortBy == types.IssuerSortURI {
+		cursor.Value = last.URI
+	}
+
+	out.NextCursor, err = cursor.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
 }
 
 // UpdateIssuer updates an issuer with the given values.
@@ -153,6 +360,10 @@ func (s *issuerService) UpdateIssuer(ctx context.Context, id string, update type
 
 	row := tx.QueryRowContext(ctx, query, args...)
 
+	// The cached issuer is only invalidated once the update commits, so it cannot be replaced by the
+	// issuer as it was before the update.
+	afterCommit(ctx, func() { s.cache.invalidate(id) })
+
 	return s.scanIssuer(row)
 }
 
@@ -169,6 +380,8 @@ func (s *issuerService) DeleteIssuer(ctx context.Context, id string) error {
 		return err
 	}
 
+	afterCommit(ctx, func() { s.cache.invalidate(id) })
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
@@ -181,12 +394,36 @@ func (s *issuerService) DeleteIssuer(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *issuerService) scanIssuer(row *sql.Row) (*types.Issuer, error) {
-	var iss types.Issuer
+func (s *issuerService) query(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		return tx.QueryContext(ctx, q, args...)
+	case ErrorMissingContextTx:
+		return s.db.QueryContext(ctx, q, args...)
+	default:
+		return nil, err
+	}
+}
 
-	var mapping sql.NullString
+func (s *issuerService) scanIssuer(row rowScanner) (*types.Issuer, error) {
+	var iss types.Issuer
 
-	err := row.Scan(&iss.TenantID, &iss.ID, &iss.Name, &iss.URI, &iss.JWKSURI, &mapping)
+	var jwks, mapping, mappingOutputs sql.NullString
+
+	err := row.Scan(
+		&iss.TenantID,
+		&iss.ID,
+		&iss.Name,
+		&iss.URI,
+		&iss.JWKSURI,
+		&jwks,
+		&iss.UserInfoEndpoint,
+		&iss.IntrospectionEndpoint,
+		&mapping,
+		&mappingOutputs,
+	)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -196,6 +433,17 @@ func (s *issuerService) scanIssuer(row *sql.Row) (*types.Issuer, error) {
 	default:
 	}
 
+	if jwks.Valid {
+		var set jose.JSONWebKeySet
+
+		err = json.Unmarshal([]byte(jwks.String), &set)
+		if err != nil {
+			return nil, err
+		}
+
+		iss.JWKS = &set
+	}
+
 	c := types.ClaimsMapping{}
 
 	if mapping.Valid {
@@ -207,6 +455,17 @@ func (s *issuerService) scanIssuer(row *sql.Row) (*types.Issuer, error) {
 		iss.ClaimMappings = c
 	}
 
+	if mappingOutputs.Valid {
+		var outputs types.ClaimMappingOutputs
+
+		err = json.Unmarshal([]byte(mappingOutputs.String), &outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		iss.ClaimMappingOutputs = outputs
+	}
+
 	return &iss, nil
 }
 
@@ -220,7 +479,7 @@ func (s *issuerService) insertIssuer(ctx context.Context, iss types.Issuer) erro
         INSERT INTO issuers (
             %s
         ) VALUES
-        ($1, $2, $3, $4, $5, $6);
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
         `
 
 	q = fmt.Sprintf(q, issuerColumnsStr)
@@ -230,6 +489,25 @@ func (s *issuerService) insertIssuer(ctx context.Context, iss types.Issuer) erro
 		return err
 	}
 
+	jwks, err := marshalIssuerJWKS(iss.JWKS)
+	if err != nil {
+		return err
+	}
+
+	var mappingOutputs sql.NullString
+
+	if len(iss.ClaimMappingOutputs) > 0 {
+		outputs, err := json.Marshal(iss.ClaimMappingOutputs)
+		if err != nil {
+			return err
+		}
+
+		mappingOutputs = sql.NullString{
+			String: string(outputs),
+			Valid:  true,
+		}
+	}
+
 	_, err = tx.ExecContext(
 		ctx,
 		q,
@@ -238,8 +516,32 @@ func (s *issuerService) insertIssuer(ctx context.Context, iss types.Issuer) erro
 		iss.Name,
 		iss.URI,
 		iss.JWKSURI,
+		jwks,
+		iss.UserInfoEndpoint,
+		iss.IntrospectionEndpoint,
 		string(mappings),
+		mappingOutputs,
 	)
 
 	return err
 }
+
+// marshalIssuerJWKS marshals an issuer's inline JWKS for storage. A nil JWKS or one with no keys is stored
+// as NULL.
+func marshalIssuerJWKS(jwks *jose.JSONWebKeySet) (sql.NullString, error) {
+	if jwks == nil || len(jwks.Keys) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	raw, err := json.Marshal(jwks)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	out := sql.NullString{
+		String: string(raw),
+		Valid:  true,
+	}
+
+	return out, nil
+}
@@ -25,6 +25,8 @@ func compareIssuers(t *testing.T, exp types.Issuer, obs types.Issuer) {
 
 	exp.ClaimMappings = nil
 	obs.ClaimMappings = nil
+	exp.ClaimMappingPrograms = nil
+	obs.ClaimMappingPrograms = nil
 
 	assert.Equal(t, exp, obs)
 	assert.Equal(t, expMappings, obsMappings)
@@ -49,7 +51,7 @@ func TestIssuerService(t *testing.T) {
 		"foo": "123",
 	}
 
-	mappings, err := types.NewClaimsMapping(mappingStrs)
+	mappings, err := types.NewClaimsMapping(mappingStrs, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -395,4 +397,136 @@ func TestIssuerService(t *testing.T) {
 
 		testingx.RunTests(context.Background(), t, testCases, runFn)
 	})
+
+	t.Run("ListIssuers", func(t *testing.T) {
+		t.Parallel()
+
+		listTenantID := "0c6d9a4e-2f57-4c5c-9a8b-5d0a51c7e0e2"
+
+		issuers := []types.Issuer{
+			{
+				TenantID:      listTenantID,
+				ID:            "9d2c3d5e-8f0a-4c42-9a51-2b1f1f0d6c01",
+				Name:          "Charlie",
+				URI:           "https://a.list.example.com/",
+				ClaimMappings: mappings,
+			},
+			{
+				TenantID:      listTenantID,
+				ID:            "9d2c3d5e-8f0a-4c42-9a51-2b1f1f0d6c02",
+				Name:          "Alpha",
+				URI:           "https://c.list.example.com/",
+				ClaimMappings: mappings,
+			},
+			{
+				TenantID:      listTenantID,
+				ID:            "9d2c3d5e-8f0a-4c42-9a51-2b1f1f0d6c03",
+				Name:          "Bravo",
+				URI:           "https://b.list.example.com/",
+				ClaimMappings: mappings,
+			},
+		}
+
+		setupFn := func(ctx context.Context) context.Context {
+			ctx, err := beginTxContext(ctx, db)
+			if !assert.NoError(t, err) {
+				assert.FailNow(t, "setup failed")
+			}
+
+			for _, iss := range issuers {
+				_, err = issSvc.CreateIssuer(ctx, iss)
+				if !assert.NoError(t, err) {
+					assert.FailNow(t, "setup failed")
+				}
+			}
+
+			return ctx
+		}
+
+		cleanupFn := func(ctx context.Context) {
+			err := rollbackContextTx(ctx)
+			assert.NoError(t, err)
+		}
+
+		// runFn follows cursors until every page is listed, returning the names of all listed issuers.
+		runFn := func(ctx context.Context, input types.IssuerListOptions) testingx.TestResult[[]string] {
+			names := []string{}
+
+			for {
+				page, err := issSvc.ListIssuers(ctx, listTenantID, input)
+				if err != nil {
+					return testingx.TestResult[[]string]{Err: err}
+				}
+
+				for _, iss := range page.Issuers {
+					names = append(names, iss.Name)
+				}
+
+				if page.NextCursor == "" {
+					return testingx.TestResult[[]string]{Success: names}
+				}
+
+				input.Cursor = page.NextCursor
+			}
+		}
+
+		checkNames := func(exp ...string) func(context.Context, *testing.T, testingx.TestResult[[]string]) {
+			return func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, exp, res.Success)
+				}
+			}
+		}
+
+		checkInvalid := func(ctx context.Context, t *testing.T, res testingx.TestResult[[]string]) {
+			assert.ErrorIs(t, res.Err, types.ErrInvalidIssuerListOptions)
+		}
+
+		testCases := []testingx.TestCase[types.IssuerListOptions, []string]{
+			{
+				Name:      "SortName",
+				Input:     types.IssuerListOptions{Limit: 2},
+				SetupFn:   setupFn,
+				CheckFn:   checkNames("Alpha", "Bravo", "Charlie"),
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:      "SortURIDescending",
+				Input:     types.IssuerListOptions{SortBy: types.IssuerSortURI, Descending: true, Limit: 1},
+				SetupFn:   setupFn,
+				CheckFn:   checkNames("Alpha", "Bravo", "Charlie"),
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:      "FilterName",
+				Input:     types.IssuerListOptions{Name: "RAV"},
+				SetupFn:   setupFn,
+				CheckFn:   checkNames("Bravo"),
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:      "FilterURI",
+				Input:     types.IssuerListOptions{URI: "https://a.list.example.com/"},
+				SetupFn:   setupFn,
+				CheckFn:   checkNames("Charlie"),
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:      "InvalidSort",
+				Input:     types.IssuerListOptions{SortBy: "jwksuri"},
+				SetupFn:   setupFn,
+				CheckFn:   checkInvalid,
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name:      "InvalidCursor",
+				Input:     types.IssuerListOptions{Cursor: "not a cursor"},
+				SetupFn:   setupFn,
+				CheckFn:   checkInvalid,
+				CleanupFn: cleanupFn,
+			},
+		}
+
+		testingx.RunTests(context.Background(), t, testCases, runFn)
+	})
 }
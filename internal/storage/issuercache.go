@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// defaultIssuerCacheTTL is the issuer cache TTL used when none is configured.
+const defaultIssuerCacheTTL = time.Minute
+
+type issuerCacheEntry struct {
+	issuer    types.Issuer
+	expiresAt time.Time
+}
+
+// issuerCache is an in-process cache of issuers, along with their compiled claim mapping programs.
+// Entries are invalidated once an update or deletion of the issuer through this process commits, and
+// otherwise expire after the configured TTL so changes made by other replicas are eventually observed.
+//
+// Each invalidation advances the cache's generation. Issuers read from the database are only stored if
+// no invalidation happened since the read began, so a read racing with a commit cannot store the issuer
+// as it was before the change.
+type issuerCache struct {
+	mu         sync.RWMutex
+	ttl        time.Duration
+	generation uint64
+	byID       map[string]issuerCacheEntry
+	byURI      map[string]string
+}
+
+// newIssuerCache creates a new issuer cache. A zero TTL uses the default TTL, and a negative TTL
+// disables caching.
+func newIssuerCache(ttl time.Duration) *issuerCache {
+	if ttl == 0 {
+		ttl = defaultIssuerCacheTTL
+	}
+
+	return &issuerCache{
+		ttl:   ttl,
+		byID:  make(map[string]issuerCacheEntry),
+		byURI: make(map[string]string),
+	}
+}
+
+func (c *issuerCache) enabled() bool {
+	return c.ttl > 0
+}
+
+// getByID returns a copy of the cached issuer with the given ID, if one exists and has not expired.
+func (c *issuerCache) getByID(id string) (*types.Issuer, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.byID[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	iss := entry.issuer
+
+	return &iss, true
+}
+
+// getByURI returns a copy of the cached issuer with the given URI, if one exists and has not expired.
+func (c *issuerCache) getByURI(uri string) (*types.Issuer, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	id, ok := c.byURI[uri]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return c.getByID(id)
+}
+
+// version returns the cache's current generation, which must be taken before reading an issuer to be
+// stored with put.
+func (c *issuerCache) version() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generation
+}
+
+// put compiles the given issuer's claim mappings and stores it in the cache, unless the cache has been
+// invalidated since the given generation. Issuers whose mappings fail to compile are not cached.
+func (c *issuerCache) put(iss *types.Issuer, version uint64) {
+	if !c.enabled() {
+		return
+	}
+
+	programs, err := iss.ClaimMappings.Compile()
+	if err != nil {
+		return
+	}
+
+	iss.ClaimMappingPrograms = programs
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != version {
+		return
+	}
+
+	c.removeLocked(iss.ID)
+
+	c.byID[iss.ID] = issuerCacheEntry{
+		issuer:    *iss,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.byURI[iss.URI] = iss.ID
+}
+
+// invalidate removes the issuer with the given ID from the cache.
+func (c *issuerCache) invalidate(id string) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	c.removeLocked(id)
+}
+
+func (c *issuerCache) removeLocked(id string) {
+	entry, ok := c.byID[id]
+	if !ok {
+		return
+	}
+
+	if c.byURI[entry.issuer.URI] == id {
+		delete(c.byURI, entry.issuer.URI)
+	}
+
+	delete(c.byID, id)
+}
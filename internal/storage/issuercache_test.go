@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+// TestIssuerCache checks that cached issuers are invalidated, and that issuers read before an
+// invalidation are not stored after it.
+func TestIssuerCache(t *testing.T) {
+	t.Parallel()
+
+	iss := types.Issuer{
+		TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+		ID:       "e495a393-ae79-4a02-a78d-9798c7d9d252",
+		Name:     "Example",
+		URI:      "https://example.com/",
+	}
+
+	type cacheResult struct {
+		byID  bool
+		byURI bool
+	}
+
+	runFn := func(ctx context.Context, steps func(c *issuerCache)) testingx.TestResult[cacheResult] {
+		c := newIssuerCache(0)
+
+		steps(c)
+
+		_, byID := c.getByID(iss.ID)
+		_, byURI := c.getByURI(iss.URI)
+
+		return testingx.TestResult[cacheResult]{
+			Success: cacheResult{
+				byID:  byID,
+				byURI: byURI,
+			},
+		}
+	}
+
+	checkCached := func(cached bool) func(context.Context, *testing.T, testingx.TestResult[cacheResult]) {
+		return func(ctx context.Context, t *testing.T, res testingx.TestResult[cacheResult]) {
+			assert.Equal(t, cacheResult{byID: cached, byURI: cached}, res.Success)
+		}
+	}
+
+	testCases := []testingx.TestCase[func(c *issuerCache), cacheResult]{
+		{
+			Name: "Put",
+			Input: func(c *issuerCache) {
+				cached := iss
+				c.put(&cached, c.version())
+			},
+			CheckFn: checkCached(true),
+		},
+		{
+			Name: "Invalidate",
+			Input: func(c *issuerCache) {
+				cached := iss
+				c.put(&cached, c.version())
+				c.invalidate(iss.ID)
+			},
+			CheckFn: checkCached(false),
+		},
+		{
+			Name: "StalePut",
+			Input: func(c *issuerCache) {
+				version := c.version()
+
+				c.invalidate(iss.ID)
+
+				cached := iss
+				c.put(&cached, version)
+			},
+			CheckFn: checkCached(false),
+		},
+		{
+			Name: "Expired",
+			Input: func(c *issuerCache) {
+				c.ttl = time.Nanosecond
+
+				cached := iss
+				c.put(&cached, c.version())
+
+				time.Sleep(time.Millisecond)
+			},
+			CheckFn: checkCached(false),
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestIssuerServiceCacheInvalidation checks that cached issuers are invalidated when updates and
+// deletions commit, and not before.
+func TestIssuerServiceCacheInvalidation(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	svc, err := newIssuerService(Config{}, db)
+	assert.Nil(t, err)
+
+	seed := []SeedIssuer{
+		{
+			TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+			ID:       "e495a393-ae79-4a02-a78d-9798c7d9d252",
+			Name:     "Updated",
+			URI:      "https://updated.example.com/",
+		},
+		{
+			TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+			ID:       "e495a393-ae79-4a02-a78d-9798c7d9d253",
+			Name:     "Deleted",
+			URI:      "https://deleted.example.com/",
+		},
+	}
+
+	err = svc.seedDatabase(context.Background(), seed)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "setup failed")
+	}
+
+	newName := "Renamed"
+
+	t.Run("UpdateIssuer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		_, err := svc.GetIssuerByURI(ctx, seed[0].URI)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		txCtx, err := beginTxContext(ctx, db)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = svc.UpdateIssuer(txCtx, seed[0].ID, types.IssuerUpdate{Name: &newName})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// Reads before the update commits are served the committed issuer.
+		iss, err := svc.GetIssuerByURI(ctx, seed[0].URI)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Updated", iss.Name)
+		}
+
+		if !assert.NoError(t, commitContextTx(txCtx)) {
+			return
+		}
+
+		iss, err = svc.GetIssuerByURI(ctx, seed[0].URI)
+		if assert.NoError(t, err) {
+			assert.Equal(t, newName, iss.Name)
+		}
+	})
+
+	t.Run("DeleteIssuer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		_, err := svc.GetIssuerByID(ctx, seed[1].ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		txCtx, err := beginTxContext(ctx, db)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if !assert.NoError(t, svc.DeleteIssuer(txCtx, seed[1].ID)) {
+			return
+		}
+
+		_, err = svc.GetIssuerByID(ctx, seed[1].ID)
+		assert.NoError(t, err)
+
+		if !assert.NoError(t, commitContextTx(txCtx)) {
+			return
+		}
+
+		_, err = svc.GetIssuerByID(ctx, seed[1].ID)
+		assert.ErrorIs(t, err, types.ErrorIssuerNotFound)
+	})
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var jwksSnapshotCols = struct {
+	IssuerID  string
+	JWKS      string
+	FetchedAt string
+}{
+	IssuerID:  "issuer_id",
+	JWKS:      "jwks",
+	FetchedAt: "fetched_at",
+}
+
+var (
+	jwksSnapshotColumns = []string{
+		jwksSnapshotCols.IssuerID,
+		jwksSnapshotCols.JWKS,
+		jwksSnapshotCols.FetchedAt,
+	}
+	jwksSnapshotColumnsStr = strings.Join(jwksSnapshotColumns, ", ")
+)
+
+// jwksSnapshotService represents a SQL-backed JWKS snapshot service.
+type jwksSnapshotService struct {
+	db *sql.DB
+}
+
+func newJWKSSnapshotService(config Config, db *sql.DB) (*jwksSnapshotService, error) {
+	svc := &jwksSnapshotService{
+		db: db,
+	}
+
+	return svc, nil
+}
+
+// StoreJWKSSnapshot stores a JWKS snapshot, replacing the issuer's previous snapshot. This function will
+// use a transaction in the context if one exists.
+func (s *jwksSnapshotService) StoreJWKSSnapshot(ctx context.Context, snapshot types.JWKSSnapshot) error {
+	jwks, err := json.Marshal(snapshot.JWKS)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf("UPSERT INTO jwks_snapshots (%s) VALUES ($1, $2, $3)", jwksSnapshotColumnsStr)
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		_, err = tx.ExecContext(ctx, q, snapshot.IssuerID, string(jwks), snapshot.FetchedAt)
+	case ErrorMissingContextTx:
+		_, err = s.db.ExecContext(ctx, q, snapshot.IssuerID, string(jwks), snapshot.FetchedAt)
+	}
+
+	return err
+}
+
+// GetJWKSSnapshot returns the JWKS snapshot for the given issuer. This function will use a transaction in
+// the context if one exists.
+func (s *jwksSnapshotService) GetJWKSSnapshot(ctx context.Context, issuerID string) (*types.JWKSSnapshot, error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM jwks_snapshots WHERE %s = $1",
+		jwksSnapshotColumnsStr,
+		jwksSnapshotCols.IssuerID,
+	)
+
+	var row *sql.Row
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		row = tx.QueryRowContext(ctx, q, issuerID)
+	case ErrorMissingContextTx:
+		row = s.db.QueryRowContext(ctx, q, issuerID)
+	default:
+		return nil, err
+	}
+
+	var (
+		snapshot types.JWKSSnapshot
+		jwks     string
+	)
+
+	err = row.Scan(&snapshot.IssuerID, &jwks, &snapshot.FetchedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrorJWKSSnapshotNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var set jose.JSONWebKeySet
+
+	if err := json.Unmarshal([]byte(jwks), &set); err != nil {
+		return nil, err
+	}
+
+	snapshot.JWKS = &set
+
+	return &snapshot, nil
+}
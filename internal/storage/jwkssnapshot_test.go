@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func TestJWKSSnapshotService(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	issuerID := "e495a393-ae79-4a02-a78d-9798c7d9d252"
+
+	config := Config{
+		SeedData: SeedData{
+			Issuers: []SeedIssuer{
+				{
+					TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+					ID:       issuerID,
+					Name:     "Example",
+					URI:      "https://example.com/",
+					JWKSURI:  "https://example.com/.well-known/jwks.json",
+				},
+			},
+		},
+	}
+
+	issSvc, err := newIssuerService(config, db)
+	assert.Nil(t, err)
+
+	err = issSvc.seedDatabase(context.Background(), config.SeedData.Issuers)
+	assert.Nil(t, err)
+
+	svc, err := newJWKSSnapshotService(config, db)
+	assert.Nil(t, err)
+
+	fetchedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Storing a snapshot replaces the issuer's previous one.
+	for i, kid := range []string{"old", "new"} {
+		snapshot := types.JWKSSnapshot{
+			IssuerID: issuerID,
+			JWKS: &jose.JSONWebKeySet{
+				Keys: []jose.JSONWebKey{
+					{
+						Key:   []byte("secret"),
+						KeyID: kid,
+						Use:   "sig",
+					},
+				},
+			},
+			FetchedAt: fetchedAt.Add(time.Duration(i) * time.Hour),
+		}
+
+		err = svc.StoreJWKSSnapshot(context.Background(), snapshot)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+	}
+
+	testCases := []testingx.TestCase[string, *types.JWKSSnapshot]{
+		{
+			Name:  "Success",
+			Input: issuerID,
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.JWKSSnapshot]) {
+				if !assert.NoError(t, res.Err) {
+					return
+				}
+
+				assert.Equal(t, issuerID, res.Success.IssuerID)
+				assert.True(t, fetchedAt.Add(time.Hour).Equal(res.Success.FetchedAt))
+				assert.Len(t, res.Success.JWKS.Key("new"), 1)
+				assert.Empty(t, res.Success.JWKS.Key("old"))
+			},
+		},
+		{
+			Name:  "NotFound",
+			Input: "00000000-0000-0000-0000-000000000000",
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.JWKSSnapshot]) {
+				assert.ErrorIs(t, res.Err, types.ErrorJWKSSnapshotNotFound)
+			},
+		},
+	}
+
+	runFn := func(ctx context.Context, input string) testingx.TestResult[*types.JWKSSnapshot] {
+		snapshot, err := svc.GetJWKSSnapshot(ctx, input)
+
+		return testingx.TestResult[*types.JWKSSnapshot]{
+			Success: snapshot,
+			Err:     err,
+		}
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
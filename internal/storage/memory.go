@@ -12,6 +12,11 @@ import (
 type memoryEngine struct {
 	*issuerService
 	*userInfoService
+	*groupRoleMappingService
+	*pairwiseSubjectService
+	*jwksSnapshotService
+	*signingKeyService
+	*auditEventService
 	crdb testserver.TestServer
 	db   *sql.DB
 }
@@ -37,16 +42,46 @@ func newMemoryEngine(config Config) (*memoryEngine, error) {
 		return nil, err
 	}
 
-	userInfoSvc, err := newUserInfoService(config, db)
+	userInfoSvc, err := newUserInfoService(config, db, issSvc)
+	if err != nil {
+		return nil, err
+	}
+
+	groupRoleMappingSvc, err := newGroupRoleMappingService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	pairwiseSubjectSvc, err := newPairwiseSubjectService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksSnapshotSvc, err := newJWKSSnapshotService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKeySvc, err := newSigningKeyService(config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	auditEventSvc, err := newAuditEventService(config, db)
 	if err != nil {
 		return nil, err
 	}
 
 	out := &memoryEngine{
-		issuerService:   issSvc,
-		userInfoService: userInfoSvc,
-		crdb:            crdb,
-		db:              db,
+		issuerService:           issSvc,
+		userInfoService:         userInfoSvc,
+		groupRoleMappingService: groupRoleMappingSvc,
+		pairwiseSubjectService:  pairwiseSubjectSvc,
+		jwksSnapshotService:     jwksSnapshotSvc,
+		signingKeyService:       signingKeySvc,
+		auditEventService:       auditEventSvc,
+		crdb:                    crdb,
+		db:                      db,
 	}
 
 	err = out.seedDatabase(context.Background(), config.SeedData)
@@ -78,18 +113,20 @@ func (eng *memoryEngine) seedDatabase(ctx context.Context, data SeedData) error
 }
 
 func buildIssuerFromSeed(seed SeedIssuer) (types.Issuer, error) {
-	claimMappings, err := types.NewClaimsMapping(seed.ClaimMappings)
+	claimMappings, err := types.NewClaimsMapping(seed.ClaimMappings, nil)
 	if err != nil {
 		return types.Issuer{}, err
 	}
 
 	out := types.Issuer{
-		TenantID:      seed.TenantID,
-		ID:            seed.ID,
-		Name:          seed.Name,
-		URI:           seed.URI,
-		JWKSURI:       seed.JWKSURI,
-		ClaimMappings: claimMappings,
+		TenantID:              seed.TenantID,
+		ID:                    seed.ID,
+		Name:                  seed.Name,
+		URI:                   seed.URI,
+		JWKSURI:               seed.JWKSURI,
+		UserInfoEndpoint:      seed.UserInfoEndpoint,
+		IntrospectionEndpoint: seed.IntrospectionEndpoint,
+		ClaimMappings:         claimMappings,
 	}
 
 	return out, nil
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE group_role_mappings (
+    id          UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    tenant_id   UUID NOT NULL,
+    issuer_id   UUID NOT NULL REFERENCES issuers(id) ON DELETE CASCADE,
+    group_value STRING NOT NULL,
+    role        STRING NOT NULL,
+    UNIQUE (issuer_id, group_value, role)
+);
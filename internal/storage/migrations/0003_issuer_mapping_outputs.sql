@@ -0,0 +1,2 @@
+-- +goose Up
+ALTER TABLE issuers ADD COLUMN mapping_outputs STRING;
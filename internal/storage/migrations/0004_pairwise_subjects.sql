@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE TABLE pairwise_subjects (
+    id           UUID PRIMARY KEY NOT NULL,
+    user_info_id UUID NOT NULL REFERENCES user_info(id) ON DELETE CASCADE,
+    sector       STRING NOT NULL,
+    UNIQUE (user_info_id, sector)
+);
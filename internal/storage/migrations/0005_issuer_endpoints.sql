@@ -0,0 +1,3 @@
+-- +goose Up
+ALTER TABLE issuers ADD COLUMN userinfo_endpoint STRING NOT NULL DEFAULT '';
+ALTER TABLE issuers ADD COLUMN introspection_endpoint STRING NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- +goose Up
+CREATE TABLE jwks_snapshots (
+    issuer_id  UUID PRIMARY KEY NOT NULL REFERENCES issuers(id) ON DELETE CASCADE,
+    jwks       STRING NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE signing_keys (
+    id           STRING PRIMARY KEY NOT NULL,
+    algorithm    STRING NOT NULL,
+    state        STRING NOT NULL,
+    public_key   STRING NOT NULL,
+    private_key  BYTES NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    activated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+-- +goose Up
+CREATE INDEX issuers_tenant_name_idx ON issuers (tenant_id, name, id);
+CREATE INDEX issuers_tenant_uri_idx ON issuers (tenant_id, uri, id);
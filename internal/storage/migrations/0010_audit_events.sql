@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE audit_events (
+    id            UUID PRIMARY KEY NOT NULL,
+    tenant_id     UUID NOT NULL,
+    actor         STRING NOT NULL,
+    action        STRING NOT NULL,
+    resource_type STRING NOT NULL,
+    resource_id   STRING NOT NULL,
+    request_id    STRING NOT NULL,
+    before_state  JSONB,
+    after_state   JSONB,
+    created_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX audit_events_tenant_created_idx ON audit_events (tenant_id, created_at DESC, id DESC);
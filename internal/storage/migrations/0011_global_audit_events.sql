@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE audit_events ALTER COLUMN tenant_id DROP NOT NULL;
+
+CREATE INDEX audit_events_global_created_idx ON audit_events (created_at DESC, id DESC) WHERE tenant_id IS NULL;
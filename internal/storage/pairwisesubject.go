@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var pairwiseSubjectCols = struct {
+	ID         string
+	UserInfoID string
+	Sector     string
+}{
+	ID:         "id",
+	UserInfoID: "user_info_id",
+	Sector:     "sector",
+}
+
+var (
+	pairwiseSubjectColumns = []string{
+		pairwiseSubjectCols.ID,
+		pairwiseSubjectCols.UserInfoID,
+		pairwiseSubjectCols.Sector,
+	}
+	pairwiseSubjectColumnsStr = strings.Join(pairwiseSubjectColumns, ", ")
+)
+
+// pairwiseSubjectService represents a SQL-backed pairwise subject service.
+type pairwiseSubjectService struct {
+	db *sql.DB
+}
+
+func newPairwiseSubjectService(config Config, db *sql.DB) (*pairwiseSubjectService, error) {
+	svc := &pairwiseSubjectService{
+		db: db,
+	}
+
+	return svc, nil
+}
+
+// StorePairwiseSubject stores a pairwise subject identifier. Pairwise subject identifiers are derived
+// deterministically, so storing an identifier that already exists does nothing.
+func (s *pairwiseSubjectService) StorePairwiseSubject(ctx context.Context, subject types.PairwiseSubject) (*types.PairwiseSubject, error) {
+	tx, err := getContextTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(
+		"INSERT INTO pairwise_subjects (%s) VALUES ($1, $2, $3) ON CONFLICT (%s) DO NOTHING",
+		pairwiseSubjectColumnsStr,
+		pairwiseSubjectCols.ID,
+	)
+
+	_, err = tx.ExecContext(ctx, q, subject.ID, subject.UserInfoID, subject.Sector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subject, nil
+}
+
+// LookupPairwiseSubject returns the pairwise subject with the given identifier. This function will use
+// a transaction in the context if one exists.
+func (s *pairwiseSubjectService) LookupPairwiseSubject(ctx context.Context, id string) (*types.PairwiseSubject, error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM pairwise_subjects WHERE %s = $1",
+		pairwiseSubjectColumnsStr,
+		pairwiseSubjectCols.ID,
+	)
+
+	var row *sql.Row
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		row = tx.QueryRowContext(ctx, q, id)
+	case ErrorMissingContextTx:
+		row = s.db.QueryRowContext(ctx, q, id)
+	default:
+		return nil, err
+	}
+
+	var subject types.PairwiseSubject
+
+	err = row.Scan(&subject.ID, &subject.UserInfoID, &subject.Sector)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrorPairwiseSubjectNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subject, nil
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func TestPairwiseSubjectService(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	config := Config{
+		SeedData: SeedData{
+			Issuers: []SeedIssuer{
+				{
+					TenantID: "56a95c1b-33f8-4def-8b6d-ca9fe6976170",
+					ID:       "e495a393-ae79-4a02-a78d-9798c7d9d252",
+					Name:     "Example",
+					URI:      "https://example.com/",
+					JWKSURI:  "https://example.com/.well-known/jwks.json",
+				},
+			},
+		},
+	}
+
+	issSvc, err := newIssuerService(config, db)
+	assert.Nil(t, err)
+
+	err = issSvc.seedDatabase(context.Background(), config.SeedData.Issuers)
+	assert.Nil(t, err)
+
+	userInfoSvc, err := newUserInfoService(config, db, issSvc)
+	assert.Nil(t, err)
+
+	svc, err := newPairwiseSubjectService(config, db)
+	assert.Nil(t, err)
+
+	ctx, err := beginTxContext(context.Background(), db)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "setup failed")
+	}
+
+	userInfo, err := userInfoSvc.StoreUserInfo(ctx, types.UserInfo{
+		Issuer:  "https://example.com/",
+		Subject: "sub0|malikadmin",
+	})
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "setup failed")
+	}
+
+	subject := types.PairwiseSubject{
+		ID:         "0a3e4c39-7b8f-5e8e-9c7a-2f44b0f0c111",
+		UserInfoID: userInfo.ID.String(),
+		Sector:     "my-client",
+	}
+
+	// Storing is idempotent, since pairwise subject identifiers are derived deterministically.
+	for i := 0; i < 2; i++ {
+		_, err = svc.StorePairwiseSubject(ctx, subject)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+	}
+
+	err = commitContextTx(ctx)
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "setup failed")
+	}
+
+	testCases := []testingx.TestCase[string, *types.PairwiseSubject]{
+		{
+			Name:  "Success",
+			Input: subject.ID,
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.PairwiseSubject]) {
+				if assert.NoError(t, res.Err) {
+					assert.Equal(t, subject, *res.Success)
+				}
+			},
+		},
+		{
+			Name:  "NotFound",
+			Input: "00000000-0000-0000-0000-000000000000",
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.PairwiseSubject]) {
+				assert.ErrorIs(t, res.Err, types.ErrorPairwiseSubjectNotFound)
+			},
+		},
+	}
+
+	runFn := func(ctx context.Context, input string) testingx.TestResult[*types.PairwiseSubject] {
+		subject, err := svc.LookupPairwiseSubject(ctx, input)
+
+		return testingx.TestResult[*types.PairwiseSubject]{
+			Success: subject,
+			Err:     err,
+		}
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -46,6 +47,17 @@ func issuerUpdateToColBindings(update types.IssuerUpdate) ([]colBinding, error)
 	bindings = bindIfNotNil(bindings, issuerCols.Name, update.Name)
 	bindings = bindIfNotNil(bindings, issuerCols.URI, update.URI)
 	bindings = bindIfNotNil(bindings, issuerCols.JWKSURI, update.JWKSURI)
+	bindings = bindIfNotNil(bindings, issuerCols.UserInfoEndpoint, update.UserInfoEndpoint)
+	bindings = bindIfNotNil(bindings, issuerCols.IntrospectionEndpoint, update.IntrospectionEndpoint)
+
+	if update.JWKS != nil {
+		jwks, err := marshalIssuerJWKS(update.JWKS)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = bindIfNotNil(bindings, issuerCols.JWKS, &jwks)
+	}
 
 	if update.ClaimMappings != nil {
 		mappingRepr, err := update.ClaimMappings.MarshalJSON()
@@ -58,6 +70,17 @@ func issuerUpdateToColBindings(update types.IssuerUpdate) ([]colBinding, error)
 		bindings = bindIfNotNil(bindings, issuerCols.Mappings, &mappingStr)
 	}
 
+	if update.ClaimMappingOutputs != nil {
+		outputsRepr, err := json.Marshal(update.ClaimMappingOutputs)
+		if err != nil {
+			return nil, err
+		}
+
+		outputsStr := string(outputsRepr)
+
+		bindings = bindIfNotNil(bindings, issuerCols.MappingOutputs, &outputsStr)
+	}
+
 	return bindings, nil
 }
 
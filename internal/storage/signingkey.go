@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+var signingKeyCols = struct {
+	ID          string
+	Algorithm   string
+	State       string
+	PublicKey   string
+	PrivateKey  string
+	CreatedAt   string
+	ActivatedAt string
+}{
+	ID:          "id",
+	Algorithm:   "algorithm",
+	State:       "state",
+	PublicKey:   "public_key",
+	PrivateKey:  "private_key",
+	CreatedAt:   "created_at",
+	ActivatedAt: "activated_at",
+}
+
+var (
+	signingKeyColumns = []string{
+		signingKeyCols.ID,
+		signingKeyCols.Algorithm,
+		signingKeyCols.State,
+		signingKeyCols.PublicKey,
+		signingKeyCols.PrivateKey,
+		signingKeyCols.CreatedAt,
+		signingKeyCols.ActivatedAt,
+	}
+	signingKeyColumnsStr = strings.Join(signingKeyColumns, ", ")
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// signingKeyService represents a SQL-backed signing key service.
+type signingKeyService struct {
+	db *sql.DB
+}
+
+func newSigningKeyService(config Config, db *sql.DB) (*signingKeyService, error) {
+	svc := &signingKeyService{
+		db: db,
+	}
+
+	return svc, nil
+}
+
+// CreateSigningKey creates a signing key. This function will use a transaction in the context if one
+// exists.
+func (s *signingKeyService) CreateSigningKey(ctx context.Context, key types.SigningKey) (*types.SigningKey, error) {
+	publicKey, err := json.Marshal(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf("INSERT INTO signing_keys (%s) VALUES ($1, $2, $3, $4, $5, $6, $7)", signingKeyColumnsStr)
+
+	args := []any{
+		key.ID,
+		key.Algorithm,
+		key.State,
+		string(publicKey),
+		key.EncryptedPrivateKey,
+		key.CreatedAt,
+		nullTime(key.ActivatedAt),
+	}
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		_, err = tx.ExecContext(ctx, q, args...)
+	case ErrorMissingContextTx:
+		_, err = s.db.ExecContext(ctx, q, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// GetSigningKeyByID returns the signing key with the given ID. This function will use a transaction in
+// the context if one exists.
+func (s *signingKeyService) GetSigningKeyByID(ctx context.Context, id string) (*types.SigningKey, error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM signing_keys WHERE %s = $1",
+		signingKeyColumnsStr,
+		signingKeyCols.ID,
+	)
+
+	var row *sql.Row
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		row = tx.QueryRowContext(ctx, q, id)
+	case ErrorMissingContextTx:
+		row = s.db.QueryRowContext(ctx, q, id)
+	default:
+		return nil, err
+	}
+
+	key, err := scanSigningKey(row)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrorSigningKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateSigningKey updates the state of the signing key with the given ID. This function will use a
+// transaction in the context if one exists.
+func (s *signingKeyService) UpdateSigningKey(ctx context.Context, id string, update types.SigningKeyUpdate) (*types.SigningKey, error) {
+	var (
+		sets []string
+		args []any
+	)
+
+	if update.State != nil {
+		args = append(args, *update.State)
+		sets = append(sets, fmt.Sprintf("%s = $%d", signingKeyCols.State, len(args)))
+	}
+
+	if update.ActivatedAt != nil {
+		args = append(args, nullTime(*update.ActivatedAt))
+		sets = append(sets, fmt.Sprintf("%s = $%d", signingKeyCols.ActivatedAt, len(args)))
+	}
+
+	if len(sets) == 0 {
+		return s.GetSigningKeyByID(ctx, id)
+	}
+
+	args = append(args, id)
+
+	q := fmt.Sprintf(
+		"UPDATE signing_keys SET %s WHERE %s = $%d RETURNING %s",
+		strings.Join(sets, ", "),
+		signingKeyCols.ID,
+		len(args),
+		signingKeyColumnsStr,
+	)
+
+	var row *sql.Row
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		row = tx.QueryRowContext(ctx, q, args...)
+	case ErrorMissingContextTx:
+		row = s.db.QueryRowContext(ctx, q, args...)
+	default:
+		return nil, err
+	}
+
+	key, err := scanSigningKey(row)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrorSigningKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ListSigningKeys returns all signing keys, oldest first. This function will use a transaction in the
+// context if one exists.
+func (s *signingKeyService) ListSigningKeys(ctx context.Context) ([]types.SigningKey, error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM signing_keys ORDER BY %s, %s",
+		signingKeyColumnsStr,
+		signingKeyCols.CreatedAt,
+		signingKeyCols.ID,
+	)
+
+	var rows *sql.Rows
+
+	tx, err := getContextTx(ctx)
+
+	switch err {
+	case nil:
+		rows, err = tx.QueryContext(ctx, q)
+	case ErrorMissingContextTx:
+		rows, err = s.db.QueryContext(ctx, q)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	out := []types.SigningKey{}
+
+	for rows.Next() {
+		key, err := scanSigningKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func scanSigningKey(row rowScanner) (*types.SigningKey, error) {
+	var (
+		key         types.SigningKey
+		publicKey   string
+		activatedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&key.Algorithm,
+		&key.State,
+		&publicKey,
+		&key.EncryptedPrivateKey,
+		&key.CreatedAt,
+		&activatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwk jose.JSONWebKey
+
+	if err := json.Unmarshal([]byte(publicKey), &jwk); err != nil {
+		return nil, err
+	}
+
+	key.PublicKey = &jwk
+
+	if activatedAt.Valid {
+		key.ActivatedAt = activatedAt.Time
+	}
+
+	return &key, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  t,
+		Valid: !t.IsZero(),
+	}
+}
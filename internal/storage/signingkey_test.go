@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/testserver"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/testingx"
+	"go.infratographer.com/identity-api/internal/types"
+)
+
+func TestSigningKeyService(t *testing.T) {
+	t.Parallel()
+
+	db, shutdown := testserver.NewDBForTest(t)
+
+	err := runMigrations(db)
+	if err != nil {
+		shutdown()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		shutdown()
+	})
+
+	svc, err := newSigningKeyService(Config{}, db)
+	assert.Nil(t, err)
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, kid := range []string{"first", "second"} {
+		key := types.SigningKey{
+			ID:        kid,
+			Algorithm: string(jose.HS256),
+			State:     "pending",
+			PublicKey: &jose.JSONWebKey{
+				Key:   []byte("public"),
+				KeyID: kid,
+				Use:   "sig",
+			},
+			EncryptedPrivateKey: []byte("encrypted"),
+			CreatedAt:           createdAt.Add(time.Duration(i) * time.Hour),
+		}
+
+		_, err = svc.CreateSigningKey(context.Background(), key)
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "setup failed")
+		}
+	}
+
+	// Keys are listed oldest first.
+	keys, err := svc.ListSigningKeys(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, keys, 2) {
+		assert.Equal(t, "first", keys[0].ID)
+		assert.Equal(t, "second", keys[1].ID)
+	}
+
+	activatedAt := createdAt.Add(24 * time.Hour)
+	active := "active"
+
+	type input struct {
+		id     string
+		update types.SigningKeyUpdate
+	}
+
+	testCases := []testingx.TestCase[input, *types.SigningKey]{
+		{
+			Name: "Promote",
+			Input: input{
+				id: "second",
+				update: types.SigningKeyUpdate{
+					State:       &active,
+					ActivatedAt: &activatedAt,
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.SigningKey]) {
+				if !assert.NoError(t, res.Err) {
+					return
+				}
+
+				assert.Equal(t, "active", res.Success.State)
+				assert.True(t, activatedAt.Equal(res.Success.ActivatedAt))
+				assert.Equal(t, []byte("encrypted"), res.Success.EncryptedPrivateKey)
+				assert.Equal(t, "second", res.Success.PublicKey.KeyID)
+			},
+		},
+		{
+			Name: "NoChanges",
+			Input: input{
+				id: "first",
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.SigningKey]) {
+				if !assert.NoError(t, res.Err) {
+					return
+				}
+
+				assert.Equal(t, "pending", res.Success.State)
+				assert.True(t, res.Success.ActivatedAt.IsZero())
+			},
+		},
+		{
+			Name: "NotFound",
+			Input: input{
+				id: "missing",
+				update: types.SigningKeyUpdate{
+					State: &active,
+				},
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[*types.SigningKey]) {
+				assert.ErrorIs(t, res.Err, types.ErrorSigningKeyNotFound)
+			},
+		},
+	}
+
+	runFn := func(ctx context.Context, in input) testingx.TestResult[*types.SigningKey] {
+		key, err := svc.UpdateSigningKey(ctx, in.id, in.update)
+
+		return testingx.TestResult[*types.SigningKey]{
+			Success: key,
+			Err:     err,
+		}
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
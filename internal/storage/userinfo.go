@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"go.infratographer.com/identity-api/internal/discovery"
 	"go.infratographer.com/identity-api/internal/types"
 )
 
@@ -31,15 +32,19 @@ var userInfoCols = struct {
 
 type userInfoService struct {
 	db         *sql.DB
+	issuers    *issuerService
 	httpClient *http.Client
+	discovery  *discovery.Client
 }
 
 type userInfoServiceOpt func(*userInfoService)
 
-func newUserInfoService(config Config, db *sql.DB, opts ...userInfoServiceOpt) (*userInfoService, error) {
+func newUserInfoService(config Config, db *sql.DB, issuers *issuerService, opts ...userInfoServiceOpt) (*userInfoService, error) {
 	s := &userInfoService{
 		db:         db,
+		issuers:    issuers,
 		httpClient: http.DefaultClient,
+		discovery:  discovery.DefaultClient,
 	}
 
 	for _, opt := range opts {
@@ -50,10 +55,11 @@ func newUserInfoService(config Config, db *sql.DB, opts ...userInfoServiceOpt) (
 }
 
 // WithHTTPClient allows configuring the HTTP client used by
-// userInfoService to call out to userinfo endpoints.
+// userInfoService to call out to userinfo and discovery endpoints.
 func WithHTTPClient(client *http.Client) func(svc *userInfoService) {
 	return func(svc *userInfoService) {
 		svc.httpClient = client
+		svc.discovery = discovery.NewClient(client, discovery.DefaultCacheTTL)
 	}
 }
 
@@ -165,18 +171,8 @@ func (s userInfoService) StoreUserInfo(ctx context.Context, userInfo types.UserI
 		return nil, err
 	}
 
-	row := tx.QueryRowContext(ctx, `
-        SELECT id FROM issuers WHERE uri = $1
-        `, userInfo.Issuer)
-
-	var issuerID string
-
-	err = row.Scan(&issuerID)
-	switch err {
-	case nil:
-	case sql.ErrNoRows:
-		return nil, types.ErrorIssuerNotFound
-	default:
+	issuer, err := s.issuers.GetIssuerByURI(ctx, userInfo.Issuer)
+	if err != nil {
 		return nil, err
 	}
 
@@ -197,8 +193,8 @@ func (s userInfoService) StoreUserInfo(ctx context.Context, userInfo types.UserI
 		userInfoCols.IssuerID,
 	)
 
-	row = tx.QueryRowContext(ctx, q,
-		userInfo.Name, userInfo.Email, userInfo.Subject, issuerID,
+	row := tx.QueryRowContext(ctx, q,
+		userInfo.Name, userInfo.Email, userInfo.Subject, issuer.ID,
 	)
 
 	var userID string
@@ -216,7 +212,7 @@ func (s userInfoService) StoreUserInfo(ctx context.Context, userInfo types.UserI
 // FetchUserInfoFromIssuer uses the subject access token to retrieve
 // information from the OIDC /userinfo endpoint.
 func (s userInfoService) FetchUserInfoFromIssuer(ctx context.Context, iss, rawToken string) (*types.UserInfo, error) {
-	endpoint, err := url.JoinPath(iss, "userinfo")
+	endpoint, err := s.userInfoEndpoint(ctx, iss)
 	if err != nil {
 		return nil, err
 	}
@@ -256,3 +252,32 @@ func (s userInfoService) FetchUserInfoFromIssuer(ctx context.Context, iss, rawTo
 
 	return &ui, nil
 }
+
+// userInfoEndpoint returns the UserInfo endpoint configured for the issuer, or else the one in its
+// discovery metadata. Issuers that publish no metadata, or metadata without a UserInfo endpoint,
+// are assumed to serve UserInfo at "userinfo" relative to the issuer URI. Any other discovery
+// failure is returned.
+func (s userInfoService) userInfoEndpoint(ctx context.Context, iss string) (string, error) {
+	issuer, err := s.issuers.GetIssuerByURI(ctx, iss)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, types.ErrorIssuerNotFound):
+		issuer = &types.Issuer{
+			URI: iss,
+		}
+	default:
+		return "", err
+	}
+
+	endpoint, err := s.discovery.UserInfoEndpoint(ctx, issuer)
+
+	switch {
+	case err == nil:
+		return endpoint, nil
+	case errors.Is(err, discovery.ErrEndpointNotFound), errors.Is(err, discovery.ErrMetadataNotFound):
+		return url.JoinPath(iss, "userinfo")
+	default:
+		return "", err
+	}
+}
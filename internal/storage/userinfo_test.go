@@ -6,12 +6,14 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach-go/v2/testserver"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"go.infratographer.com/identity-api/internal/discovery"
 	"go.infratographer.com/identity-api/internal/testingx"
 	"go.infratographer.com/identity-api/internal/types"
 )
@@ -65,7 +67,7 @@ func TestUserInfoStore(t *testing.T) {
 	err = issSvc.seedDatabase(context.Background(), config.SeedData.Issuers)
 	assert.Nil(t, err)
 
-	svc, err := newUserInfoService(config, db, WithHTTPClient(httpClient))
+	svc, err := newUserInfoService(config, db, issSvc, WithHTTPClient(httpClient))
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -206,9 +208,10 @@ func TestUserInfoStore(t *testing.T) {
 		t.Parallel()
 
 		type fetchInput struct {
-			issuer   string
-			token    string
-			respBody *string
+			issuer        string
+			token         string
+			respBody      *string
+			discoveryBody *string
 		}
 
 		type fetchResult struct {
@@ -226,6 +229,25 @@ func TestUserInfoStore(t *testing.T) {
 
 		nullResp := `{"name": null, "email": null, "sub": null}`
 
+		discoveryResp := `
+                  {
+                    "issuer": "https://discovered.com",
+                    "jwks_uri": "https://discovered.com/keys",
+                    "userinfo_endpoint": "https://discovered.com/oidc/userinfo"
+                  }`
+
+		discoveryNoUserInfoResp := `
+                  {
+                    "issuer": "https://discovered.com",
+                    "jwks_uri": "https://discovered.com/keys"
+                  }`
+
+		mismatchedDiscoveryResp := `
+                  {
+                    "issuer": "https://evil.biz",
+                    "userinfo_endpoint": "https://evil.biz/userinfo"
+                  }`
+
 		cases := []testingx.TestCase[fetchInput, fetchResult]{
 			{
 				Name:    "Success",
@@ -239,6 +261,51 @@ func TestUserInfoStore(t *testing.T) {
 				},
 				CleanupFn: cleanupFn,
 			},
+			{
+				Name: "DiscoveredEndpoint",
+				Input: fetchInput{
+					issuer:        "https://discovered.com",
+					token:         "supersecrettoken",
+					respBody:      &exampleResp,
+					discoveryBody: &discoveryResp,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[fetchResult]) {
+					assert.NoError(t, res.Err)
+					assert.Equal(t, "https://discovered.com/oidc/userinfo", res.Success.tr.req.URL.String())
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "DiscoveredWithoutEndpoint",
+				Input: fetchInput{
+					issuer:        "https://discovered.com",
+					token:         "supersecrettoken",
+					respBody:      &exampleResp,
+					discoveryBody: &discoveryNoUserInfoResp,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[fetchResult]) {
+					assert.NoError(t, res.Err)
+					assert.Equal(t, "https://discovered.com/userinfo", res.Success.tr.req.URL.String())
+				},
+				CleanupFn: cleanupFn,
+			},
+			{
+				Name: "DiscoveryError",
+				Input: fetchInput{
+					issuer:        "https://discovered.com",
+					token:         "supersecrettoken",
+					respBody:      &exampleResp,
+					discoveryBody: &mismatchedDiscoveryResp,
+				},
+				SetupFn: setupFn,
+				CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[fetchResult]) {
+					assert.ErrorIs(t, res.Err, discovery.ErrIssuerMismatch)
+					assert.Nil(t, res.Success.tr.req)
+				},
+				CleanupFn: cleanupFn,
+			},
 			{
 				Name:    "BadIssuer",
 				Input:   fetchInput{issuer: "://", token: "supersecrettoken"},
@@ -323,9 +390,9 @@ func TestUserInfoStore(t *testing.T) {
 		}
 
 		runFn := func(ctx context.Context, input fetchInput) testingx.TestResult[fetchResult] {
-			tr := recordingTransport{body: input.respBody}
+			tr := recordingTransport{body: input.respBody, discovery: input.discoveryBody}
 			client := http.Client{Transport: &tr}
-			svc, err := newUserInfoService(config, db, WithHTTPClient(&client))
+			svc, err := newUserInfoService(config, db, issSvc, WithHTTPClient(&client))
 			if !assert.NoError(t, err) {
 				assert.FailNow(t, "failed to create new fake transport: %v", err)
 			}
@@ -442,11 +509,30 @@ func TestUserInfoStore(t *testing.T) {
 }
 
 type recordingTransport struct {
-	req  *http.Request
-	body *string
+	req       *http.Request
+	body      *string
+	discovery *string
 }
 
 func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Discovery requests are answered with the discovery body, or a 404 if there is none,
+	// and are not recorded.
+	if strings.HasSuffix(req.URL.Path, discovery.WellKnownPath) {
+		if rt.discovery == nil {
+			return &http.Response{
+				Status:     http.StatusText(http.StatusNotFound),
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}
+
+		return &http.Response{
+			Status:     http.StatusText(http.StatusOK),
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(*rt.discovery)),
+		}, nil
+	}
+
 	rt.req = req
 
 	if rt.body != nil {
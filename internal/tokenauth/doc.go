@@ -0,0 +1,2 @@
+// Package tokenauth verifies bearer tokens issued by identity-api itself.
+package tokenauth
@@ -0,0 +1,108 @@
+package tokenauth
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.hollow.sh/toolbox/ginauth"
+	"go.hollow.sh/toolbox/ginjwt"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+// Verifier verifies bearer tokens signed by identity-api's current signing keys.
+type Verifier struct {
+	cfg        fositex.OAuth2Configurator
+	authConfig ginjwt.AuthConfig
+	mw         atomic.Pointer[middleware]
+}
+
+// middleware represents the middleware verifying tokens signed by the keys of a signing JWKS.
+type middleware struct {
+	signingJWKS *jose.JSONWebKeySet
+	mw          *ginauth.MultiTokenMiddleware
+}
+
+// NewVerifier creates a Verifier for tokens issued by the configured issuer for the given audience.
+// Scopes are read from the given claim.
+func NewVerifier(cfg fositex.OAuth2Configurator, audience, scopesClaim string) (*Verifier, error) {
+	ctx := context.Background()
+
+	out := &Verifier{
+		cfg: cfg,
+		authConfig: ginjwt.AuthConfig{
+			Enabled:                true,
+			Audience:               audience,
+			Issuer:                 cfg.GetAccessTokenIssuer(ctx),
+			RolesClaim:             scopesClaim,
+			RoleValidationStrategy: "all",
+		},
+	}
+
+	mw, err := out.newMiddleware(cfg.GetSigningJWKS(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	out.mw.Store(mw)
+
+	return out, nil
+}
+
+func (v *Verifier) newMiddleware(signingJWKS *jose.JSONWebKeySet) (*middleware, error) {
+	set := jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{},
+	}
+
+	for _, key := range signingJWKS.Keys {
+		if public := key.Public(); public.Valid() {
+			set.Keys = append(set.Keys, public)
+		}
+	}
+
+	authConfig := v.authConfig
+	authConfig.JWKS = set
+
+	mw, err := ginjwt.NewMultiTokenMiddlewareFromConfigs(authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &middleware{
+		signingJWKS: signingJWKS,
+		mw:          mw,
+	}
+
+	return out, nil
+}
+
+// middleware returns the middleware for the current signing keys. When the signing JWKS has been
+// replaced, e.g. by reloading the signing keys, the middleware is rebuilt from the new keys.
+func (v *Verifier) middleware(ctx context.Context) *middleware {
+	mw := v.mw.Load()
+
+	if signingJWKS := v.cfg.GetSigningJWKS(ctx); signingJWKS != mw.signingJWKS {
+		// The middleware only fails to build if the auth config is invalid, which does not change
+		// between key sets, so the previous middleware is kept on error.
+		if next, err := v.newMiddleware(signingJWKS); err == nil {
+			v.mw.CompareAndSwap(mw, next)
+			mw = next
+		}
+	}
+
+	return mw
+}
+
+// AuthRequired returns a handler which aborts requests without a valid token carrying the given scopes.
+func (v *Verifier) AuthRequired(scopes []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		v.middleware(ctx.Request.Context()).mw.AuthRequired(scopes)(ctx)
+	}
+}
+
+// Verify verifies the request's token, returning its claims. Scopes are not checked.
+func (v *Verifier) Verify(ctx *gin.Context) (ginauth.ClaimMetadata, error) {
+	return v.middleware(ctx.Request.Context()).mw.VerifyTokenWithScopes(ctx, nil)
+}
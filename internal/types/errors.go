@@ -16,4 +16,27 @@ var (
 	// ErrInvalidUserInfo represents an error condition where the
 	// UserInfo provided fails validation prior to storage.
 	ErrInvalidUserInfo = errors.New("failed to store user info")
+
+	// ErrorGroupRoleMappingNotFound represents an error condition where a group to role mapping was not found.
+	ErrorGroupRoleMappingNotFound = errors.New("group role mapping not found")
+
+	// ErrInvalidJWKS represents an error condition where an issuer's inline JWKS fails validation.
+	ErrInvalidJWKS = errors.New("invalid JWKS")
+
+	// ErrorPairwiseSubjectNotFound represents an error condition where a pairwise subject identifier was not found.
+	ErrorPairwiseSubjectNotFound = errors.New("pairwise subject not found")
+
+	// ErrorJWKSSnapshotNotFound represents an error condition where a JWKS snapshot was not found.
+	ErrorJWKSSnapshotNotFound = errors.New("JWKS snapshot not found")
+
+	// ErrorSigningKeyNotFound represents an error condition where a signing key was not found.
+	ErrorSigningKeyNotFound = errors.New("signing key not found")
+
+	// ErrInvalidIssuerListOptions represents an error condition where the options for listing issuers,
+	// such as the sort field or cursor, are not valid.
+	ErrInvalidIssuerListOptions = errors.New("invalid issuer list options")
+
+	// ErrInvalidAuditEventListOptions represents an error condition where the options for listing audit
+	// events, such as the cursor, are not valid.
+	ErrInvalidAuditEventListOptions = errors.New("invalid audit event list options")
 )
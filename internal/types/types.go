@@ -4,11 +4,14 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/uuid"
 	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 	"google.golang.org/protobuf/encoding/prototext"
+	"gopkg.in/square/go-jose.v2"
 
 	"go.infratographer.com/identity-api/internal/celutils"
 	v1 "go.infratographer.com/identity-api/pkg/api/v1"
@@ -24,10 +27,25 @@ type Issuer struct {
 	Name string
 	// URI represents the issuer URI as found in the "iss" claim of a JWT.
 	URI string
-	// JWKSURI represents the URI where the issuer's JWKS lives. Must be accessible by identity-api.
+	// JWKSURI represents the URI where the issuer's JWKS lives. Must be accessible by identity-api. If empty,
+	// the jwks_uri from the issuer's OIDC discovery metadata is used.
 	JWKSURI string
+	// JWKS represents an inline JWKS for issuers whose JWKS cannot be fetched. If set, it is used in place
+	// of JWKSURI.
+	JWKS *jose.JSONWebKeySet
+	// UserInfoEndpoint represents the issuer's UserInfo endpoint. If empty, the userinfo_endpoint from the
+	// issuer's OIDC discovery metadata is used.
+	UserInfoEndpoint string
+	// IntrospectionEndpoint represents the issuer's token introspection endpoint. If empty, the
+	// introspection_endpoint from the issuer's OIDC discovery metadata is used.
+	IntrospectionEndpoint string
 	// ClaimMappings represents a map of claims to a CEL expression that will be evaluated
 	ClaimMappings ClaimsMapping
+	// ClaimMappingOutputs represents the declared outputs of claim mappings, keyed by claim.
+	ClaimMappingOutputs ClaimMappingOutputs
+	// ClaimMappingPrograms represents the compiled programs for ClaimMappings. It may be nil, in which case
+	// the programs must be compiled from ClaimMappings before evaluation.
+	ClaimMappingPrograms ClaimMappingPrograms
 }
 
 // ToV1Issuer converts an issuer to an API issuer.
@@ -41,19 +59,142 @@ func (i Issuer) ToV1Issuer() (v1.Issuer, error) {
 		ID:            uuid.MustParse(i.ID),
 		Name:          i.Name,
 		URI:           i.URI,
-		JWKSURI:       i.JWKSURI,
 		ClaimMappings: claimsMappingRepr,
 	}
 
+	if i.JWKSURI != "" {
+		jwksURI := i.JWKSURI
+		out.JWKSURI = &jwksURI
+	}
+
+	if i.JWKS != nil {
+		jwks, err := IssuerJWKSToV1(i.JWKS)
+		if err != nil {
+			return v1.Issuer{}, err
+		}
+
+		out.JWKS = &jwks
+	}
+
+	if i.UserInfoEndpoint != "" {
+		userInfoEndpoint := i.UserInfoEndpoint
+		out.UserInfoEndpoint = &userInfoEndpoint
+	}
+
+	if i.IntrospectionEndpoint != "" {
+		introspectionEndpoint := i.IntrospectionEndpoint
+		out.IntrospectionEndpoint = &introspectionEndpoint
+	}
+
+	if len(i.ClaimMappingOutputs) > 0 {
+		outputs := i.ClaimMappingOutputs.ToV1ClaimMappingOutputs()
+		out.ClaimMappingOutputs = &outputs
+	}
+
 	return out, nil
 }
 
 // IssuerUpdate represents an update operation on an issuer.
 type IssuerUpdate struct {
-	Name          *string
-	URI           *string
-	JWKSURI       *string
-	ClaimMappings ClaimsMapping
+	Name                  *string
+	URI                   *string
+	JWKSURI               *string
+	UserInfoEndpoint      *string
+	IntrospectionEndpoint *string
+	// JWKS replaces the issuer's inline JWKS. A JWKS with no keys removes it.
+	JWKS                *jose.JSONWebKeySet
+	ClaimMappings       ClaimsMapping
+	ClaimMappingOutputs ClaimMappingOutputs
+}
+
+// NewIssuerJWKS creates an issuer's inline JWKS from its API representation. Only public signing keys
+// with key IDs are accepted, since tokens are matched to keys by their "kid" header.
+func NewIssuerJWKS(in map[string]interface{}) (*jose.JSONWebKeySet, error) {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks jose.JSONWebKeySet
+
+	if err := json.Unmarshal(raw, &jwks); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJWKS, err)
+	}
+
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrInvalidJWKS)
+	}
+
+	for _, key := range jwks.Keys {
+		switch {
+		case key.KeyID == "":
+			return nil, fmt.Errorf("%w: key without a key ID", ErrInvalidJWKS)
+		case !key.IsPublic():
+			return nil, fmt.Errorf("%w: key '%s' is not a public key", ErrInvalidJWKS, key.KeyID)
+		case key.Use != "sig":
+			return nil, fmt.Errorf("%w: key '%s' is not a signing key", ErrInvalidJWKS, key.KeyID)
+		}
+	}
+
+	return &jwks, nil
+}
+
+// IssuerJWKSToV1 converts an issuer's inline JWKS to its API representation.
+func IssuerJWKSToV1(jwks *jose.JSONWebKeySet) (map[string]interface{}, error) {
+	raw, err := json.Marshal(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]interface{}
+
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+const (
+	// DefaultIssuerListLimit is the number of issuers listed per page by default.
+	DefaultIssuerListLimit = 50
+	// MaxIssuerListLimit is the maximum number of issuers listed per page.
+	MaxIssuerListLimit = 200
+)
+
+// IssuerSortField represents a field issuers can be sorted by.
+type IssuerSortField string
+
+const (
+	// IssuerSortName sorts issuers by name.
+	IssuerSortName IssuerSortField = "name"
+	// IssuerSortURI sorts issuers by URI.
+	IssuerSortURI IssuerSortField = "uri"
+)
+
+// IssuerListOptions represents the options for listing a tenant's issuers.
+type IssuerListOptions struct {
+	// Name filters issuers to those whose name contains the given value, ignoring case.
+	Name string
+	// URI filters issuers to the one with the given URI.
+	URI string
+	// SortBy is the field issuers are sorted by, with ties broken by ID. Defaults to IssuerSortName.
+	SortBy IssuerSortField
+	// Descending sorts issuers in descending order.
+	Descending bool
+	// Cursor is the cursor returned with the previous page, if any. Cursors may only be used with the
+	// sort order they were returned for.
+	Cursor string
+	// Limit is the maximum number of issuers listed. Defaults to DefaultIssuerListLimit, and is capped
+	// at MaxIssuerListLimit.
+	Limit int
+}
+
+// IssuerPage represents a page of a tenant's issuers.
+type IssuerPage struct {
+	Issuers []Issuer
+	// NextCursor is the cursor for the next page, or empty if there are no more issuers.
+	NextCursor string
 }
 
 // IssuerService represents a service for managing issuers.
@@ -61,15 +202,32 @@ type IssuerService interface {
 	CreateIssuer(ctx context.Context, iss Issuer) (*Issuer, error)
 	GetIssuerByID(ctx context.Context, id string) (*Issuer, error)
 	GetIssuerByURI(ctx context.Context, uri string) (*Issuer, error)
+	ListIssuers(ctx context.Context, tenantID string, opts IssuerListOptions) (*IssuerPage, error)
 	UpdateIssuer(ctx context.Context, id string, update IssuerUpdate) (*Issuer, error)
 	DeleteIssuer(ctx context.Context, id string) error
 }
 
+// ClaimMappingContext represents the context of a token exchange that claim mappings are evaluated in.
+type ClaimMappingContext struct {
+	// ClientID is the ID of the client performing the exchange, if any.
+	ClientID string
+	// RequestedScopes represents the scopes requested in the exchange.
+	RequestedScopes []string
+	// Audience represents the audience requested in the exchange.
+	Audience []string
+	// UserInfo represents the user info for the subject of the exchange, if known.
+	UserInfo *UserInfo
+	// Roles represents the roles mapped from the subject's upstream groups.
+	Roles []string
+}
+
 // ClaimsMapping represents a map of claims to a CEL expression that will be evaluated
 type ClaimsMapping map[string]*cel.Ast
 
-// NewClaimsMapping creates a ClaimsMapping from the given map of CEL expressions.
-func NewClaimsMapping(exprs map[string]string) (ClaimsMapping, error) {
+// NewClaimsMapping creates a ClaimsMapping from the given map of CEL expressions, rejecting any
+// expression whose estimated cost exceeds the configured limit or that cannot produce its declared
+// output type.
+func NewClaimsMapping(exprs map[string]string, outputs ClaimMappingOutputs) (ClaimsMapping, error) {
 	out := make(ClaimsMapping, len(exprs))
 
 	for k, v := range exprs {
@@ -78,9 +236,33 @@ func NewClaimsMapping(exprs map[string]string) (ClaimsMapping, error) {
 			return nil, err
 		}
 
+		if err := celutils.CheckCost(ast); err != nil {
+			return nil, err
+		}
+
 		out[k] = ast
 	}
 
+	if err := outputs.Check(out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Compile compiles each CEL expression in the mapping into a program.
+func (c ClaimsMapping) Compile() (ClaimMappingPrograms, error) {
+	out := make(ClaimMappingPrograms, len(c))
+
+	for k, v := range c {
+		prog, err := celutils.Program(v)
+		if err != nil {
+			return nil, err
+		}
+
+		out[k] = prog
+	}
+
 	return out, nil
 }
 
@@ -151,6 +333,89 @@ func (c *ClaimsMapping) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ClaimMappingOutput represents the declared output of a claim mapping.
+type ClaimMappingOutput struct {
+	// Type represents the type the mapping must produce. If empty, the output type is not checked.
+	Type celutils.OutputType `json:"type,omitempty"`
+	// OmitNull represents whether the claim is omitted if the mapping evaluates to null.
+	OmitNull bool `json:"omit_null,omitempty"`
+}
+
+// ClaimMappingOutputs represents the declared outputs of claim mappings, keyed by claim.
+type ClaimMappingOutputs map[string]ClaimMappingOutput
+
+// NewClaimMappingOutputs creates ClaimMappingOutputs from the given API claim mapping outputs.
+func NewClaimMappingOutputs(in map[string]v1.ClaimMappingOutput) ClaimMappingOutputs {
+	out := make(ClaimMappingOutputs, len(in))
+
+	for k, v := range in {
+		var output ClaimMappingOutput
+
+		if v.Type != nil {
+			output.Type = celutils.OutputType(*v.Type)
+		}
+
+		if v.OmitNull != nil {
+			output.OmitNull = *v.OmitNull
+		}
+
+		out[k] = output
+	}
+
+	return out
+}
+
+// Check checks that each mapping in the given ClaimsMapping can produce its declared output type.
+func (o ClaimMappingOutputs) Check(mappings ClaimsMapping) error {
+	for k, ast := range mappings {
+		if err := celutils.CheckOutputType(ast, o[k].Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// For returns the declared outputs of the mappings in the given ClaimsMapping, dropping outputs
+// declared for claims that are not mapped.
+func (o ClaimMappingOutputs) For(mappings ClaimsMapping) ClaimMappingOutputs {
+	out := make(ClaimMappingOutputs, len(o))
+
+	for k, v := range o {
+		if _, ok := mappings[k]; ok {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// ToV1ClaimMappingOutputs converts claim mapping outputs to API claim mapping outputs.
+func (o ClaimMappingOutputs) ToV1ClaimMappingOutputs() map[string]v1.ClaimMappingOutput {
+	out := make(map[string]v1.ClaimMappingOutput, len(o))
+
+	for k, v := range o {
+		var output v1.ClaimMappingOutput
+
+		if v.Type != "" {
+			outputType := v1.ClaimMappingOutputType(v.Type)
+			output.Type = &outputType
+		}
+
+		if v.OmitNull {
+			omitNull := v.OmitNull
+			output.OmitNull = &omitNull
+		}
+
+		out[k] = output
+	}
+
+	return out
+}
+
+// ClaimMappingPrograms represents a map of claims to a compiled CEL program that will be evaluated
+type ClaimMappingPrograms map[string]cel.Program
+
 // BuildClaimsMappingFromMap builds a ClaimsMapping from a map of strings.
 func BuildClaimsMappingFromMap(in map[string]*exprpb.CheckedExpr) ClaimsMapping {
 	out := make(ClaimsMapping, len(in))
@@ -188,3 +453,258 @@ type UserInfoService interface {
 	// and unpacks it into the UserInfo type.
 	FetchUserInfoFromIssuer(ctx context.Context, iss, rawToken string) (*UserInfo, error)
 }
+
+// GroupRoleMapping represents a mapping from a group in an upstream issuer's groups claim to an
+// Infratographer role.
+type GroupRoleMapping struct {
+	// ID represents the ID of the mapping.
+	ID string
+	// TenantID represents the ID of the tenant the mapping belongs to.
+	TenantID string
+	// IssuerID represents the ID of the issuer the mapping applies to.
+	IssuerID string
+	// Group represents the group value as it appears in the issuer's groups claim.
+	Group string
+	// Role represents the name of the role granted to members of the group.
+	Role string
+}
+
+// ToV1GroupRoleMapping converts a group role mapping to an API group role mapping.
+func (m GroupRoleMapping) ToV1GroupRoleMapping() v1.GroupRoleMapping {
+	return v1.GroupRoleMapping{
+		ID:       uuid.MustParse(m.ID),
+		IssuerID: uuid.MustParse(m.IssuerID),
+		Group:    m.Group,
+		Role:     m.Role,
+	}
+}
+
+// GroupRoleMappingService represents a service for managing mappings from upstream groups to roles.
+type GroupRoleMappingService interface {
+	CreateGroupRoleMapping(ctx context.Context, mapping GroupRoleMapping) (*GroupRoleMapping, error)
+	ListGroupRoleMappings(ctx context.Context, issuerID string) ([]GroupRoleMapping, error)
+
+	// DeleteGroupRoleMapping deletes a mapping from an issuer and returns the deleted mapping.
+	DeleteGroupRoleMapping(ctx context.Context, issuerID, id string) (*GroupRoleMapping, error)
+
+	// LookupRolesByGroups returns the sorted, distinct roles mapped from the given groups for an issuer.
+	LookupRolesByGroups(ctx context.Context, issuerID string, groups []string) ([]string, error)
+}
+
+// PairwiseSubject represents a pairwise subject identifier, which identifies a user to a single sector
+// (i.e., a client or audience) without revealing the identifier issued to other sectors.
+type PairwiseSubject struct {
+	// ID represents the pairwise subject identifier.
+	ID string
+	// UserInfoID represents the ID of the user info the identifier was derived from.
+	UserInfoID string
+	// Sector represents the sector the identifier was issued to.
+	Sector string
+}
+
+// PairwiseSubjectService represents a service for storing and resolving pairwise subject identifiers.
+type PairwiseSubjectService interface {
+	// StorePairwiseSubject stores a pairwise subject identifier. Storing an identifier that already
+	// exists is not an error.
+	StorePairwiseSubject(ctx context.Context, subject PairwiseSubject) (*PairwiseSubject, error)
+
+	// LookupPairwiseSubject returns the pairwise subject with the given identifier.
+	LookupPairwiseSubject(ctx context.Context, id string) (*PairwiseSubject, error)
+}
+
+// JWKSSnapshot represents the last JWKS successfully fetched for an issuer, used when the issuer's JWKS
+// cannot be fetched.
+type JWKSSnapshot struct {
+	// IssuerID represents the ID of the issuer the JWKS was fetched for.
+	IssuerID string
+	// JWKS represents the fetched JWKS.
+	JWKS *jose.JSONWebKeySet
+	// FetchedAt represents when the JWKS was fetched.
+	FetchedAt time.Time
+}
+
+// JWKSSnapshotService represents a service for storing issuers' last known good JWKS.
+type JWKSSnapshotService interface {
+	// StoreJWKSSnapshot stores a JWKS snapshot, replacing the issuer's previous snapshot.
+	StoreJWKSSnapshot(ctx context.Context, snapshot JWKSSnapshot) error
+
+	// GetJWKSSnapshot returns the JWKS snapshot for the given issuer.
+	GetJWKSSnapshot(ctx context.Context, issuerID string) (*JWKSSnapshot, error)
+}
+
+// SigningKey represents a token signing key generated and stored by identity-api. The private key is
+// encrypted with a key-encryption key before it is stored.
+type SigningKey struct {
+	// ID represents the key ID.
+	ID string
+	// Algorithm represents the JWT signing algorithm of the key.
+	Algorithm string
+	// State represents the key's rotation state.
+	State string
+	// PublicKey represents the public key, which is published in the JWKS.
+	PublicKey *jose.JSONWebKey
+	// EncryptedPrivateKey represents the encrypted private key.
+	EncryptedPrivateKey []byte
+	// CreatedAt represents when the key was generated.
+	CreatedAt time.Time
+	// ActivatedAt represents when the key was last promoted to sign tokens, or the zero time if it never
+	// has been.
+	ActivatedAt time.Time
+}
+
+// ToV1SigningKey converts a signing key to an API signing key. The private key is never included.
+func (k SigningKey) ToV1SigningKey() (v1.SigningKey, error) {
+	publicKey, err := json.Marshal(k.PublicKey)
+	if err != nil {
+		return v1.SigningKey{}, err
+	}
+
+	out := v1.SigningKey{
+		ID:        k.ID,
+		Algorithm: k.Algorithm,
+		State:     k.State,
+		CreatedAt: k.CreatedAt,
+	}
+
+	if err := json.Unmarshal(publicKey, &out.PublicKey); err != nil {
+		return v1.SigningKey{}, err
+	}
+
+	if !k.ActivatedAt.IsZero() {
+		activatedAt := k.ActivatedAt
+		out.ActivatedAt = &activatedAt
+	}
+
+	return out, nil
+}
+
+// SigningKeyUpdate represents an update operation on a signing key.
+type SigningKeyUpdate struct {
+	State       *string
+	ActivatedAt *time.Time
+}
+
+// SigningKeyService represents a service for storing signing keys.
+type SigningKeyService interface {
+	CreateSigningKey(ctx context.Context, key SigningKey) (*SigningKey, error)
+	GetSigningKeyByID(ctx context.Context, id string) (*SigningKey, error)
+	UpdateSigningKey(ctx context.Context, id string, update SigningKeyUpdate) (*SigningKey, error)
+
+	// ListSigningKeys returns all signing keys, oldest first.
+	ListSigningKeys(ctx context.Context) ([]SigningKey, error)
+}
+
+const (
+	// DefaultAuditEventListLimit is the number of audit events listed per page by default.
+	DefaultAuditEventListLimit = 50
+	// MaxAuditEventListLimit is the maximum number of audit events listed per page.
+	MaxAuditEventListLimit = 200
+)
+
+// AuditAction represents an operation recorded in the audit log.
+type AuditAction string
+
+const (
+	// AuditActionCreate records the creation of a resource.
+	AuditActionCreate AuditAction = "create"
+	// AuditActionUpdate records an update to a resource.
+	AuditActionUpdate AuditAction = "update"
+	// AuditActionDelete records the deletion of a resource.
+	AuditActionDelete AuditAction = "delete"
+)
+
+const (
+	// AuditResourceIssuer is the resource type of issuers in the audit log.
+	AuditResourceIssuer = "issuer"
+	// AuditResourceRoleMapping is the resource type of group to role mappings in the audit log.
+	AuditResourceRoleMapping = "role_mapping"
+	// AuditResourceSigningKey is the resource type of managed signing keys in the audit log.
+	AuditResourceSigningKey = "signing_key"
+)
+
+// AuditEvent represents a change made to a tenant's resources, or to a resource not scoped to a tenant,
+// through the management API.
+type AuditEvent struct {
+	// ID represents the ID of the event.
+	ID string
+	// TenantID represents the ID of the tenant the changed resource belongs to, or is empty if the
+	// resource is not scoped to a tenant, such as a signing key.
+	TenantID string
+	// Actor represents the subject of the token the change was made with.
+	Actor string
+	// Action represents the operation performed on the resource.
+	Action AuditAction
+	// ResourceType represents the type of the changed resource.
+	ResourceType string
+	// ResourceID represents the ID of the changed resource.
+	ResourceID string
+	// RequestID represents the ID of the request the change was made in.
+	RequestID string
+	// Before represents the JSON fields of the resource before the change, or nil if it was created.
+	Before json.RawMessage
+	// After represents the JSON fields of the resource after the change, or nil if it was deleted.
+	After json.RawMessage
+	// CreatedAt represents when the change was made.
+	CreatedAt time.Time
+}
+
+// ToV1AuditEvent converts an audit event to an API audit event.
+func (e AuditEvent) ToV1AuditEvent() (v1.AuditEvent, error) {
+	out := v1.AuditEvent{
+		ID:           uuid.MustParse(e.ID),
+		Actor:        e.Actor,
+		Action:       v1.AuditEventAction(e.Action),
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		RequestID:    e.RequestID,
+		CreatedAt:    e.CreatedAt,
+	}
+
+	if e.TenantID != "" {
+		tenantID := uuid.MustParse(e.TenantID)
+		out.TenantID = &tenantID
+	}
+
+	if e.Before != nil {
+		if err := json.Unmarshal(e.Before, &out.Before); err != nil {
+			return v1.AuditEvent{}, err
+		}
+	}
+
+	if e.After != nil {
+		if err := json.Unmarshal(e.After, &out.After); err != nil {
+			return v1.AuditEvent{}, err
+		}
+	}
+
+	return out, nil
+}
+
+// AuditEventListOptions represents the options for listing audit events.
+type AuditEventListOptions struct {
+	// ResourceID filters events to those for the resource with the given ID.
+	ResourceID string
+	// Cursor is the cursor returned with the previous page, if any.
+	Cursor string
+	// Limit is the maximum number of events listed. Defaults to DefaultAuditEventListLimit, and is
+	// capped at MaxAuditEventListLimit.
+	Limit int
+}
+
+// AuditEventPage represents a page of audit events.
+type AuditEventPage struct {
+	Events []AuditEvent
+	// NextCursor is the cursor for the next page, or empty if there are no more events.
+	NextCursor string
+}
+
+// AuditEventService represents a service for recording and listing audit events.
+type AuditEventService interface {
+	// RecordAuditEvent records an audit event in the transaction in the context, so the event is only
+	// recorded if the change it describes is committed.
+	RecordAuditEvent(ctx context.Context, event AuditEvent) (*AuditEvent, error)
+
+	// ListAuditEvents lists a page of the given tenant's audit events, newest first. If tenantID is empty,
+	// the events of resources not scoped to a tenant are listed.
+	ListAuditEvents(ctx context.Context, tenantID string, opts AuditEventListOptions) (*AuditEventPage, error)
+}
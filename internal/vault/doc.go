@@ -0,0 +1,2 @@
+// Package vault signs tokens with keys held by the HashiCorp Vault transit secrets engine.
+package vault
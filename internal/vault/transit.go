@@ -0,0 +1,370 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+)
+
+const (
+	// DefaultMount is the default path of the transit secrets engine.
+	DefaultMount = "transit"
+	// DefaultRefreshInterval is how often a transit key's public keys are refreshed by default.
+	DefaultRefreshInterval = 5 * time.Minute
+	// DefaultTimeout is how long a request to Vault may take by default.
+	DefaultTimeout = 10 * time.Second
+
+	signaturePrefix = "vault:v"
+)
+
+var (
+	// ErrVaultRequest represents a failed request to Vault.
+	ErrVaultRequest = errors.New("vault request failed")
+
+	// ErrUnsupportedAlgorithm is returned when a signing algorithm cannot be used with Vault transit keys.
+	ErrUnsupportedAlgorithm = errors.New("unsupported transit signing algorithm")
+
+	// ErrInvalidPublicKey is returned when Vault returns a public key that cannot be parsed.
+	ErrInvalidPublicKey = errors.New("invalid transit public key")
+
+	// ErrInvalidSignature is returned when Vault returns a signature that cannot be parsed.
+	ErrInvalidSignature = errors.New("invalid transit signature")
+)
+
+// transitParams represents the transit sign parameters used for a JWT signing algorithm.
+type transitParams struct {
+	hashAlgorithm      string
+	signatureAlgorithm string
+	jwsMarshaling      bool
+}
+
+var algorithmParams = map[jose.SignatureAlgorithm]transitParams{
+	jose.RS256: {hashAlgorithm: "sha2-256", signatureAlgorithm: "pkcs1v15"},
+	jose.RS384: {hashAlgorithm: "sha2-384", signatureAlgorithm: "pkcs1v15"},
+	jose.RS512: {hashAlgorithm: "sha2-512", signatureAlgorithm: "pkcs1v15"},
+	jose.PS256: {hashAlgorithm: "sha2-256", signatureAlgorithm: "pss"},
+	jose.PS384: {hashAlgorithm: "sha2-384", signatureAlgorithm: "pss"},
+	jose.PS512: {hashAlgorithm: "sha2-512", signatureAlgorithm: "pss"},
+	jose.ES256: {hashAlgorithm: "sha2-256", jwsMarshaling: true},
+	jose.ES384: {hashAlgorithm: "sha2-384", jwsMarshaling: true},
+	jose.ES512: {hashAlgorithm: "sha2-512", jwsMarshaling: true},
+	jose.EdDSA: {},
+}
+
+type keysResponse struct {
+	Data struct {
+		Keys map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+		LatestVersion int `json:"latest_version"`
+	} `json:"data"`
+}
+
+type signRequest struct {
+	Input               string `json:"input"`
+	KeyVersion          int    `json:"key_version"`
+	HashAlgorithm       string `json:"hash_algorithm,omitempty"`
+	SignatureAlgorithm  string `json:"signature_algorithm,omitempty"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm,omitempty"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// TransitSigner is a fositex.Signer that signs tokens with a Vault transit key. Tokens are signed with
+// the latest version of the key, and every version Vault returns is published.
+type TransitSigner struct {
+	config     fositex.VaultTransitConfig
+	params     transitParams
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	version int
+	jwks    *jose.JSONWebKeySet
+}
+
+// implement the fositex.Signer interface
+var _ fositex.Signer = (*TransitSigner)(nil)
+
+// NewTransitSigner creates a new TransitSigner using the given HTTP client, fetching the transit key's
+// public keys.
+func NewTransitSigner(ctx context.Context, config fositex.VaultTransitConfig, httpClient *http.Client) (*TransitSigner, error) {
+	params, ok := algorithmParams[config.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, config.Algorithm)
+	}
+
+	if config.Mount == "" {
+		config.Mount = DefaultMount
+	}
+
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = DefaultRefreshInterval
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	out := &TransitSigner{
+		config:     config,
+		params:     params,
+		httpClient: httpClient,
+	}
+
+	if err := out.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Public returns the public key of the latest version of the transit key.
+func (s *TransitSigner) Public() *jose.JSONWebKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := s.jwks.Key(s.keyID(s.version))
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return &keys[0]
+}
+
+// Algs returns the signing algorithm of the transit key.
+func (s *TransitSigner) Algs() []jose.SignatureAlgorithm {
+	return []jose.SignatureAlgorithm{s.config.Algorithm}
+}
+
+// JWKS returns the public keys of every version of the transit key.
+func (s *TransitSigner) JWKS() *jose.JSONWebKeySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.jwks
+}
+
+// SignPayload signs the payload with the latest version of the transit key. Signers returned by
+// WithContext should be preferred, so that signing is canceled along with the request it is made for.
+func (s *TransitSigner) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	return s.signPayload(context.Background(), payload, alg)
+}
+
+// WithContext returns a jose.OpaqueSigner that signs with the transit key, making requests to Vault with
+// the given context.
+func (s *TransitSigner) WithContext(ctx context.Context) jose.OpaqueSigner {
+	return &contextSigner{
+		TransitSigner: s,
+		ctx:           ctx,
+	}
+}
+
+func (s *TransitSigner) signPayload(ctx context.Context, payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	if alg != s.config.Algorithm {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+
+	s.mu.RLock()
+	version := s.version
+	s.mu.RUnlock()
+
+	req := signRequest{
+		Input:              base64.StdEncoding.EncodeToString(payload),
+		KeyVersion:         version,
+		HashAlgorithm:      s.params.hashAlgorithm,
+		SignatureAlgorithm: s.params.signatureAlgorithm,
+	}
+
+	if s.params.jwsMarshaling {
+		req.MarshalingAlgorithm = "jws"
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp signResponse
+
+	if err := s.do(ctx, http.MethodPost, "sign", bytes.NewReader(body), &resp); err != nil {
+		return nil, err
+	}
+
+	return s.decodeSignature(resp.Data.Signature)
+}
+
+// Refresh fetches the public keys of the transit key, so versions created by rotating the key in Vault
+// are published and used to sign.
+func (s *TransitSigner) Refresh(ctx context.Context) error {
+	var resp keysResponse
+
+	if err := s.do(ctx, http.MethodGet, "keys", nil, &resp); err != nil {
+		return err
+	}
+
+	versions := make([]int, 0, len(resp.Data.Keys))
+
+	for v := range resp.Data.Keys {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: invalid version %s", ErrInvalidPublicKey, v)
+		}
+
+		versions = append(versions, version)
+	}
+
+	sort.Ints(versions)
+
+	jwks := &jose.JSONWebKeySet{
+		Keys: make([]jose.JSONWebKey, 0, len(versions)),
+	}
+
+	for _, version := range versions {
+		key, err := parsePublicKey(resp.Data.Keys[strconv.Itoa(version)].PublicKey)
+		if err != nil {
+			return err
+		}
+
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{
+			Key:       key,
+			KeyID:     s.keyID(version),
+			Algorithm: string(s.config.Algorithm),
+			Use:       "sig",
+		})
+	}
+
+	if len(jwks.Key(s.keyID(resp.Data.LatestVersion))) == 0 {
+		return fmt.Errorf("%w: no public key for latest version %d", ErrInvalidPublicKey, resp.Data.LatestVersion)
+	}
+
+	s.mu.Lock()
+	s.version = resp.Data.LatestVersion
+	s.jwks = jwks
+	s.mu.Unlock()
+
+	return nil
+}
+
+// StartBackgroundRefresh refreshes the transit key's public keys every refresh interval until the given
+// context is canceled. Failed refreshes keep the previous keys.
+func (s *TransitSigner) StartBackgroundRefresh(ctx context.Context) {
+	ticker := time.NewTicker(s.config.RefreshInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = s.Refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (s *TransitSigner) keyID(version int) string {
+	return fmt.Sprintf("%s-%d", s.config.Key, version)
+}
+
+func (s *TransitSigner) decodeSignature(signature string) ([]byte, error) {
+	// Signatures are of the form "vault:v<version>:<signature>".
+	parts := strings.SplitN(signature, ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(signature, signaturePrefix) {
+		return nil, ErrInvalidSignature
+	}
+
+	encoding := base64.StdEncoding
+	if s.params.jwsMarshaling {
+		encoding = base64.RawURLEncoding
+	}
+
+	out, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	return out, nil
+}
+
+func (s *TransitSigner) do(ctx context.Context, method, action string, body io.Reader, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(s.config.Address, "/"), s.config.Mount, action, s.config.Key)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Vault-Token", s.config.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected response code %d from %s", ErrVaultRequest, resp.StatusCode, action)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// contextSigner is a TransitSigner bound to the context of the request it signs for.
+type contextSigner struct {
+	*TransitSigner
+
+	ctx context.Context
+}
+
+// SignPayload signs the payload with the latest version of the transit key.
+func (s *contextSigner) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	return s.signPayload(s.ctx, payload, alg)
+}
+
+// parsePublicKey parses a transit public key. RSA and ECDSA keys are PEM-encoded PKIX keys, while
+// Ed25519 keys are base64-encoded.
+func parsePublicKey(in string) (any, error) {
+	if block, _ := pem.Decode([]byte(in)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPublicKey, err)
+		}
+
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(in)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
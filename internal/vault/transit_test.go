@@ -0,0 +1,276 @@
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+
+	"go.infratographer.com/identity-api/internal/fositex"
+	"go.infratographer.com/identity-api/internal/testingx"
+)
+
+const testToken = "s.token"
+
+// fakeTransit serves the transit keys and sign endpoints for a single version of a key.
+func fakeTransit(t *testing.T, key crypto.Signer) *httptest.Server {
+	var publicKey string
+
+	switch pub := key.Public().(type) {
+	case ed25519.PublicKey:
+		publicKey = base64.StdEncoding.EncodeToString(pub)
+	default:
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		publicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/transit/keys/test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		resp := map[string]any{
+			"data": map[string]any{
+				"keys": map[string]any{
+					"1": map[string]any{
+						"public_key": publicKey,
+					},
+				},
+				"latest_version": 1,
+			},
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	mux.HandleFunc("/v1/transit/sign/test", func(w http.ResponseWriter, r *http.Request) {
+		var req signRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.KeyVersion != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		input, err := base64.StdEncoding.DecodeString(req.Input)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var signature string
+
+		switch k := key.(type) {
+		case ed25519.PrivateKey:
+			signature = base64.StdEncoding.EncodeToString(ed25519.Sign(k, input))
+		case *ecdsa.PrivateKey:
+			digest := sha256.Sum256(input)
+
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			raw := make([]byte, 64)
+			r.FillBytes(raw[:32])
+			s.FillBytes(raw[32:])
+
+			signature = base64.RawURLEncoding.EncodeToString(raw)
+		}
+
+		resp := map[string]any{
+			"data": map[string]any{
+				"signature": "vault:v1:" + signature,
+			},
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	srv := httptest.NewServer(mux)
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// TestTransitSigner checks that tokens signed by Vault transit keys verify against the published JWKS.
+func TestTransitSigner(t *testing.T) {
+	t.Parallel()
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type input struct {
+		key   crypto.Signer
+		alg   jose.SignatureAlgorithm
+		token string
+	}
+
+	runFn := func(ctx context.Context, in input) testingx.TestResult[string] {
+		srv := fakeTransit(t, in.key)
+
+		config := fositex.VaultTransitConfig{
+			Address:   srv.URL,
+			Token:     in.token,
+			Key:       "test",
+			Algorithm: in.alg,
+		}
+
+		signer, err := NewTransitSigner(ctx, config, srv.Client())
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		jwsSigner, err := jose.NewSigner(
+			jose.SigningKey{
+				Algorithm: in.alg,
+				Key: &jose.JSONWebKey{
+					Key:   signer.WithContext(ctx),
+					KeyID: signer.Public().KeyID,
+				},
+			},
+			nil,
+		)
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		token, err := josejwt.Signed(jwsSigner).Claims(josejwt.Claims{Subject: "sub"}).CompactSerialize()
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		parsed, err := josejwt.ParseSigned(token)
+		if err != nil {
+			return testingx.TestResult[string]{Err: err}
+		}
+
+		keys := signer.JWKS().Key(parsed.Headers[0].KeyID)
+		if len(keys) != 1 {
+			return testingx.TestResult[string]{Err: ErrInvalidPublicKey}
+		}
+
+		var out josejwt.Claims
+
+		err = parsed.Claims(keys[0].Key, &out)
+
+		return testingx.TestResult[string]{
+			Success: out.Subject,
+			Err:     err,
+		}
+	}
+
+	checkSuccess := func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+		if assert.NoError(t, res.Err) {
+			assert.Equal(t, "sub", res.Success)
+		}
+	}
+
+	testCases := []testingx.TestCase[input, string]{
+		{
+			Name: "ES256",
+			Input: input{
+				key:   p256Key,
+				alg:   jose.ES256,
+				token: testToken,
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "EdDSA",
+			Input: input{
+				key:   ed25519Key,
+				alg:   jose.EdDSA,
+				token: testToken,
+			},
+			CheckFn: checkSuccess,
+		},
+		{
+			Name: "Forbidden",
+			Input: input{
+				key:   p256Key,
+				alg:   jose.ES256,
+				token: "s.invalid",
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				assert.ErrorIs(t, res.Err, ErrVaultRequest)
+			},
+		},
+		{
+			Name: "UnsupportedAlgorithm",
+			Input: input{
+				key:   p256Key,
+				alg:   jose.HS256,
+				token: testToken,
+			},
+			CheckFn: func(ctx context.Context, t *testing.T, res testingx.TestResult[string]) {
+				assert.ErrorIs(t, res.Err, ErrUnsupportedAlgorithm)
+			},
+		},
+	}
+
+	testingx.RunTests(context.Background(), t, testCases, runFn)
+}
+
+// TestTransitSignerContext checks that signers bound to a context make their requests to Vault with it.
+func TestTransitSignerContext(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := fakeTransit(t, key)
+
+	config := fositex.VaultTransitConfig{
+		Address:   srv.URL,
+		Token:     testToken,
+		Key:       "test",
+		Algorithm: jose.ES256,
+	}
+
+	signer, err := NewTransitSigner(context.Background(), config, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err = signer.WithContext(ctx).SignPayload([]byte("payload"), jose.ES256)
+	assert.NoError(t, err)
+
+	cancel()
+
+	_, err = signer.WithContext(ctx).SignPayload([]byte("payload"), jose.ES256)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -1,6 +0,0 @@
-package v1
-
-// ErrorResponse represents a generic error response.
-type ErrorResponse struct {
-	Errors []string `json:"errors"`
-}
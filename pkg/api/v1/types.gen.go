@@ -11,24 +11,174 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuditEventAction.
+const (
+	Create AuditEventAction = "create"
+	Delete AuditEventAction = "delete"
+	Update AuditEventAction = "update"
+)
+
+// Defines values for ClaimMappingOutputType.
+const (
+	Bool       ClaimMappingOutputType = "bool"
+	Int        ClaimMappingOutputType = "int"
+	Liststring ClaimMappingOutputType = "list<string>"
+	Map        ClaimMappingOutputType = "map"
+	String     ClaimMappingOutputType = "string"
+)
+
+// Defines values for ListIssuersParamsSort.
+const (
+	MinusName ListIssuersParamsSort = "-name"
+	MinusUri  ListIssuersParamsSort = "-uri"
+	Name      ListIssuersParamsSort = "name"
+	Uri       ListIssuersParamsSort = "uri"
+)
+
+// AuditEvent defines model for AuditEvent.
+type AuditEvent struct {
+	// Action Operation performed on the resource
+	Action AuditEventAction `json:"action"`
+
+	// Actor Subject of the token the change was made with. Empty if management API authentication is disabled
+	Actor string `json:"actor"`
+
+	// After Fields of the resource after the change. Omitted for deletions, and only includes changed fields for updates
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// Before Fields of the resource before the change. Omitted for creations, and only includes changed fields for updates
+	Before *map[string]interface{} `json:"before,omitempty"`
+
+	// CreatedAt When the change was made
+	CreatedAt time.Time `json:"created_at"`
+
+	// Id ID of the audit event
+	ID openapi_types.UUID `json:"id"`
+
+	// RequestId ID of the request the change was made in, from its X-Request-ID header
+	RequestID string `json:"request_id"`
+
+	// ResourceId ID of the changed resource
+	ResourceID string `json:"resource_id"`
+
+	// ResourceType Type of the changed resource (issuer, role_mapping or signing_key)
+	ResourceType string `json:"resource_type"`
+
+	// TenantId ID of the tenant the changed resource belongs to. Omitted for resources not scoped to a tenant, such as signing keys
+	TenantID *openapi_types.UUID `json:"tenant_id,omitempty"`
+}
+
+// AuditEventAction Operation performed on the resource
+type AuditEventAction string
+
+// AuditEventList defines model for AuditEventList.
+type AuditEventList struct {
+	// AuditEvents Audit events of the tenant, newest first
+	AuditEvents []AuditEvent `json:"audit_events"`
+
+	// NextCursor Cursor for the next page of audit events. Omitted on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// ClaimMappingEvaluation defines model for ClaimMappingEvaluation.
+type ClaimMappingEvaluation struct {
+	// Claims Claims that would be issued in an access token for the given input
+	Claims map[string]interface{} `json:"claims"`
+
+	// Errors Error for each claim mapping that failed to parse or evaluate
+	Errors map[string]string `json:"errors"`
+
+	// MappedClaims Output of each claim mapping that evaluated successfully
+	MappedClaims map[string]interface{} `json:"mapped_claims"`
+}
+
+// ClaimMappingEvaluationRequest defines model for ClaimMappingEvaluationRequest.
+type ClaimMappingEvaluationRequest struct {
+	// Audience Sample audience requested in the exchange
+	Audience *[]string `json:"audience,omitempty"`
+
+	// ClaimMappingOutputs Candidate declared outputs of claim mappings, keyed by claim. Replaces the issuer's declared outputs if provided
+	ClaimMappingOutputs *map[string]ClaimMappingOutput `json:"claim_mapping_outputs,omitempty"`
+
+	// ClaimMappings Candidate CEL expressions to evaluate in place of the issuer's configured claim mappings
+	ClaimMappings *map[string]string `json:"claim_mappings,omitempty"`
+
+	// Claims Sample subject token claims. Exactly one of claims or subject_token must be provided
+	Claims *map[string]interface{} `json:"claims,omitempty"`
+
+	// ClientId Sample ID of the client performing the exchange
+	ClientID *string `json:"client_id,omitempty"`
+
+	// RequestedScopes Sample scopes requested in the exchange
+	RequestedScopes *[]string `json:"requested_scopes,omitempty"`
+
+	// SubjectToken Sample subject token JWT. The signature is not verified. Exactly one of claims or subject_token must be provided
+	SubjectToken *string         `json:"subject_token,omitempty"`
+	Userinfo     *SampleUserInfo `json:"userinfo,omitempty"`
+}
+
+// ClaimMappingOutput defines model for ClaimMappingOutput.
+type ClaimMappingOutput struct {
+	// OmitNull Omit the claim if the claim mapping evaluates to null
+	OmitNull *bool `json:"omit_null,omitempty"`
+
+	// Type Type the claim mapping must produce. If omitted, the output type is not checked
+	Type *ClaimMappingOutputType `json:"type,omitempty"`
+}
+
+// ClaimMappingOutputType Type the claim mapping must produce. If omitted, the output type is not checked
+type ClaimMappingOutputType string
+
+// CreateGroupRoleMapping defines model for CreateGroupRoleMapping.
+type CreateGroupRoleMapping struct {
+	// Group Group value as it appears in the upstream issuer's groups claim
+	Group string `json:"group"`
+
+	// Role Name of the role granted to members of the group
+	Role string `json:"role"`
+}
+
 // CreateIssuer defines model for CreateIssuer.
 type CreateIssuer struct {
+	// ClaimMappingOutputs Declared outputs of claim mappings, keyed by claim
+	ClaimMappingOutputs *map[string]ClaimMappingOutput `json:"claim_mapping_outputs,omitempty"`
+
 	// ClaimMappings CEL expressions mapping token claims to other claims
 	ClaimMappings *map[string]string `json:"claim_mappings,omitempty"`
 
-	// JwksUri JWKS URI
-	JWKSURI string `json:"jwks_uri"`
+	// IntrospectionEndpoint Token introspection endpoint. If omitted, the introspection_endpoint from the issuer's OIDC discovery metadata is used
+	IntrospectionEndpoint *string `json:"introspection_endpoint,omitempty"`
+
+	// Jwks Inline JWKS used to verify the issuer's tokens in place of fetching its JWKS URI. Cannot be combined with jwks_uri
+	JWKS *map[string]interface{} `json:"jwks,omitempty"`
+
+	// JwksUri JWKS URI. If omitted, the jwks_uri from the issuer's OIDC discovery metadata is used
+	JWKSURI *string `json:"jwks_uri,omitempty"`
 
 	// Name A human-readable name for the issuer
 	Name string `json:"name"`
 
 	// Uri URI for the issuer. Must match the "iss" claim value in incoming JWTs
 	URI string `json:"uri"`
+
+	// UserinfoEndpoint UserInfo endpoint. If omitted, the userinfo_endpoint from the issuer's OIDC discovery metadata is used
+	UserInfoEndpoint *string `json:"userinfo_endpoint,omitempty"`
+}
+
+// CreateSigningKey defines model for CreateSigningKey.
+type CreateSigningKey struct {
+	// Algorithm JWT signing algorithm of the key. If omitted, the configured default algorithm is used
+	Algorithm *string `json:"algorithm,omitempty"`
 }
 
 // DeleteResponse defines model for DeleteResponse.
@@ -37,30 +187,87 @@ type DeleteResponse struct {
 	Success bool `json:"success"`
 }
 
+// ErrorResponse A generic error response
+type ErrorResponse struct {
+	// Errors Messages describing why the request failed
+	Errors []string `json:"errors"`
+}
+
+// GroupRoleMapping defines model for GroupRoleMapping.
+type GroupRoleMapping struct {
+	// Group Group value as it appears in the upstream issuer's groups claim
+	Group string `json:"group"`
+
+	// Id ID of the role mapping
+	ID openapi_types.UUID `json:"id"`
+
+	// IssuerId ID of the issuer the role mapping belongs to
+	IssuerID openapi_types.UUID `json:"issuer_id"`
+
+	// Role Name of the role granted to members of the group
+	Role string `json:"role"`
+}
+
+// GroupRoleMappingList defines model for GroupRoleMappingList.
+type GroupRoleMappingList struct {
+	// RoleMappings Role mappings for the issuer
+	RoleMappings []GroupRoleMapping `json:"role_mappings"`
+}
+
 // Issuer defines model for Issuer.
 type Issuer struct {
+	// ClaimMappingOutputs Declared outputs of claim mappings, keyed by claim
+	ClaimMappingOutputs *map[string]ClaimMappingOutput `json:"claim_mapping_outputs,omitempty"`
+
 	// ClaimMappings CEL expressions mapping token claims to other claims
 	ClaimMappings map[string]string `json:"claim_mappings"`
 
 	// Id ID of the issuer
 	ID openapi_types.UUID `json:"id"`
 
-	// JwksUri JWKS URI
-	JWKSURI string `json:"jwks_uri"`
+	// IntrospectionEndpoint Token introspection endpoint. If omitted, the introspection_endpoint from the issuer's OIDC discovery metadata is used
+	IntrospectionEndpoint *string `json:"introspection_endpoint,omitempty"`
+
+	// Jwks Inline JWKS used to verify the issuer's tokens in place of fetching its JWKS URI
+	JWKS *map[string]interface{} `json:"jwks,omitempty"`
+
+	// JwksUri JWKS URI. If omitted, the jwks_uri from the issuer's OIDC discovery metadata is used
+	JWKSURI *string `json:"jwks_uri,omitempty"`
 
 	// Name A human-readable name for the issuer
 	Name string `json:"name"`
 
 	// Uri URI for the issuer. Must match the "iss" claim value in incoming JWTs
 	URI string `json:"uri"`
+
+	// UserinfoEndpoint UserInfo endpoint. If omitted, the userinfo_endpoint from the issuer's OIDC discovery metadata is used
+	UserInfoEndpoint *string `json:"userinfo_endpoint,omitempty"`
+}
+
+// IssuerList defines model for IssuerList.
+type IssuerList struct {
+	// Issuers Issuers of the tenant
+	Issuers []Issuer `json:"issuers"`
+
+	// NextCursor Cursor for the next page of issuers. Omitted on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
 
 // IssuerUpdate defines model for IssuerUpdate.
 type IssuerUpdate struct {
+	// ClaimMappingOutputs Declared outputs of claim mappings, keyed by claim
+	ClaimMappingOutputs *map[string]ClaimMappingOutput `json:"claim_mapping_outputs,omitempty"`
+
 	// ClaimMappings CEL expressions mapping token claims to other claims
 	ClaimMappings *map[string]string `json:"claim_mappings,omitempty"`
 
-	// JwksUri JWKS URI
+	// IntrospectionEndpoint Token introspection endpoint. If omitted, the introspection_endpoint from the issuer's OIDC discovery metadata is used
+	IntrospectionEndpoint *string `json:"introspection_endpoint,omitempty"`
+
+	// Jwks Inline JWKS used to verify the issuer's tokens in place of fetching its JWKS URI. Cannot be combined with jwks_uri. An empty object removes the inline JWKS
+	JWKS *map[string]interface{} `json:"jwks,omitempty"`
+
+	// JwksUri JWKS URI. If omitted, the jwks_uri from the issuer's OIDC discovery metadata is used
 	JWKSURI *string `json:"jwks_uri,omitempty"`
 
 	// Name A human-readable name for the issuer
@@ -68,31 +275,174 @@ type IssuerUpdate struct {
 
 	// Uri URI for the issuer. Must match the "iss" claim value in incoming JWTs
 	URI *string `json:"uri,omitempty"`
+
+	// UserinfoEndpoint UserInfo endpoint. If omitted, the userinfo_endpoint from the issuer's OIDC discovery metadata is used
+	UserInfoEndpoint *string `json:"userinfo_endpoint,omitempty"`
 }
 
-// UpdateIssuerJSONRequestBody defines body for UpdateIssuer for application/json ContentType.
-type UpdateIssuerJSONRequestBody = IssuerUpdate
+// SampleUserInfo defines model for SampleUserInfo.
+type SampleUserInfo struct {
+	// Email Sample user email
+	Email *string `json:"email,omitempty"`
+
+	// Name Sample user name
+	Name *string `json:"name,omitempty"`
+}
+
+// SigningKey defines model for SigningKey.
+type SigningKey struct {
+	// ActivatedAt When the key was last promoted to sign tokens
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+
+	// Algorithm JWT signing algorithm of the key
+	Algorithm string `json:"algorithm"`
+
+	// CreatedAt When the key was generated
+	CreatedAt time.Time `json:"created_at"`
+
+	// Id Key ID of the signing key
+	ID string `json:"id"`
+
+	// PublicKey Public key as a JWK
+	PublicKey map[string]interface{} `json:"public_key"`
+
+	// State Rotation state of the key (pending, active, retiring or retired)
+	State string `json:"state"`
+}
+
+// SigningKeyList defines model for SigningKeyList.
+type SigningKeyList struct {
+	// SigningKeys Signing keys, oldest first
+	SigningKeys []SigningKey `json:"signing_keys"`
+}
+
+// Forbidden A generic error response
+type Forbidden = ErrorResponse
+
+// Unauthorized A generic error response
+type Unauthorized = ErrorResponse
+
+// ListGlobalAuditEventsParams defines parameters for ListGlobalAuditEvents.
+type ListGlobalAuditEventsParams struct {
+	// ResourceId Only list events for the resource with the given ID
+	ResourceId *string `form:"resource_id,omitempty" json:"resource_id,omitempty"`
+
+	// Limit Maximum number of audit events to list. Defaults to 50
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor returned with the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListAuditEventsParams defines parameters for ListAuditEvents.
+type ListAuditEventsParams struct {
+	// ResourceId Only list events for the resource with the given ID
+	ResourceId *string `form:"resource_id,omitempty" json:"resource_id,omitempty"`
+
+	// Limit Maximum number of audit events to list. Defaults to 50
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor returned with the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListIssuersParams defines parameters for ListIssuers.
+type ListIssuersParams struct {
+	// Name Only list issuers whose name contains the given value, ignoring case
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// Uri Only list the issuer with the given URI
+	Uri *string `form:"uri,omitempty" json:"uri,omitempty"`
+
+	// Sort Field to sort issuers by, prefixed with "-" to sort in descending order. Defaults to name
+	Sort *ListIssuersParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Maximum number of issuers to list. Defaults to 50
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor returned with the previous page. Must be used with the same sort order
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListIssuersParamsSort defines parameters for ListIssuers.
+type ListIssuersParamsSort string
+
+// CreateSigningKeyJSONRequestBody defines body for CreateSigningKey for application/json ContentType.
+type CreateSigningKeyJSONRequestBody = CreateSigningKey
 
 // CreateIssuerJSONRequestBody defines body for CreateIssuer for application/json ContentType.
 type CreateIssuerJSONRequestBody = CreateIssuer
 
+// UpdateIssuerJSONRequestBody defines body for UpdateIssuer for application/json ContentType.
+type UpdateIssuerJSONRequestBody = IssuerUpdate
+
+// EvaluateClaimMappingsJSONRequestBody defines body for EvaluateClaimMappings for application/json ContentType.
+type EvaluateClaimMappingsJSONRequestBody = ClaimMappingEvaluationRequest
+
+// CreateGroupRoleMappingJSONRequestBody defines body for CreateGroupRoleMapping for application/json ContentType.
+type CreateGroupRoleMappingJSONRequestBody = CreateGroupRoleMapping
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXX0/7NhT9KtbdHjYpNP2Nt7wBnVDYkBAt4gEQcpPb1pDYxr4uVFW++2Q7pX8HA+0n",
-	"UYk3N7Gvzz3n+DidQ6FqrSRKspDNwRYTrHkYnhjkhLm1Do3/rY3SaEhgeFtUXNT3NddayHF4wstSkFCS",
-	"VxdrM2mmETKwZIQcQ5NAibYwQvu5kMHJn38zfNEGrRVKWtaWZKQeUbKwjWWkmKIJmvY3JIuqaviABfmq",
-	"D8+P9t4Z4bdc3+Hs+q8+u7rMl6taLAm8HIzVgeQ1ttP8rCaB+GSzzhGbuJrLA4O85MMKmZ/GRsowmiAT",
-	"kahku9+doK4u842lHXbuLLGaUzEJj29BWHsLsWc25ZVDJiQTslC1Z+jsemDf6Sn00yRg8MkJgyVkN7G5",
-	"iGqFtbsmgR5WSHiJVitpcVtz64oCrd3BTPXMZ5aRcdhZIhoqVSGXWwAWZfyWe2MvUW63nfeYGq2LP1Km",
-	"5gQZOCfKd8TJe9++/YBvA5+7zZts+mVprStdcsLv/NpnHzTeCkKO1DaMPhbOCJqxQaC7j2YqCmS/9Qf9",
-	"39k5l3yMNUpiRxc5E5ZxGUYeeO1fehj9QZ8VSo7E2Bnuy9qQYYIq/PcN1ktDAlM0NkLqdrqdH543pVFy",
-	"LSCDw063cwgJaE6T4KmUa5FOf6SROZvORdnE5nwC+5F3YECTl5C1yZwvJNLc8BoJjYXsZncqxcreeG1N",
-	"zyBkAcLiHGXxTC0PmQ/wpP0G8CDeTrOmufOL420R2vqj2w1nS0lCSeFQaV2JIjSSPliPb75S/1eDI8jg",
-	"l3T5EZK2XyDpxmUUPLChfbxHRq5iZjktAevqmpvZK21B9paPZ0HRnGMxRcnyXtCa+wS4aRMjpscYaVuG",
-	"U6Q453iW9z6qg6+4byK0jvsU+adIq8wPZ2+wrX1obPMdw/tztncx+H8a408OLR2rcvY/k91eWM369ech",
-	"Nl9U6Ih4RevdKjfJa+wRSu53n8dB3msWSRguamV3nL21vyP/yQuxuPdCEdYuzCHkblcswHxFb6x1v0fe",
-	"iLjf90bT/BMAAP//8WoYH4oOAAA=",
+	"H4sIAAAAAAAC/+xcW2/buPL/KoT+f6C7gGyn23Ne8tZtsoXb7bZIUvQAbRDQ0tjmRiK1JJVUJ/B3Pxhe",
+	"dKXtOHWyySJPcSxyOJz5zYXDkW+iROSF4MC1ig5vIgmqEFyB+ec3IWcsTYHjP4ngGrjGj7QoMpZQzQSf",
+	"/KmEeaySJeQUP/2/hHl0GP3fpKE8sU/V5FhKIU/cGtFqtYqjFFQiWYHEosPobAlkBlSCJFpcAicZTS4V",
+	"0UsgogBp1nyhiEpEATER0jxJaJaBJEwRLjShWSauISVakALkXMi8Oz1axdFnTku9FJL9F9KH3ZyEv0pQ",
+	"miwpckuuaMbSzo4jnOdI4oqvy5Tp4yvHXSFxH5pZBdHEUr7pLfTR79VLAFIiuBGDBCVKmUAUR8DLPDr8",
+	"GiUSqMYvyiK1H1LIQEN0Hke6KiA6jJSWjC9QcjTRQg5XPC1nf0KiiZibVazu8FOypHwB5JoqktMUyDXT",
+	"yzE5zgtdETYnOeV0ATlwTV5/mhLUCnDt5I8aTZmiswzSKMTLXIPhhaYpwwk0+9SSj5Yl9FXwG4MsVZ5N",
+	"LwxiKLX4HZOPOdMaUjIXkhhxMMFVTChHSWYVYTzJyhSUm5CSuaWM460cVcOyMMJBlmcwFxL2wrMltZZp",
+	"o9X9MG0Bkl5QPVT8l2VY0VEcIfBwSoSER5rlEFIiS4dEp0d+txTRT8DAv0WxLNkQEXH0fbQQI05zMDSQ",
+	"ujO3i82ruFFBwDIek7kUOWFakf+MTuzQ0fSILIGmILew4cZ7bqzutrDjtdOy1c1L2GG9NeyU/ipnVQHr",
+	"1iE/MaVKkDGRIoOLnBYF4wv0sootOOOLi0uofg4pUQOnfJuU7aDwyjPIBF8ookUXxX6Ade3G7RvPTh21",
+	"mKgyWRKqPIvkEiq1I1TODCkUn0MMkxgWvkZmpvV4sXe2fQl3tdpBXMdyzldxy5f/zlTIn+PzC4N2NZTk",
+	"68YWVFemMeFwjQCeM6l0FEdMQ662xauGG6NCKyAqJa3wfw7f9UVSShVy92/M90ZDyAWOJQVdGGS1TFY1",
+	"ynThJ6PKjhyiqCf7jixQeG8yyvIPFpPHVzQrqQ9+XSEmOE6td7E3g7hsKGOSQTW5FmWGAZkYU0gJ44Ry",
+	"QpMElHJhze96wa6AE8aLUoe8Jkgp5CY+hmbUZcvkE2Y1oMmSmH0Rb5SG2Tllmct0qFSAlgpWMhDiCOdC",
+	"erG7gD6WuihNcF/HiV82RXtEWc3LLKuGTPSU3OWollnslbhe786vhm0IeBJwfac0LzIgfoD3+lbJqFD4",
+	"bv1S24CGrq5nJ4ZT7ysvhJHURq1vMsn2Xq3Qh7B4Q3nKMKSSFJKMSjQuuyoqqKMbFaM7hJTMKvtgTE6g",
+	"yCj6U710GJcv1JASm5NCiiuWQjpUYm/TP4LxZjNvjn8n8L2QoBQTHG2tBhXqx3DtvV7NdyL4nC1K5Ly7",
+	"8bVM74R7hxjlkltr/pbMmBx/p4nOKiI41IJXJlja4Rd2eF4qjf5kszgZrAmfjoVWcmDG+sTeGmAHupsi",
+	"3RszuZMaQXphwqpau7Z9vCdz6Qhn/ZIdib/7cjYmeHjCGE91KcGf9a5AsjmDdA/aaFguFUjG52KbsVpe",
+	"PyuQUxxtDnwBAx54KJEzfcHLLAsc3HLmEiSDZjZv/eP9rbcKYyKGTL2HmRAZUN7IPZj9DUkaqRRSpGUC",
+	"YzKdE2GjdmzGWp9AkKKXe7KE5BLS1iGyhhvyEMURMwl7xpT+Vh4cvErsc/MZIhOKAsdLI0GTMr2VoixO",
+	"RAZOlkMpLnDEcIdmIh6rSyBUEaYJhhgqlUdtWSgtgeaNEzGUlBVJCA+YDA8X+oPmtT/CEWQhKdc2GueQ",
+	"z0DWSZpldVvC40eZ5c5rSUwNm2vSnIeMO0c7R5v7jRy9eFGnIy0njboQegmS1BnGgCHGtRSqAJPdXwBP",
+	"C8F44KB7Zuh2RhM/emgzYar2INmJYB+nR29IylQirkBWJAdNU6opGlqpwv7pz+tLtVsFYcozxoG8+/L+",
+	"1FBFuRjXWXWZMbJTnXA7B50sUa5MK0vg88l0TN5Qjn5gBiQR+YxxSE1lhyBvF6VkA0l34xAS8lsxwwfi",
+	"bpbqi9bP2YMwh0x9PpkiX/abwQGMLMuc8pEEmmJRiuCw+jhguQgGlNAGP59Me1PH5AP64ZzqZGm+/hYx",
+	"pb5FzsqsT2OIwUSYwP/uy5nasie3Hx/SNuDbx7ENoB5Q2bsKPBPHns2+kzTjrEQbF3lqj/7voQocB7KF",
+	"kEwv8xDEzuqqQT3M++xLqIYCaKWbKcxpmenWxLUWi3s4MhXVujg8YNOdmgKYy65ppQha9TgQ6Hvi8WRQ",
+	"Nt1y9JAwWQAHyRJiDl3EV/6juMdac47tEvgAStEFKGK/nqEYr5dVp6BmD6g7JIi9/bi1cTuPKSPYUk3E",
+	"bMCFozsVLS0fW6ppdtBgwVYlbde1DUHLwQOlPIanZrfxMAfqaz1cN2uXKwNIPWnJRw399a2KZQP4bYNu",
+	"lyfcy3Mmd8+Z3C3s5W72+Jwi7pwiPieAzwngfSSAxmRbWeDADzWeNhwrLKOBKGEn9W5Wbhse7OS93qM4",
+	"Rn/gCsVvtRHJZ3vD/xyCnosJT6iYMCavOQHTMmJFTSTk4spfYjT8PAed56Czn6CziqNeeX/gMyGnLFt7",
+	"g4FcEzskoJuwpttTXYgLlBI2VjsSza629+pcQmUaXGwokSIX7hSn2II7w711A88PVFhC5G7VbOQ3YEoY",
+	"OPyH2o3eQ9W6YGs1ktzqdFCUs4wl2Bqzmx/9ZOaZrVBFKDqwUEhR2kXs/plW2x4587wlU/JTATxlfBET",
+	"gwaIiQTNpOvkMZ8h/fl2Z/NGt56Rzn4HDS4NNsPJV6uPKHTf2EhexURk6c4tLc36W8/nHVbO8amCpJRM",
+	"V6dIzPJrGzNfl3rZ/Pebx9m7L2dRX6mve70irotkVhGWAtdMVyNasNoFd9sfX6i6OSEmCZWy8te7g97X",
+	"yPWImmKgYatR6FLrwroKf4vZE7PbJ7FZyCnIK4bNX6dnpz+TDx2OCFOEcvMJWTbsIk+nZ6d1MdTwpUxx",
+	"kukM1i/QJR3F0RVIZVk6GB+MX6LKRAGcFiw6jF6ND8avEG5UL40uJrRgk6uXExSRHjVNUgswQKtFNE2j",
+	"wwjh9zYTM5o1XU7KUJM0B20OAF8Ht6/YI5kxVTdZeTXVbWomLWl6f6ZH5qYzOoz+KkFW/mRy2GsJa9qH",
+	"ByY3KKrS7ywvc8JLLKf126nQRSN/Y3Jky8/mm38frOEiYznTnfVzSz46/OXgII5yxu1/L2vsMK5hATLE",
+	"mjurSNClrFM0lEUh4YqJUvlDSYgVdwDaJIvzuNt9/svBwd5as3uNd4He7NO6bamphK/i6F8HL9fRrpmd",
+	"dLrJzaRX2yc1zfXG95R5TmXlkKv6va/mfHS3Xshub6AxU4pnpa+un/DYd9it4trE3PyRd9NrTazxtyq6",
+	"R/X1wsrjV59aG8naCvDx7r0NQXFUCBUQ9OCSq+7d+VWk1d6EPFhm1Q2ZWpawehAlP2oFv3UppyIULaut",
+	"6jH5A67xgyJUAqnzMCXQniv7JWZPaglpq3+/aufdGwCyxkInN5csXU1cHm+SriCOPtkBHSBtjIc2J25t",
+	"0HSaumWcn8fw3Lj5y7oFusHM3+XznwienFYQTg4wdZqOn3vSbwOFnLWCb1a5dN+MZMon+vaw7KnMSk2U",
+	"xgvlGod3xptdYD3cTszzH0SbW+QZbPsBm9UJYq0l59iW05ipyzXFFSxMWYw5YLVVk9QlO4ez28HIJipq",
+	"cqPdKxer26fzOyTy7jDv3jexOXM3nZoLGQaVZ2wjsjZfIQZy5+eTxfPJ4pGkpv608EJ1AHCXY0LAmlu3",
+	"a2sN2V203cmIHf2/z349A9dLoVwpHnFEGVct8zVV85iwBRdIkiRUrcOv+bOTJTfMtPqAev7D3sCH1rOX",
+	"pjssZ14/NbmHkM3+Z1WM5jln373BfotG36JmHDd9YXVGk4Ls+ha37xCLSKDDo28yd3NGnfvfEf45j+/i",
+	"Af1mHr/zc7c0M7BXbvUghQA0Ajcifpw+snUd/6T8o7+BbznDaetKfdNpfeqv5XZycbaa7k2a8Xt0cef3",
+	"WURwu3/gAkJ71UcKMSseU1N3t69BbN0qxk5uWLqy1X3zaw0DJNqe47sgsQkrnW7SBwu3lhvHghbE7TDI",
+	"AUt/aO379Hu9pu/HDEzLaguYwwPJOjcYzPPegkvzfq2mR08bfLi/p4a8J+AK34Juw21WbYBYgc0pQ5DZ",
+	"brZ/goOrf3nnnmC2/0Df6Sd8DvR9dFvB7DHQT0w71sj3Ok7q33lYW391v5UA7XZM9bTNxG+61yq6vhrw",
+	"OG1n8y9aPLAxhZl51MZ1XL8BP8RBK6TQBWVcaaJsW50Zq0xeI8rODyhQ16zjf9Dgh21VigxG7bbk9T0q",
+	"vdeKnriBmqqU7L9u9eTSp+BbZ0+jacS8QYeqGGghHItwl+RD++WJTWWNvmCeNlpduWXwCuVTCydh1Txw",
+	"HAmv/9jrMI3O8dqT8uZl4NqQqAXHrS3ornFicuM+TY9uUdb5J1nixleY92+Ga7jpsLCl6FRr6rn2tMfa",
+	"Uzh4ba5EDayv1chtzKDdwv31fHW++t8AlZz8LPNYAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file